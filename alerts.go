@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/infrastructure/alert"
+	"ethClassify/internal/usecase"
+	"ethClassify/utils"
+)
+
+type alertConfig struct {
+	Webhooks []struct {
+		Name   string `json:"name"`
+		URL    string `json:"url"`
+		Format string `json:"format"`
+	} `json:"webhooks"`
+	Rules []struct {
+		Name          string   `json:"name"`
+		Types         []string `json:"types"`
+		Addresses     []string `json:"addresses"`
+		FromAddresses []string `json:"fromAddresses"`
+		Labels        []string `json:"labels"`
+		FromLabels    []string `json:"fromLabels"`
+		MinValueWei   string   `json:"minValueWei"`
+		MinValueEth   string   `json:"minValueEth"`
+		FlashLoan     bool     `json:"flashLoan"`
+		Webhooks      []string `json:"webhooks"`
+	} `json:"rules"`
+}
+
+func loadAlertConfig(path string) ([]usecase.AlertRule, []alert.Webhook, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read alert config: %w", err)
+	}
+	var cfg alertConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, nil, fmt.Errorf("decode alert config %s: %w", path, err)
+	}
+
+	webhooks := make([]alert.Webhook, 0, len(cfg.Webhooks))
+	known := make(map[string]bool, len(cfg.Webhooks))
+	for _, hook := range cfg.Webhooks {
+		webhooks = append(webhooks, alert.Webhook{Name: hook.Name, URL: hook.URL, Format: hook.Format})
+		known[hook.Name] = true
+	}
+
+	rules := make([]usecase.AlertRule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		if r.Name == "" {
+			return nil, nil, fmt.Errorf("alert rule requires a name")
+		}
+		if len(r.Webhooks) == 0 {
+			return nil, nil, fmt.Errorf("alert rule %s has no webhooks", r.Name)
+		}
+		for _, hook := range r.Webhooks {
+			if !known[hook] {
+				return nil, nil, fmt.Errorf("alert rule %s references unknown webhook %s", r.Name, hook)
+			}
+		}
+
+		match := usecase.TxMatch{
+			Addresses:     r.Addresses,
+			FromAddresses: r.FromAddresses,
+			Labels:        r.Labels,
+			FromLabels:    r.FromLabels,
+			FlashLoan:     r.FlashLoan,
+		}
+		for _, t := range r.Types {
+			match.Types = append(match.Types, domain.ClassificationType(strings.ToUpper(t)))
+		}
+		switch {
+		case r.MinValueWei != "" && r.MinValueEth != "":
+			return nil, nil, fmt.Errorf("alert rule %s sets both minValueWei and minValueEth", r.Name)
+		case r.MinValueWei != "":
+			v, ok := new(big.Int).SetString(r.MinValueWei, 10)
+			if !ok || v.Sign() < 0 {
+				return nil, nil, fmt.Errorf("alert rule %s: invalid minValueWei %q", r.Name, r.MinValueWei)
+			}
+			match.MinValue = v
+		case r.MinValueEth != "":
+			v, ok := utils.EtherToWei(r.MinValueEth)
+			if !ok {
+				return nil, nil, fmt.Errorf("alert rule %s: invalid minValueEth %q", r.Name, r.MinValueEth)
+			}
+			match.MinValue = v
+		}
+
+		rules = append(rules, usecase.AlertRule{
+			Name:     r.Name,
+			Match:    match,
+			Webhooks: r.Webhooks,
+		})
+	}
+	return rules, webhooks, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"ethClassify/internal/infrastructure/checkpoint"
+	"ethClassify/internal/infrastructure/ethereum"
+	"ethClassify/internal/infrastructure/storage/postgres"
+	"ethClassify/internal/infrastructure/storage/sqlite"
+	"ethClassify/internal/interface/cli"
+	"ethClassify/internal/usecase"
+)
+
+func runBackfill(args []string) {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "ethClassify backfill - clasifica un rango historico de bloques")
+		fmt.Fprintf(fs.Output(), "Uso: %s backfill -url <rpc-url> -from <bloque> -to <bloque> [opciones]\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Guarda el ultimo bloque escrito en el archivo de checkpoint y reanuda desde ahi.")
+		fmt.Fprintln(fs.Output(), "\nOpciones:")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nEjemplo:\n  %s backfill -url https://mainnet.infura.io/v3/<project-id> -from 18000000 -to 18010000 -checkpoint backfill.json -format json > out.jsonl\n", os.Args[0])
+	}
+
+	url := fs.String("url", "", "rpc url raw link")
+	chainName := fs.String("chain", "", chainFlagUsage)
+	from := fs.Uint64("from", 0, "first block of the range (inclusive)")
+	to := fs.Uint64("to", 0, "last block of the range (inclusive)")
+	checkpointPath := fs.String("checkpoint", "", "checkpoint file storing the last fully written block")
+	dbPath := fs.String("db", "", "write results into this sqlite database instead of stdout")
+	postgresDSN := fs.String("postgres", "", "write results into this postgres database (DSN) instead of stdout")
+	withLogs := fs.Bool("with-logs", false, "use transaction receipts/logs for ERC-type classification (extra RPC calls)")
+	traceSource := fs.String("trace-source", "", "attach internal call traces: geth (debug_traceBlockByNumber callTracer) or parity (trace_block)")
+	stateDiff := fs.Bool("state-diff", false, "attach the prestateTracer state diff of every transaction (one debug_traceTransaction call per tx)")
+	explain := fs.Bool("explain", false, "record the classifier/resolver decisions for every transaction")
+	format := fs.String("format", "json", "output format: text or json (one block per line)")
+	balances := fs.Bool("balances", false, "print the per-address balance changes of every transaction (text output)")
+	workers := fs.Int("workers", 8, "number of concurrent workers per block for receipt fetching and classification")
+	blockWorkers := fs.Int("block-workers", 4, "number of blocks fetched and classified concurrently")
+	watchPath := fs.String("watch-addresses", "", "file with one watched address per line; only matching txs are written")
+	progressEvery := fs.Duration("progress-every", 10*time.Second, "interval between progress reports on stderr")
+	fs.Parse(args)
+
+	if *url == "" {
+		fmt.Fprintln(fs.Output(), "error: -url is required")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *to < *from {
+		fmt.Fprintln(fs.Output(), "error: -to must be greater than or equal to -from")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *workers < 1 || *blockWorkers < 1 {
+		fmt.Fprintln(fs.Output(), "error: -workers and -block-workers must be at least 1")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *dbPath != "" && *postgresDSN != "" {
+		fmt.Fprintln(fs.Output(), "error: -db and -postgres are mutually exclusive")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(fs.Output(), "error: unknown -format %q\n", *format)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	watchLogs(*watchPath, withLogs)
+	reader, err := ethereum.NewBlockReader(*url, ethereum.ReaderOptions{
+		WithLogs:      *withLogs,
+		Workers:       *workers,
+		TraceSource:   *traceSource,
+		WithStateDiff: *stateDiff,
+	})
+	if err != nil {
+		log.Fatalf("failed to create block reader: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	profile := mustResolveChain(ctx, *chainName, reader)
+
+	uc := usecase.Backfill{
+		Classify: usecase.ClassifyBlock{
+			Reader:   reader,
+			Pipeline: newPipeline(pipelineOptions{Chain: profile, WithLogs: *withLogs, StateDiff: *stateDiff, Explain: *explain}),
+			Workers:  *workers,
+			Watch:    loadWatchlist(*watchPath),
+		},
+		Sink:             cli.Printer{Format: *format, Text: cli.TextOptions{Balances: *balances, NativeSymbol: profile.NativeSymbol}},
+		BlockWorkers:     *blockWorkers,
+		ProgressInterval: *progressEvery,
+		OnProgress: func(p usecase.BackfillProgress) {
+			log.Printf("backfill: block %d, %d done, %d remaining, %.2f blocks/s, elapsed %s, eta %s",
+				p.LastBlock, p.Done, p.Remaining, p.Rate, p.Elapsed.Round(time.Second), p.ETA.Round(time.Second))
+		},
+	}
+	if *checkpointPath != "" {
+		uc.Checkpoint = checkpoint.NewFileStore(*checkpointPath)
+	}
+
+	if *dbPath != "" {
+		store, err := sqlite.Open(ctx, *dbPath)
+		if err != nil {
+			log.Fatalf("failed to open database: %v", err)
+		}
+		defer store.Close()
+		uc.Sink = store
+	}
+	if *postgresDSN != "" {
+		store, err := postgres.Open(ctx, *postgresDSN)
+		if err != nil {
+			log.Fatalf("failed to open postgres: %v", err)
+		}
+		defer store.Close()
+		uc.Sink = store
+	}
+
+	if err := uc.Execute(ctx, *from, *to); err != nil {
+		if errors.Is(err, context.Canceled) {
+			log.Printf("backfill interrupted, resume with the same -checkpoint")
+			return
+		}
+		log.Fatalf("failed to backfill: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"ethClassify/internal/infrastructure/chain"
+)
+
+const chainFlagUsage = "chain profile (ethereum, optimism, polygon, base, arbitrum); empty detects it from eth_chainId"
+
+type chainIDReader interface {
+	ChainID(ctx context.Context) (uint64, error)
+}
+
+// resolveChain returns the profile named by -chain, checked against the
+// node, or the one matching the node's eth_chainId when no name is given.
+// Unknown chain ids fall back to a generic profile without labels.
+func resolveChain(ctx context.Context, name string, node chainIDReader) (chain.Profile, error) {
+	id, err := node.ChainID(ctx)
+	if err != nil {
+		return chain.Profile{}, err
+	}
+	if name == "" {
+		if profile, ok := chain.ByChainID(id); ok {
+			return profile, nil
+		}
+		log.Printf("no profile for chain id %d, using generic labels", id)
+		return chain.Generic(id), nil
+	}
+	profile, ok := chain.ByName(name)
+	if !ok {
+		return chain.Profile{}, fmt.Errorf("unknown chain %q (known: %s)", name, strings.Join(chain.Names(), ", "))
+	}
+	if profile.ChainID != id {
+		return chain.Profile{}, fmt.Errorf("chain %s expects chain id %d but the node reports %d", profile.Name, profile.ChainID, id)
+	}
+	return profile, nil
+}
+
+func mustResolveChain(ctx context.Context, name string, node chainIDReader) chain.Profile {
+	profile, err := resolveChain(ctx, name, node)
+	if err != nil {
+		log.Fatalf("failed to resolve chain: %v", err)
+	}
+	return profile
+}
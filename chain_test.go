@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type staticChainID uint64
+
+func (id staticChainID) ChainID(context.Context) (uint64, error) { return uint64(id), nil }
+
+type failingChainID struct{}
+
+func (failingChainID) ChainID(context.Context) (uint64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestResolveChain(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		node    chainIDReader
+		want    string
+		wantErr string
+	}{
+		{name: "detected from eth_chainId", node: staticChainID(42161), want: "arbitrum"},
+		{name: "flag matching the node", flag: "base", node: staticChainID(8453), want: "base"},
+		{name: "flag is case-insensitive", flag: "Optimism", node: staticChainID(10), want: "optimism"},
+		// The flag wins over detection, so a node on another chain is an
+		// error rather than a silent switch to the node's profile.
+		{name: "flag disagreeing with the node", flag: "polygon", node: staticChainID(1), wantErr: "chain polygon expects chain id 137 but the node reports 1"},
+		{name: "unknown flag", flag: "solana", node: staticChainID(1), wantErr: `unknown chain "solana"`},
+		{name: "unknown chain id", node: staticChainID(31337), want: "chain-31337"},
+		{name: "unknown chain id with a flag", flag: "ethereum", node: staticChainID(31337), wantErr: "node reports 31337"},
+		{name: "node error", node: failingChainID{}, wantErr: "connection refused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := resolveChain(context.Background(), tt.flag, tt.node)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveChain = %v, %v; want error containing %q", profile.Name, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || profile.Name != tt.want {
+				t.Fatalf("resolveChain = %v, %v; want %s", profile.Name, err, tt.want)
+			}
+		})
+	}
+}
@@ -2,21 +2,187 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"math/big"
+	"time"
+)
+
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrInvalidArgument = errors.New("invalid argument")
 )
 
 type Block struct {
-	Number       *big.Int
-	Hash         string
-	Transactions []Tx
+	Number     *big.Int
+	Hash       string
+	ParentHash string
+	Sequencer  *SequencerInfo
+	// BlobGasUsed and ExcessBlobGas are nil before Cancun and on chains
+	// without blobs.
+	BlobGasUsed   *uint64
+	ExcessBlobGas *uint64
+	Transactions  []Tx
+}
+
+// SequencerInfo is the L1 origin an L2 block was built on; nil on L1. OP-stack
+// chains report it in the block's L1 attributes deposit, Arbitrum in the
+// block header (together with the outbox send count and root).
+type SequencerInfo struct {
+	L1BlockNumber  uint64
+	L1BlockHash    string
+	L1BaseFee      *big.Int
+	L1BlobBaseFee  *big.Int
+	SequenceNumber uint64
+	SendCount      uint64
+	SendRoot       string
 }
 
 type Tx struct {
-	Hash  string
-	To    *string
-	Value *big.Int
-	Data  []byte
-	Logs  []Log
+	Hash string
+	// Index is the tx's position in its block; zero for pending txs.
+	Index uint
+	// Type is the EIP-2718 envelope type (0 for legacy txs).
+	Type      uint8
+	From      string
+	To        *string
+	Nonce     uint64
+	Value     *big.Int
+	Data      []byte
+	Logs      []Log
+	Calls     *Call
+	Receipt   *Receipt
+	StateDiff []AccountDiff
+	L2        *L2Tx
+	// BlobHashes are the versioned hashes of an EIP-4844 tx's blobs.
+	BlobHashes       []string
+	MaxFeePerBlobGas *big.Int
+	// Authorizations is the authorization list of an EIP-7702 set-code tx.
+	Authorizations []SetCodeAuthorization
+}
+
+// SetCodeAuthorization is a signed EIP-7702 authorization delegating the
+// code of Authority to Delegate; the zero address as Delegate clears the
+// delegation. Authority is recovered from the signature and empty when it
+// does not recover. ChainID 0 is valid on every chain.
+type SetCodeAuthorization struct {
+	ChainID   *big.Int
+	Delegate  string
+	Nonce     uint64
+	Authority string
+}
+
+const (
+	L2KindOPDeposit          = "OP_DEPOSIT"
+	L2KindArbDeposit         = "ARB_DEPOSIT"
+	L2KindArbUnsigned        = "ARB_UNSIGNED"
+	L2KindArbContract        = "ARB_CONTRACT"
+	L2KindArbRetry           = "ARB_RETRY"
+	L2KindArbSubmitRetryable = "ARB_SUBMIT_RETRYABLE"
+	L2KindArbInternal        = "ARB_INTERNAL"
+)
+
+// L2Tx holds the fields of rollup-specific tx types (OP-stack deposits,
+// Arbitrum L1 messages, retryables and ArbOS internal txs); nil for ordinary
+// signed txs. Only the fields of the given Kind are set. Mint is the ETH
+// created on L2 for the sender before execution.
+type L2Tx struct {
+	Kind             string
+	IsSystem         bool
+	SourceHash       string
+	Mint             *big.Int
+	RequestID        string
+	TicketID         string
+	RefundTo         string
+	Beneficiary      string
+	L1BaseFee        *big.Int
+	DepositValue     *big.Int
+	MaxSubmissionFee *big.Int
+	MaxRefund        *big.Int
+	RetryTo          *string
+	RetryValue       *big.Int
+	RetryData        []byte
+}
+
+// AccountDiff is the state change of one account touched by a tx, as reported
+// by prestateTracer in diff mode. Fields are only meaningful when the matching
+// Changed flag is set; Storage lists only modified slots.
+type AccountDiff struct {
+	Address        string
+	Created        bool
+	Deleted        bool
+	BalanceChanged bool
+	BalanceBefore  *big.Int
+	BalanceAfter   *big.Int
+	NonceChanged   bool
+	NonceBefore    uint64
+	NonceAfter     uint64
+	CodeChanged    bool
+	CodeBefore     []byte
+	CodeAfter      []byte
+	Storage        []SlotDiff
+}
+
+type SlotDiff struct {
+	Slot   string
+	Before string
+	After  string
+}
+
+// Receipt holds the execution outcome; it is only set when the reader fetched
+// receipts.
+type Receipt struct {
+	Success           bool
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+	ContractAddress   string
+	L1Fee             *L1Fee
+	BlobGasUsed       uint64
+	BlobGasPrice      *big.Int
+	// Coinbase and BaseFee come from the including block; the priority fee
+	// (EffectiveGasPrice-BaseFee per gas) is paid to Coinbase. BaseFee is nil
+	// before London, when the whole gas fee goes to Coinbase.
+	Coinbase string
+	BaseFee  *big.Int
+}
+
+// L1Fee is the data-availability cost reported by L2 receipts. On OP-stack
+// chains Fee is charged on top of GasUsed*EffectiveGasPrice; on Arbitrum the
+// L1 cost is already part of GasUsed and only GasUsedForL1 and L1BlockNumber
+// are set.
+type L1Fee struct {
+	Fee               *big.Int
+	GasPrice          *big.Int
+	GasUsed           *big.Int
+	FeeScalar         string
+	BaseFeeScalar     uint64
+	BlobBaseFee       *big.Int
+	BlobBaseFeeScalar uint64
+	GasUsedForL1      uint64
+	L1BlockNumber     uint64
+}
+
+const (
+	CallTypeCall         = "CALL"
+	CallTypeCallCode     = "CALLCODE"
+	CallTypeDelegateCall = "DELEGATECALL"
+	CallTypeStaticCall   = "STATICCALL"
+	CallTypeCreate       = "CREATE"
+	CallTypeCreate2      = "CREATE2"
+	CallTypeSelfDestruct = "SELFDESTRUCT"
+)
+
+// Call is a frame of a tx's call tree. The root frame is the tx itself; it
+// is only present when the reader was configured with a trace source.
+type Call struct {
+	Type    string
+	From    string
+	To      string
+	Value   *big.Int
+	Input   []byte
+	Output  []byte
+	GasUsed uint64
+	Error   string
+	Calls   []Call
 }
 
 type ClassificationType string
@@ -26,7 +192,31 @@ const (
 	ClassificationTransfer             ClassificationType = "TRANSFER"
 	ClassificationContractCall         ClassificationType = "CONTRACT_CALL"
 	ClassificationDexSwap              ClassificationType = "DEX_SWAP"
+	ClassificationDexSwapIntent        ClassificationType = "DEX_SWAP_INTENT"
 	ClassificationSandwichSuspect      ClassificationType = "SANDWICH_SUSPECT"
+	ClassificationSelfDestruct         ClassificationType = "SELF_DESTRUCT"
+	ClassificationBalanceSweep         ClassificationType = "BALANCE_SWEEP"
+	ClassificationProxyUpgrade         ClassificationType = "PROXY_UPGRADE"
+	ClassificationProxyAdminChange     ClassificationType = "PROXY_ADMIN_CHANGE"
+	ClassificationCodeChange           ClassificationType = "CODE_CHANGE"
+	ClassificationUserOpBundle         ClassificationType = "USER_OP_BUNDLE"
+	ClassificationBatchCall            ClassificationType = "BATCH_CALL"
+	ClassificationSafeExec             ClassificationType = "SAFE_EXEC"
+	ClassificationSetCode              ClassificationType = "SET_CODE"
+	ClassificationLendingSupply        ClassificationType = "LENDING_SUPPLY"
+	ClassificationLendingWithdraw      ClassificationType = "LENDING_WITHDRAW"
+	ClassificationLendingBorrow        ClassificationType = "LENDING_BORROW"
+	ClassificationLendingRepay         ClassificationType = "LENDING_REPAY"
+	ClassificationLendingLiquidation   ClassificationType = "LENDING_LIQUIDATION"
+	ClassificationFlashLoan            ClassificationType = "FLASH_LOAN"
+	ClassificationRollupBatch          ClassificationType = "ROLLUP_BATCH"
+	ClassificationBlobTx               ClassificationType = "BLOB_TX"
+	ClassificationBridgeDeposit        ClassificationType = "BRIDGE_DEPOSIT"
+	ClassificationBridgeWithdrawal     ClassificationType = "BRIDGE_WITHDRAWAL"
+	ClassificationL2System             ClassificationType = "L2_SYSTEM"
+	ClassificationL2Deposit            ClassificationType = "L2_DEPOSIT"
+	ClassificationL2RetryableSubmit    ClassificationType = "L2_RETRYABLE_SUBMIT"
+	ClassificationL2RetryableRedeem    ClassificationType = "L2_RETRYABLE_REDEEM"
 	ClassificationERC20Transfer        ClassificationType = "ERC20_TRANSFER"
 	ClassificationERC20Approve         ClassificationType = "ERC20_APPROVE"
 	ClassificationERC20TransferFrom    ClassificationType = "ERC20_TRANSFER_FROM"
@@ -37,17 +227,195 @@ const (
 )
 
 type TxResult struct {
-	Tx       Tx
-	Type     ClassificationType
+	Tx        Tx
+	Type      ClassificationType
+	Selector  string
+	FromLabel string
+	ToLabel   string
+	Swap      *SwapInfo
+	Intent    *SwapIntent
+	Bridge    *BridgeTransfer
+	Lending   []LendingAction
+	Flash     []FlashLoan
+	Batch     *RollupBatch
+	UserOp    *UserOperation
+	Safe      *SafeExecution
+	SetCode   []CodeDelegation
+	Delegated []DelegatedAccount
+	Internal  *InternalActivity
+	Balances  []BalanceDelta
+	Details   string
+	Evidence  *Evidence
+	Watch     []WatchMatch
+	Trace     []TraceStep
+	// Children are the classified inner calls of a wrapper tx, in
+	// execution order; see TxUnwrapper.
+	Children []TxResult
+}
+
+// UserOperation is an ERC-4337 user operation executed by an EntryPoint.
+// Success, ActualGasCost and ActualGasUsed come from its UserOperationEvent
+// and are unset when the tx has no logs.
+type UserOperation struct {
+	EntryPoint    string
+	Version       string
+	Hash          string
+	Sender        string
+	Nonce         *big.Int
+	Paymaster     string
+	Success       *bool
+	ActualGasCost *big.Int
+	ActualGasUsed *big.Int
+}
+
+const (
+	SafeSignatureECDSA        = "ecdsa"
+	SafeSignatureEthSign      = "eth_sign"
+	SafeSignatureApprovedHash = "approved_hash"
+	SafeSignatureExecutor     = "executor"
+	SafeSignatureContract     = "contract"
+)
+
+// SafeExecution is a Safe multisig execTransaction. SafeTxHash and Success
+// come from its ExecutionSuccess/ExecutionFailure event; without it ECDSA
+// signers cannot be recovered and are left empty.
+type SafeExecution struct {
+	Safe       string
+	To         string
+	Value      *big.Int
+	Operation  string
+	MultiSend  bool
+	SafeTxHash string
+	Success    *bool
+	Payment    *big.Int
+	Signatures []SafeSignature
+}
+
+// SafeSignature is one owner's approval: an ECDSA or eth_sign signature, a
+// hash pre-approved on chain (or the executing owner's own approval), or an
+// EIP-1271 contract signature.
+type SafeSignature struct {
+	Signer string
+	Method string
+}
+
+// CodeDelegation is an authorization of a SET_CODE tx as classified.
+// Unknown flags a delegate outside the known account implementations and
+// WrongChain an authorization for another chain, which the EVM skips like
+// one with a stale nonce (only the latter is invisible without state).
+type CodeDelegation struct {
+	Authority    string
+	Delegate     string
+	DelegateName string
+	ChainID      *big.Int
+	Nonce        uint64
+	Revoked      bool
+	Unknown      bool
+	WrongChain   bool
+}
+
+// DelegatedAccount is an EOA seen delegating its code in an earlier SET_CODE
+// tx, as tracked across blocks; Block and TxHash locate that tx.
+type DelegatedAccount struct {
+	Account  string
+	Delegate string
+	Block    uint64
+	TxHash   string
+}
+
+// InnerCall is a call a wrapper tx executes on someone's behalf. Tx holds
+// the call as if it were sent directly (From is the account executing it)
+// with the logs attributable to it.
+type InnerCall struct {
+	Tx     Tx
+	UserOp *UserOperation
+}
+
+type WatchRole string
+
+const (
+	WatchRoleFrom       WatchRole = "from"
+	WatchRoleTo         WatchRole = "to"
+	WatchRoleLogEmitter WatchRole = "log-emitter"
+	WatchRoleTopic      WatchRole = "topic"
+)
+
+type WatchMatch struct {
+	Address string
+	Label   string
+	Role    WatchRole
+}
+
+type TraceOutcome string
+
+const (
+	TraceMatched TraceOutcome = "MATCHED"
+	TraceNoMatch TraceOutcome = "NO_MATCH"
+	TraceSkipped TraceOutcome = "SKIPPED"
+)
+
+type TraceStep struct {
+	Stage    string
+	Name     string
+	Outcome  TraceOutcome
+	Reason   string
+	Evidence *Evidence
+}
+
+type Evidence struct {
+	Rule     string
 	Selector string
-	ToLabel  string
-	Swap     *SwapInfo
-	Details  string
+	Topic    string
+	Address  string
+	Slot     string
+	LogIndex *uint
 }
 
 type BlockResult struct {
 	Block   Block
 	Results []TxResult
+	Blobs   *BlobSummary
+}
+
+// RollupInbox identifies a rollup's batch posts: txs from one of Batchers or
+// sent to Inbox. A SharedInbox is a contract users call too (deposits,
+// messages), so only blob txs and calls to one of BatchSelectors sent to it
+// are batch posts.
+type RollupInbox struct {
+	Name           string
+	Inbox          string
+	Batchers       []string
+	SharedInbox    bool
+	BatchSelectors []string
+}
+
+// RollupBatch is a rollup batch post or, with Rollup empty, a blob tx from an
+// unknown sender. BlobFee is BlobGasUsed*BlobGasPrice, paid on top of gas.
+type RollupBatch struct {
+	Rollup       string
+	Blobs        int
+	BlobGasUsed  uint64
+	BlobGasPrice *big.Int
+	BlobFee      *big.Int
+}
+
+// BlobSummary is the blob usage of a whole block, counted before any
+// watchlist filtering.
+type BlobSummary struct {
+	Txs           int
+	Blobs         int
+	BlobGasUsed   uint64
+	ExcessBlobGas uint64
+	BlobGasPrice  *big.Int
+	BlobFees      *big.Int
+	Rollups       []RollupBlobUsage
+}
+
+type RollupBlobUsage struct {
+	Rollup  string
+	Txs     int
+	Blobs   int
+	BlobFee *big.Int
 }
 
 type SwapInfo struct {
@@ -61,8 +429,322 @@ type SwapInfo struct {
 	Amount1Out *big.Int
 }
 
+// DexDeployment is one DEX instance on a chain. Protocol names the event ABI
+// its pools emit ("uniswap-v2", "uniswap-v3"), so forks sharing the ABI can be
+// told apart by the router or factory a tx went through.
+type DexDeployment struct {
+	Name     string
+	Protocol string
+	Factory  string
+	Routers  []string
+}
+
+const (
+	BridgeProtocolOPStandard      = "op-standard-bridge"
+	BridgeProtocolArbitrumInbox   = "arbitrum-inbox"
+	BridgeProtocolArbitrumGateway = "arbitrum-gateway"
+	BridgeProtocolArbitrumOutbox  = "arbitrum-outbox"
+	BridgeProtocolZkSync          = "zksync"
+	BridgeProtocolPolygonPoS      = "polygon-pos"
+	BridgeProtocolAcross          = "across"
+	BridgeProtocolHop             = "hop"
+)
+
+// BridgeContract is a bridge endpoint on the chain being classified. Protocol
+// selects the event ABI; Chain is the other side of the bridge, used when the
+// events do not carry a chain id.
+type BridgeContract struct {
+	Name     string
+	Protocol string
+	Address  string
+	Chain    string
+}
+
+// BridgeTransfer is funds leaving (deposit) or entering (withdrawal) the
+// chain through a bridge. Chain is the destination of a deposit or the origin
+// of a withdrawal; Token is empty for the native currency.
+type BridgeTransfer struct {
+	Bridge string
+	Chain  string
+	Token  string
+	Amount *big.Int
+	From   string
+	To     string
+}
+
+const (
+	LendingProtocolAaveV2     = "aave-v2"
+	LendingProtocolAaveV3     = "aave-v3"
+	LendingProtocolCompoundV2 = "compound-v2"
+	LendingProtocolCompoundV3 = "compound-v3"
+	LendingProtocolMorphoBlue = "morpho-blue"
+)
+
+// LendingMarket is a lending contract on the chain being classified: an Aave
+// pool (Spark is an Aave V3 fork), a Compound V2 cToken, a Compound V3 Comet
+// or Morpho Blue. Protocol selects the event ABI; Asset is the underlying
+// of a cToken or the base asset of a Comet, empty for the native currency
+// and for pools, whose events name the asset.
+type LendingMarket struct {
+	Name     string
+	Protocol string
+	Address  string
+	Asset    string
+}
+
+const (
+	LendingActionSupply      = "supply"
+	LendingActionWithdraw    = "withdraw"
+	LendingActionBorrow      = "borrow"
+	LendingActionRepay       = "repay"
+	LendingActionLiquidation = "liquidation"
+)
+
+// LendingAction is one lending event. User is the account whose position
+// changes and Caller the one acting for it (repayer, liquidator). Collateral
+// marks supplies and withdrawals of collateral in protocols that keep it
+// apart from the lent asset. Market is the pool or market contract, or the
+// Morpho Blue market id, whose assets the events do not name.
+type LendingAction struct {
+	Protocol    string
+	Market      string
+	Action      string
+	Asset       string
+	Amount      *big.Int
+	User        string
+	Caller      string
+	Collateral  bool
+	Liquidation *Liquidation
+}
+
+// Liquidation is the outcome of a liquidation: the debt the liquidator
+// repaid and the collateral seized from the borrower. Compound V2 seizes
+// cTokens, so CollateralAsset is then the collateral cToken.
+type Liquidation struct {
+	Borrower         string
+	Liquidator       string
+	DebtAsset        string
+	DebtRepaid       *big.Int
+	CollateralAsset  string
+	CollateralSeized *big.Int
+}
+
+// FlashLoan is an asset borrowed and returned within the tx. Asset is
+// empty when the provider's event does not name it (a Uniswap V3 pool token
+// whose transfer was not found); Fee is unset when it does not report it.
+type FlashLoan struct {
+	Provider string
+	Lender   string
+	Borrower string
+	Asset    string
+	Amount   *big.Int
+	Fee      *big.Int
+}
+
+const (
+	FlashProtocolBalancerV2 = "balancer-v2"
+	FlashProtocolDydx       = "dydx"
+	FlashProtocolMakerFlash = "maker-flash"
+)
+
+// FlashLender is a flash loan provider other than the lending markets:
+// the Balancer Vault, dYdX SoloMargin or Maker's DssFlash. Uniswap V3 pools
+// are recognised by their Flash event instead and need no entry.
+type FlashLender struct {
+	Name     string
+	Protocol string
+	Address  string
+}
+
+// SwapIntent is a swap predicted from router calldata, before any Swap event exists.
+type SwapIntent struct {
+	Router       string
+	Method       string
+	Path         []string
+	AmountIn     *big.Int
+	AmountInMax  *big.Int
+	AmountOut    *big.Int
+	AmountOutMin *big.Int
+	Recipient    string
+	Deadline     *big.Int
+}
+
+// InternalActivity summarises the successful internal frames of a tx that
+// move ETH or change which contracts exist.
+type InternalActivity struct {
+	Transfers     []InternalCall
+	SelfDestructs []InternalCall
+	Creates       []InternalCall
+}
+
+const (
+	AssetNative  = "NATIVE"
+	AssetERC20   = "ERC20"
+	AssetERC721  = "ERC721"
+	AssetERC1155 = "ERC1155"
+)
+
+// BalanceDelta is the net change of one asset for one address within a tx.
+// Token is empty for the native currency; TokenID is set for NFTs.
+type BalanceDelta struct {
+	Address  string
+	Standard string
+	Token    string
+	TokenID  *big.Int
+	Delta    *big.Int
+}
+
+type InternalCall struct {
+	Type  string
+	From  string
+	To    string
+	Value *big.Int
+	Depth int
+}
+
+type TokenTransfer struct {
+	LogIndex uint
+	Standard string
+	Token    string
+	From     string
+	To       string
+	Amount   *big.Int
+	TokenID  *big.Int
+}
+
+type TxFilter struct {
+	Type      ClassificationType
+	Address   string
+	FromBlock *uint64
+	ToBlock   *uint64
+	Limit     int
+}
+
+type AlertEvent struct {
+	Rule        string
+	Webhook     string
+	BlockNumber uint64
+	BlockHash   string
+	Result      TxResult
+}
+
+type PendingAlert struct {
+	ID       int64
+	Event    AlertEvent
+	Attempts int
+}
+
+type MempoolEventKind string
+
+const (
+	MempoolPending          MempoolEventKind = "PENDING"
+	MempoolIncluded         MempoolEventKind = "INCLUDED"
+	MempoolReplaced         MempoolEventKind = "REPLACED"
+	MempoolDropped          MempoolEventKind = "DROPPED"
+	MempoolPrivateInclusion MempoolEventKind = "PRIVATE_INCLUSION"
+)
+
+// MempoolEvent reports a pending tx and, later, how it left the mempool.
+// TimeInMempool is set once the tx is included, replaced or dropped.
+type MempoolEvent struct {
+	Kind          MempoolEventKind
+	Result        TxResult
+	SeenAt        time.Time
+	TimeInMempool time.Duration
+	BlockNumber   *big.Int
+	BlockHash     string
+	ReplacedBy    string
+}
+
+// CallRequest is a tx to simulate; it may be unsigned or never broadcast.
+// Hash is only set when the call replays a known tx.
+type CallRequest struct {
+	Hash  string
+	From  string
+	To    *string
+	Value *big.Int
+	Data  []byte
+	Gas   uint64
+}
+
+// Simulation is the outcome of executing a CallRequest against a block's
+// state. Tx carries the logs collected during execution.
+type Simulation struct {
+	Tx          Tx
+	Block       string
+	Tracer      string
+	Success     bool
+	Error       string
+	GasUsed     uint64
+	ReturnData  []byte
+	LogsTracked bool
+}
+
+type SimulationResult struct {
+	Simulation Simulation
+	Result     TxResult
+}
+
 type BlockReader interface {
 	LatestBlock(ctx context.Context) (Block, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (Block, error)
+}
+
+type HeadReader interface {
+	HeadNumber(ctx context.Context) (uint64, error)
+}
+
+type FinalityReader interface {
+	FinalizedNumber(ctx context.Context) (uint64, error)
+}
+
+type BlockResultCache interface {
+	Get(number uint64) (BlockResult, bool)
+	Add(number uint64, result BlockResult)
+}
+
+type TxReader interface {
+	TransactionByHash(ctx context.Context, hash string) (Tx, error)
+}
+
+type TxSimulator interface {
+	Simulate(ctx context.Context, call CallRequest, block *big.Int) (Simulation, error)
+}
+
+type TxCallReader interface {
+	TransactionCall(ctx context.Context, hash string) (CallRequest, error)
+}
+
+type ResultSink interface {
+	Write(ctx context.Context, result BlockResult) error
+}
+
+type PendingSource interface {
+	SubscribePending(ctx context.Context, out chan<- Tx) error
+}
+
+type MempoolSink interface {
+	Publish(ctx context.Context, event MempoolEvent) error
+}
+
+type ResultStore interface {
+	Query(ctx context.Context, filter TxFilter) ([]BlockResult, error)
+}
+
+type CheckpointStore interface {
+	Load() (uint64, bool, error)
+	Save(block uint64) error
+}
+
+type AlertOutbox interface {
+	Enqueue(ctx context.Context, event AlertEvent) (bool, error)
+	Due(ctx context.Context, now time.Time, limit int) ([]PendingAlert, error)
+	MarkDelivered(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, attempts int, next time.Time, reason string, dead bool) error
+}
+
+type AlertNotifier interface {
+	Notify(ctx context.Context, event AlertEvent) error
 }
 
 type AddressLabeler interface {
@@ -77,7 +759,27 @@ type TxLogResolver interface {
 	Resolve(ctx context.Context, tx Tx, current TxResult) (TxResult, bool, error)
 }
 
+// TxStateResolver refines a classification from the tx's state diff; it
+// sees changes that emit no events.
+type TxStateResolver interface {
+	ResolveState(ctx context.Context, tx Tx, current TxResult) (TxResult, bool, error)
+}
+
+// TxUnwrapper splits a wrapper tx (an ERC-4337 bundle, a smart account
+// batch) into the calls it executes. The pipeline classifies each inner call
+// like a tx of its own and attaches the results as children.
+type TxUnwrapper interface {
+	Unwrap(ctx context.Context, tx Tx, current TxResult) (TxResult, []InnerCall, bool, error)
+}
+
+// TxEnricher adds information to a classified tx without changing its type.
+// Every enricher runs, regardless of earlier matches.
+type TxEnricher interface {
+	Enrich(ctx context.Context, tx Tx, current TxResult) (TxResult, bool, error)
+}
+
 type Log struct {
+	Index   uint
 	Address string
 	Topics  []string
 	Data    []byte
@@ -0,0 +1,98 @@
+package alert
+
+import (
+	"fmt"
+	"math/big"
+
+	"ethClassify/internal/domain"
+	"ethClassify/utils"
+)
+
+type Message struct {
+	Rule        string  `json:"rule"`
+	BlockNumber uint64  `json:"blockNumber"`
+	BlockHash   string  `json:"blockHash"`
+	TxHash      string  `json:"txHash"`
+	From        string  `json:"from"`
+	FromLabel   string  `json:"fromLabel,omitempty"`
+	To          *string `json:"to"`
+	ToLabel     string  `json:"toLabel,omitempty"`
+	Value       string  `json:"value"`
+	Type        string  `json:"type"`
+	Selector    string  `json:"selector,omitempty"`
+	Details     string  `json:"details,omitempty"`
+}
+
+func NewMessage(event domain.AlertEvent) Message {
+	res := event.Result
+	value := "0"
+	if res.Tx.Value != nil {
+		value = res.Tx.Value.String()
+	}
+	return Message{
+		Rule:        event.Rule,
+		BlockNumber: event.BlockNumber,
+		BlockHash:   event.BlockHash,
+		TxHash:      res.Tx.Hash,
+		From:        res.Tx.From,
+		FromLabel:   res.FromLabel,
+		To:          res.Tx.To,
+		ToLabel:     res.ToLabel,
+		Value:       value,
+		Type:        string(res.Type),
+		Selector:    res.Selector,
+		Details:     res.Details,
+	}
+}
+
+func (m Message) Event(webhook string) domain.AlertEvent {
+	value, ok := new(big.Int).SetString(m.Value, 10)
+	if !ok {
+		value = big.NewInt(0)
+	}
+	return domain.AlertEvent{
+		Rule:        m.Rule,
+		Webhook:     webhook,
+		BlockNumber: m.BlockNumber,
+		BlockHash:   m.BlockHash,
+		Result: domain.TxResult{
+			Tx: domain.Tx{
+				Hash:  m.TxHash,
+				From:  m.From,
+				To:    m.To,
+				Value: value,
+			},
+			Type:      domain.ClassificationType(m.Type),
+			Selector:  m.Selector,
+			FromLabel: m.FromLabel,
+			ToLabel:   m.ToLabel,
+			Details:   m.Details,
+		},
+	}
+}
+
+// Summary renders the message as chat text, valuing it in the given native
+// currency symbol.
+func (m Message) Summary(nativeSymbol string) string {
+	to := "CONTRACT_CREATION"
+	if m.To != nil {
+		to = withLabel(*m.To, m.ToLabel)
+	}
+	value, ok := new(big.Int).SetString(m.Value, 10)
+	if !ok {
+		value = big.NewInt(0)
+	}
+	text := fmt.Sprintf("[%s] %s in block %d\ntx %s\nfrom %s to %s value %s",
+		m.Rule, m.Type, m.BlockNumber, m.TxHash, withLabel(m.From, m.FromLabel), to, utils.WeiToNativeString(value, nativeSymbol))
+	if m.Details != "" {
+		text += "\n" + m.Details
+	}
+	return text
+}
+
+func withLabel(addr, label string) string {
+	if label == "" {
+		return addr
+	}
+	return fmt.Sprintf("%s (%s)", addr, label)
+}
@@ -0,0 +1,133 @@
+package alert
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"ethClassify/internal/domain"
+
+	_ "modernc.org/sqlite"
+)
+
+const outboxSchema = `CREATE TABLE IF NOT EXISTS alert_outbox (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	dedupe_key      TEXT    NOT NULL UNIQUE,
+	rule            TEXT    NOT NULL,
+	webhook         TEXT    NOT NULL,
+	tx_hash         TEXT    NOT NULL,
+	payload         TEXT    NOT NULL,
+	attempts        INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at INTEGER NOT NULL,
+	delivered_at    INTEGER,
+	dead            INTEGER NOT NULL DEFAULT 0,
+	last_error      TEXT    NOT NULL DEFAULT '',
+	created_at      INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS alert_outbox_due_idx ON alert_outbox (next_attempt_at) WHERE delivered_at IS NULL AND dead = 0;`
+
+type Outbox struct {
+	db *sql.DB
+}
+
+func OpenOutbox(ctx context.Context, dsn string) (*Outbox, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open alert outbox %s: %w", dsn, err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, outboxSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create alert outbox: %w", err)
+	}
+	return &Outbox{db: db}, nil
+}
+
+func (o *Outbox) Close() error {
+	return o.db.Close()
+}
+
+func (o *Outbox) Enqueue(ctx context.Context, event domain.AlertEvent) (bool, error) {
+	payload, err := json.Marshal(NewMessage(event))
+	if err != nil {
+		return false, fmt.Errorf("encode alert: %w", err)
+	}
+	txHash := strings.ToLower(event.Result.Tx.Hash)
+	key := strings.Join([]string{event.Rule, event.Webhook, txHash}, "|")
+	now := time.Now().UnixMilli()
+
+	res, err := o.db.ExecContext(ctx,
+		`INSERT INTO alert_outbox (dedupe_key, rule, webhook, tx_hash, payload, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?) ON CONFLICT (dedupe_key) DO NOTHING`,
+		key, event.Rule, event.Webhook, txHash, string(payload), now, now,
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert alert: %w", err)
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("insert alert: %w", err)
+	}
+	return inserted > 0, nil
+}
+
+func (o *Outbox) Due(ctx context.Context, now time.Time, limit int) ([]domain.PendingAlert, error) {
+	rows, err := o.db.QueryContext(ctx,
+		`SELECT id, webhook, payload, attempts FROM alert_outbox
+		WHERE delivered_at IS NULL AND dead = 0 AND next_attempt_at <= ?
+		ORDER BY id LIMIT ?`,
+		now.UnixMilli(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query due alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.PendingAlert
+	for rows.Next() {
+		var (
+			id       int64
+			webhook  string
+			payload  string
+			attempts int
+		)
+		if err := rows.Scan(&id, &webhook, &payload, &attempts); err != nil {
+			return nil, fmt.Errorf("scan alert: %w", err)
+		}
+		var msg Message
+		if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+			return nil, fmt.Errorf("decode alert %d: %w", id, err)
+		}
+		out = append(out, domain.PendingAlert{
+			ID:       id,
+			Event:    msg.Event(webhook),
+			Attempts: attempts,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate alerts: %w", err)
+	}
+	return out, nil
+}
+
+func (o *Outbox) MarkDelivered(ctx context.Context, id int64) error {
+	if _, err := o.db.ExecContext(ctx, "UPDATE alert_outbox SET delivered_at = ? WHERE id = ?", time.Now().UnixMilli(), id); err != nil {
+		return fmt.Errorf("mark alert %d delivered: %w", id, err)
+	}
+	return nil
+}
+
+func (o *Outbox) MarkFailed(ctx context.Context, id int64, attempts int, next time.Time, reason string, dead bool) error {
+	if _, err := o.db.ExecContext(ctx,
+		"UPDATE alert_outbox SET attempts = ?, next_attempt_at = ?, last_error = ?, dead = ? WHERE id = ?",
+		attempts, next.UnixMilli(), reason, dead, id,
+	); err != nil {
+		return fmt.Errorf("mark alert %d failed: %w", id, err)
+	}
+	return nil
+}
+
+var _ domain.AlertOutbox = (*Outbox)(nil)
@@ -0,0 +1,114 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"unicode/utf8"
+
+	"ethClassify/internal/domain"
+)
+
+const (
+	FormatGeneric = "generic"
+	FormatSlack   = "slack"
+	FormatDiscord = "discord"
+
+	discordContentLimit = 2000
+)
+
+type Webhook struct {
+	Name   string
+	URL    string
+	Format string
+}
+
+type WebhookNotifier struct {
+	client       *http.Client
+	webhooks     map[string]Webhook
+	nativeSymbol string
+}
+
+func NewWebhookNotifier(client *http.Client, webhooks []Webhook, nativeSymbol string) (*WebhookNotifier, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	byName := make(map[string]Webhook, len(webhooks))
+	for _, hook := range webhooks {
+		if hook.Name == "" || hook.URL == "" {
+			return nil, fmt.Errorf("webhook requires name and url")
+		}
+		switch hook.Format {
+		case "":
+			hook.Format = FormatGeneric
+		case FormatGeneric, FormatSlack, FormatDiscord:
+		default:
+			return nil, fmt.Errorf("webhook %s: unknown format %q", hook.Name, hook.Format)
+		}
+		if _, dup := byName[hook.Name]; dup {
+			return nil, fmt.Errorf("duplicate webhook %s", hook.Name)
+		}
+		byName[hook.Name] = hook
+	}
+	return &WebhookNotifier{client: client, webhooks: byName, nativeSymbol: nativeSymbol}, nil
+}
+
+func (n *WebhookNotifier) Has(name string) bool {
+	_, ok := n.webhooks[name]
+	return ok
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, event domain.AlertEvent) error {
+	hook, ok := n.webhooks[event.Webhook]
+	if !ok {
+		return fmt.Errorf("unknown webhook %s", event.Webhook)
+	}
+
+	body, err := encodePayload(hook.Format, NewMessage(event), n.nativeSymbol)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook %s: %w", hook.Name, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded %s", hook.Name, resp.Status)
+	}
+	return nil
+}
+
+func encodePayload(format string, msg Message, nativeSymbol string) ([]byte, error) {
+	var payload any
+	switch format {
+	case FormatSlack:
+		payload = map[string]string{"text": msg.Summary(nativeSymbol)}
+	case FormatDiscord:
+		content := msg.Summary(nativeSymbol)
+		if utf8.RuneCountInString(content) > discordContentLimit {
+			content = string([]rune(content)[:discordContentLimit])
+		}
+		payload = map[string]string{"content": content}
+	default:
+		payload = msg
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode webhook payload: %w", err)
+	}
+	return body, nil
+}
+
+var _ domain.AlertNotifier = (*WebhookNotifier)(nil)
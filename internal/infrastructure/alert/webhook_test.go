@@ -0,0 +1,43 @@
+package alert
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEncodePayloadDiscordTruncatesOnRuneBoundary(t *testing.T) {
+	// Each "é" is two bytes, so a byte cut at the limit would split one.
+	msg := Message{Rule: "r", Type: "TRANSFER", Value: "0", Details: strings.Repeat("é", discordContentLimit)}
+	body, err := encodePayload(FormatDiscord, msg, "ETH")
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	content := payload["content"]
+	if !utf8.ValidString(content) || strings.ContainsRune(content, utf8.RuneError) {
+		t.Fatalf("content is not valid UTF-8")
+	}
+	if got := utf8.RuneCountInString(content); got != discordContentLimit {
+		t.Fatalf("content has %d characters, want %d", got, discordContentLimit)
+	}
+}
+
+func TestEncodePayloadDiscordKeepsShortContent(t *testing.T) {
+	msg := Message{Rule: "r", Type: "TRANSFER", Value: "0", Details: "ünïcode"}
+	body, err := encodePayload(FormatDiscord, msg, "ETH")
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload["content"] != msg.Summary("ETH") {
+		t.Fatalf("content = %q", payload["content"])
+	}
+}
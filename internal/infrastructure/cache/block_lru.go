@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+
+	"ethClassify/internal/domain"
+)
+
+type BlockLRU struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[uint64]*list.Element
+}
+
+type blockEntry struct {
+	number uint64
+	result domain.BlockResult
+}
+
+func NewBlockLRU(capacity int) *BlockLRU {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &BlockLRU{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[uint64]*list.Element, capacity),
+	}
+}
+
+func (c *BlockLRU) Get(number uint64) (domain.BlockResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[number]
+	if !ok {
+		return domain.BlockResult{}, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*blockEntry).result, true
+}
+
+func (c *BlockLRU) Add(number uint64, result domain.BlockResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[number]; ok {
+		elem.Value.(*blockEntry).result = result
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[number] = c.order.PushFront(&blockEntry{number: number, result: result})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*blockEntry).number)
+	}
+}
+
+var _ domain.BlockResultCache = (*BlockLRU)(nil)
@@ -0,0 +1,325 @@
+package chain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+// Profile bundles the chain-specific data the classifiers and presenters need.
+type Profile struct {
+	Name          string
+	ChainID       uint64
+	NativeSymbol  string
+	WrappedNative string
+	Dexes         []domain.DexDeployment
+	Bridges       []domain.BridgeContract
+	Rollups       []domain.RollupInbox
+	Lending       []domain.LendingMarket
+	FlashLenders  []domain.FlashLender
+	Labels        map[string]string
+}
+
+var (
+	uniswapV3Routers = []string{
+		"0xE592427A0AEce92De3Edee1F18E0157C05861564", // SwapRouter
+		"0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45", // SwapRouter02
+	}
+	uniswapV3 = domain.DexDeployment{
+		Name:     "uniswap-v3",
+		Protocol: "uniswap-v3",
+		Factory:  "0x1F98431c8aD98523631AE4a59f267346ea31F984",
+		Routers:  uniswapV3Routers,
+	}
+	// SushiSwap shares factory and router addresses on most sidechains and L2s.
+	sushiswapL2 = domain.DexDeployment{
+		Name:     "sushiswap",
+		Protocol: "uniswap-v2",
+		Factory:  "0xc35DADB65012eC5796536bD9864eD8773aBc74C4",
+		Routers:  []string{"0x1b02dA8Cb0d097eB8D57A175b88c7D8b47997506"},
+	}
+)
+
+// Aave V3 shares its pool address on most L2s and sidechains; Morpho Blue
+// is deployed at the same address wherever it exists.
+var (
+	aaveV3L2Pool = domain.LendingMarket{Name: "aave-v3-pool", Protocol: domain.LendingProtocolAaveV3, Address: "0x794a61358D6845594F94dc1DB02A252b5b4814aD"}
+	morphoBlue   = domain.LendingMarket{Name: "morpho-blue", Protocol: domain.LendingProtocolMorphoBlue, Address: "0xBBBBBbbBBb9cC5e90e3b3Af64bdAF62C37EEFFCb"}
+)
+
+// The Balancer V2 Vault has the same address on every chain.
+var balancerVault = domain.FlashLender{Name: "balancer-vault", Protocol: domain.FlashProtocolBalancerV2, Address: "0xBA12222222228d8Ba445958a75a0704d566BF2C8"}
+
+var ethereumFlashLenders = []domain.FlashLender{
+	balancerVault,
+	{Name: "dydx-solo-margin", Protocol: domain.FlashProtocolDydx, Address: "0x1E0447b19BB6EcFdAe1e4AE1694b0C3659614e4e"},
+	{Name: "maker-dss-flash", Protocol: domain.FlashProtocolMakerFlash, Address: "0x60744434d6339a6B27d73d9Eda62b6F66a0a04FA"},
+}
+
+var ethereumLending = []domain.LendingMarket{
+	{Name: "aave-v3-pool", Protocol: domain.LendingProtocolAaveV3, Address: "0x87870Bca3F3fD6335C3F4ce8392D69350B4fA4E2"},
+	{Name: "aave-v2-pool", Protocol: domain.LendingProtocolAaveV2, Address: "0x7d2768dE32b0b80b7a3454c06BdAc94A69DDc7A9"},
+	{Name: "spark-pool", Protocol: domain.LendingProtocolAaveV3, Address: "0xC13e21B648A5Ee794902342038FF3aDAB66BE987"},
+	{Name: "compound-ceth", Protocol: domain.LendingProtocolCompoundV2, Address: "0x4Ddc2D193948926D02f9B1fE9e1daa0718270ED5"},
+	{Name: "compound-cusdc", Protocol: domain.LendingProtocolCompoundV2, Address: "0x39AA39c021dfbaE8faC545936693aC917d5E7563", Asset: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"},
+	{Name: "compound-cdai", Protocol: domain.LendingProtocolCompoundV2, Address: "0x5d3a536E4D6DbD6114cc1Ead35777bAB948E3643", Asset: "0x6B175474E89094C44Da98b954EedeAC495271d0F"},
+	{Name: "compound-cusdt", Protocol: domain.LendingProtocolCompoundV2, Address: "0xf650C3d88D12dB855b8bf7D11Be6C55A4e07dCC9", Asset: "0xdAC17F958D2ee523a2206206994597C13D831ec7"},
+	{Name: "compound-cwbtc", Protocol: domain.LendingProtocolCompoundV2, Address: "0xccF4429DB6322D5C611ee964527D42E5d685DD6a", Asset: "0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599"},
+	{Name: "compound-cusdcv3", Protocol: domain.LendingProtocolCompoundV3, Address: "0xc3d688B66703497DAA19211EEdff47f25384cdc3", Asset: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"},
+	{Name: "compound-cwethv3", Protocol: domain.LendingProtocolCompoundV3, Address: "0xA17581A9E3356d9A858b789D68B4d866e593aE94", Asset: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"},
+	{Name: "compound-cusdtv3", Protocol: domain.LendingProtocolCompoundV3, Address: "0x3Afdc9BCA9213A35503b077a6072F3D0d5AB0840", Asset: "0xdAC17F958D2ee523a2206206994597C13D831ec7"},
+	morphoBlue,
+}
+
+// acrossSpokePool returns the Across deposit/fill contract of one chain; the
+// counterparty chain comes from the events themselves.
+func acrossSpokePool(address string) domain.BridgeContract {
+	return domain.BridgeContract{Name: "across", Protocol: domain.BridgeProtocolAcross, Address: address}
+}
+
+// Rollups posting their batches to Ethereum, by batch inbox and batcher.
+var ethereumRollups = []domain.RollupInbox{
+	{Name: "optimism", Inbox: "0xFF00000000000000000000000000000000000010", Batchers: []string{"0x6887246668a3b87F54DeB3b94Ba47a6f63F32985"}},
+	{Name: "base", Inbox: "0xFf00000000000000000000000000000000008453", Batchers: []string{"0x5050F69a9786F081509234F1a7F4684b5E5b76C9"}},
+	{Name: "zora", Inbox: "0x6F54Ca6F6EdE96662024Ffd61BFd18f3f4e34DFf", Batchers: []string{"0x625726c858dBF78c0125436C943Bf4b4bE9d9cc4"}},
+	{
+		Name: "arbitrum", Inbox: "0x1c479675ad559DC151F6Ec7ed3FbF8ceE79582B6", Batchers: []string{"0xC1b634853Cb333D3aD8663715b08f41A3Aec47cc"},
+		SharedInbox: true,
+		// addSequencerL2BatchFromOrigin (old and new), addSequencerL2BatchFromBlobs, addSequencerL2Batch
+		BatchSelectors: []string{"8f111f3c", "6f12b0c9", "3e5aa082", "e0bc9729"},
+	},
+	{
+		Name: "scroll", Inbox: "0xa13BAF47339d63B743e7Da8741db5456DAc1E556",
+		SharedInbox: true,
+		// commitBatch, commitBatchWithBlobProof, commitBatches
+		BatchSelectors: []string{"1325aca0", "86b053a9", "9bbaa2ba"},
+	},
+	{Name: "linea", Inbox: "0xd19d4B5d358258f05D7B411E21A1460D11B0876F", SharedInbox: true},
+	{
+		Name: "starknet", Inbox: "0xc662c410C0ECf747543f5bA90660f6ABeBD9C8c4",
+		SharedInbox: true,
+		// updateState
+		BatchSelectors: []string{"538f9406"},
+	},
+}
+
+// Canonical rollup and sidechain bridges live on L1, so only the ethereum
+// profile lists them; L2 profiles only carry third-party bridges.
+var ethereumBridges = []domain.BridgeContract{
+	{Name: "optimism-standard-bridge", Protocol: domain.BridgeProtocolOPStandard, Address: "0x99C9fc46f92E8a1c0deC1b1747d010903E884bE1", Chain: "optimism"},
+	{Name: "base-standard-bridge", Protocol: domain.BridgeProtocolOPStandard, Address: "0x3154Cf16ccdb4C6d922629664174b904d80F2C35", Chain: "base"},
+	{Name: "arbitrum-inbox", Protocol: domain.BridgeProtocolArbitrumInbox, Address: "0x4Dbd4fc535Ac27206064B68FfCf827b0A60BAB3f", Chain: "arbitrum"},
+	{Name: "arbitrum-erc20-gateway", Protocol: domain.BridgeProtocolArbitrumGateway, Address: "0xa3A7B6F88361F48403514059F1F16C8E78d60EeC", Chain: "arbitrum"},
+	{Name: "arbitrum-custom-gateway", Protocol: domain.BridgeProtocolArbitrumGateway, Address: "0xcEe284F754E854890e311e3280b767F80797180d", Chain: "arbitrum"},
+	{Name: "arbitrum-outbox", Protocol: domain.BridgeProtocolArbitrumOutbox, Address: "0x0B9857ae2D4A3DBe74ffE1d7DF045bb7F96E4840", Chain: "arbitrum"},
+	{Name: "zksync-shared-bridge", Protocol: domain.BridgeProtocolZkSync, Address: "0xD7f9f54194C633F36CCD5F3da84ad4a1c38cB2cB", Chain: "zksync"},
+	{Name: "zksync-erc20-bridge", Protocol: domain.BridgeProtocolZkSync, Address: "0x57891966931Eb4Bb6FB81430E6cE0A03AAbDe063", Chain: "zksync"},
+	{Name: "polygon-erc20-predicate", Protocol: domain.BridgeProtocolPolygonPoS, Address: "0x40ec5B33f54e0E8A33A975908C5BA1c14e5BbbDf", Chain: "polygon"},
+	{Name: "polygon-ether-predicate", Protocol: domain.BridgeProtocolPolygonPoS, Address: "0x8484Ef722627bf18ca5Ae6BcF031c23E6e922B30", Chain: "polygon"},
+	acrossSpokePool("0x5c7BCd6E7De5423a257D81B442095A1a6ced35C5"),
+	{Name: "hop", Protocol: domain.BridgeProtocolHop, Address: "0xb8901acB165ed027E32754E0FFe830802919727f"},
+}
+
+var profiles = []Profile{
+	{
+		Name:          "ethereum",
+		ChainID:       1,
+		NativeSymbol:  "ETH",
+		WrappedNative: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+		Dexes: []domain.DexDeployment{
+			{
+				Name:     "uniswap-v2",
+				Protocol: "uniswap-v2",
+				Factory:  "0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f",
+				Routers:  []string{"0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"},
+			},
+			uniswapV3,
+			{
+				Name:     "sushiswap",
+				Protocol: "uniswap-v2",
+				Factory:  "0xC0AEe478e3658e2610c5F7A4A2E1777cE9e4f2Ac",
+				Routers:  []string{"0xd9e1cE17f2641f24aE83637ab66a2cca9C378B9F"},
+			},
+		},
+		Bridges:      ethereumBridges,
+		Rollups:      ethereumRollups,
+		Lending:      ethereumLending,
+		FlashLenders: ethereumFlashLenders,
+		Labels: map[string]string{
+			"0xdac17f958d2ee523a2206206994597c13d831ec7": "USDT",
+			"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": "USDC",
+			"0x6b175474e89094c44da98b954eedeac495271d0f": "DAI",
+		},
+	},
+	{
+		Name:          "optimism",
+		ChainID:       10,
+		NativeSymbol:  "ETH",
+		WrappedNative: "0x4200000000000000000000000000000000000006",
+		Dexes:         []domain.DexDeployment{uniswapV3},
+		Bridges:       []domain.BridgeContract{acrossSpokePool("0x6f26Bf09B1C792e3228e5467807a900A503c0281")},
+		Lending:       []domain.LendingMarket{aaveV3L2Pool},
+		FlashLenders:  []domain.FlashLender{balancerVault},
+		Labels: map[string]string{
+			"0x0b2c639c533813f4aa9d7837caf62653d097ff85": "USDC",
+			"0x7f5c764cbc14f9669b88837ca1490cca17c31607": "USDC.e",
+			"0x94b008aa00579c1307b0ef2c499ad98a8ce58e58": "USDT",
+			"0xda10009cbd5d07dd0cecc66161fc93d7c9000da1": "DAI",
+			"0x4200000000000000000000000000000000000042": "OP",
+		},
+	},
+	{
+		Name:          "polygon",
+		ChainID:       137,
+		NativeSymbol:  "POL",
+		WrappedNative: "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270",
+		Dexes: []domain.DexDeployment{
+			uniswapV3,
+			{
+				Name:     "quickswap",
+				Protocol: "uniswap-v2",
+				Factory:  "0x5757371414417b8C6CAad45bAeF941aBc7d3Ab32",
+				Routers:  []string{"0xa5E0829CaCEd8fFDD4De3c43696c57F7D7A678ff"},
+			},
+			sushiswapL2,
+		},
+		Bridges: []domain.BridgeContract{acrossSpokePool("0x9295ee1d8C5b022Be115A2AD3c30C72E34e7F096")},
+		Lending: []domain.LendingMarket{
+			aaveV3L2Pool,
+			{Name: "aave-v2-pool", Protocol: domain.LendingProtocolAaveV2, Address: "0x8dFf5E27EA6b7AC08EbFdf9eB090F32ee9a30fcf"},
+			{Name: "compound-cusdcv3", Protocol: domain.LendingProtocolCompoundV3, Address: "0xF25212E676D1F7F89Cd72fFEe66158f6a2b3596b", Asset: "0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359"},
+		},
+		FlashLenders: []domain.FlashLender{balancerVault},
+		Labels: map[string]string{
+			"0x3c499c542cef5e3811e1192ce70d8cc03d5c3359": "USDC",
+			"0x2791bca1f2de4661ed88a30c99a7a9449aa84174": "USDC.e",
+			"0xc2132d05d31c914a87c6611c10748aeb04b58e8f": "USDT",
+			"0x8f3cf7ad23cd3cadbd9735aff958023239c6a063": "DAI",
+			"0x7ceb23fd6bc0add59e62ac25578270cff1b9f619": "WETH",
+		},
+	},
+	{
+		Name:          "base",
+		ChainID:       8453,
+		NativeSymbol:  "ETH",
+		WrappedNative: "0x4200000000000000000000000000000000000006",
+		Dexes: []domain.DexDeployment{
+			{
+				Name:     "uniswap-v2",
+				Protocol: "uniswap-v2",
+				Factory:  "0x8909Dc15e40173Ff4699343b6eB8132c65e18eC6",
+				Routers:  []string{"0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24"},
+			},
+			{
+				Name:     "uniswap-v3",
+				Protocol: "uniswap-v3",
+				Factory:  "0x33128a8fC17869897dcE68Ed026d694621f6FDfD",
+				Routers:  []string{"0x2626664c2603336E57B271c5C0b26F421741e481"},
+			},
+		},
+		Bridges: []domain.BridgeContract{acrossSpokePool("0x09aea4b2242abC8bb4BB78D537A67a245A7bEC64")},
+		Lending: []domain.LendingMarket{
+			{Name: "aave-v3-pool", Protocol: domain.LendingProtocolAaveV3, Address: "0xA238Dd80C259a72e81d7e4664a9801593F98d1c5"},
+			{Name: "compound-cusdcv3", Protocol: domain.LendingProtocolCompoundV3, Address: "0xb125E6687d4313864e53df431d5425969c15Eb2F", Asset: "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"},
+			morphoBlue,
+		},
+		FlashLenders: []domain.FlashLender{balancerVault},
+		Labels: map[string]string{
+			"0x833589fcd6edb6e08f4c7c32d4f71b54bda02913": "USDC",
+			"0x50c5725949a6f0c72e6c4a641f24049a917db0cb": "DAI",
+		},
+	},
+	{
+		Name:          "arbitrum",
+		ChainID:       42161,
+		NativeSymbol:  "ETH",
+		WrappedNative: "0x82aF49447D8a07e3bd95BD0d56f35241523fBab1",
+		Dexes:         []domain.DexDeployment{uniswapV3, sushiswapL2},
+		Bridges:       []domain.BridgeContract{acrossSpokePool("0xe35e9842fceaCA96570B734083f4a58e8F7C5f2A")},
+		Lending: []domain.LendingMarket{
+			aaveV3L2Pool,
+			{Name: "compound-cusdcv3", Protocol: domain.LendingProtocolCompoundV3, Address: "0x9c4ec768c28520B50860ea7a15bd7213a9fF58bf", Asset: "0xaf88d065e77c8cC2239327C5EDb3A432268e5831"},
+		},
+		FlashLenders: []domain.FlashLender{balancerVault},
+		Labels: map[string]string{
+			"0xaf88d065e77c8cc2239327c5edb3a432268e5831": "USDC",
+			"0xff970a61a04b1ca14834a43f5de4533ebddb5cc8": "USDC.e",
+			"0xfd086bc7cd5c481dcc9c85ebe478a1c0b69fcbb9": "USDT",
+			"0xda10009cbd5d07dd0cecc66161fc93d7c9000da1": "DAI",
+			"0x912ce59144191c1204e64559fe8253a0e49e6548": "ARB",
+		},
+	},
+}
+
+// ByName returns the profile with the given name (case-insensitive).
+func ByName(name string) (Profile, bool) {
+	for _, p := range profiles {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+func ByChainID(id uint64) (Profile, bool) {
+	for _, p := range profiles {
+		if p.ChainID == id {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// Generic is used for chains without a profile: no labels, no DEX
+// deployments, and the native currency shown as ETH.
+func Generic(id uint64) Profile {
+	return Profile{Name: fmt.Sprintf("chain-%d", id), ChainID: id, NativeSymbol: "ETH"}
+}
+
+func Names() []string {
+	names := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AllLabels merges the profile's token labels with labels for the wrapped
+// native token, every DEX factory and router, every bridge contract, every
+// rollup inbox and batcher, every lending market and every flash lender.
+func (p Profile) AllLabels() map[string]string {
+	out := make(map[string]string, len(p.Labels)+1+3*len(p.Dexes)+len(p.Bridges)+2*len(p.Rollups)+len(p.Lending)+len(p.FlashLenders))
+	for addr, label := range p.Labels {
+		out[strings.ToLower(addr)] = label
+	}
+	if p.WrappedNative != "" {
+		out[strings.ToLower(p.WrappedNative)] = "W" + p.NativeSymbol
+	}
+	for _, dex := range p.Dexes {
+		if dex.Factory != "" {
+			out[strings.ToLower(dex.Factory)] = dex.Name + " factory"
+		}
+		for _, router := range dex.Routers {
+			out[strings.ToLower(router)] = dex.Name + " router"
+		}
+	}
+	for _, bridge := range p.Bridges {
+		out[strings.ToLower(bridge.Address)] = bridge.Name
+	}
+	for _, rollup := range p.Rollups {
+		out[strings.ToLower(rollup.Inbox)] = rollup.Name + " batch inbox"
+		for _, batcher := range rollup.Batchers {
+			out[strings.ToLower(batcher)] = rollup.Name + " batcher"
+		}
+	}
+	for _, market := range p.Lending {
+		out[strings.ToLower(market.Address)] = market.Name
+	}
+	for _, lender := range p.FlashLenders {
+		out[strings.ToLower(lender.Address)] = lender.Name
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package chain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProfilesAreFoundByNameAndChainID(t *testing.T) {
+	seen := map[uint64]string{}
+	for _, name := range Names() {
+		profile, ok := ByName(strings.ToUpper(name))
+		if !ok || profile.Name != name {
+			t.Fatalf("ByName(%q) = %v, %v", strings.ToUpper(name), profile.Name, ok)
+		}
+		if other, dup := seen[profile.ChainID]; dup {
+			t.Fatalf("chain id %d used by %s and %s", profile.ChainID, other, name)
+		}
+		seen[profile.ChainID] = name
+		if byID, ok := ByChainID(profile.ChainID); !ok || byID.Name != name {
+			t.Errorf("ByChainID(%d) = %v, %v; want %s", profile.ChainID, byID.Name, ok, name)
+		}
+		if profile.NativeSymbol == "" || profile.WrappedNative == "" {
+			t.Errorf("%s has no native currency", name)
+		}
+	}
+	if _, ok := ByChainID(31337); ok {
+		t.Error("ByChainID(31337) found a profile")
+	}
+}
+
+func TestAllLabelsLowercasesAddresses(t *testing.T) {
+	profile, _ := ByName("polygon")
+	labels := profile.AllLabels()
+	for addr := range labels {
+		if addr != strings.ToLower(addr) {
+			t.Errorf("label key %s is not lowercase", addr)
+		}
+	}
+	if got := labels[strings.ToLower(profile.WrappedNative)]; got != "WPOL" {
+		t.Errorf("wrapped native label = %q, want WPOL", got)
+	}
+	if got := labels["0x794a61358d6845594f94dc1db02a252b5b4814ad"]; got != "aave-v3-pool" {
+		t.Errorf("aave pool label = %q", got)
+	}
+}
+
+func TestGenericProfile(t *testing.T) {
+	profile := Generic(31337)
+	if profile.Name != "chain-31337" || profile.NativeSymbol != "ETH" || len(profile.AllLabels()) != 0 {
+		t.Errorf("Generic(31337) = %+v", profile)
+	}
+}
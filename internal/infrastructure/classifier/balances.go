@@ -0,0 +1,225 @@
+package classifier
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	transferSingleTopic = "0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62"
+	transferBatchTopic  = "0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb"
+	wethDepositTopic    = "0xe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c"
+	wethWithdrawalTopic = "0x7fcf532c15f0a6db0bd6d0e038bea71d30d808c7d98cb3bf7268a95bf5081b65"
+
+	zeroAddress = "0x0000000000000000000000000000000000000000"
+)
+
+// BalanceDeltaEnricher computes the net balance change per address and asset.
+// ETH moves come from the call tree when available (top-level value
+// otherwise), ETH minted by L2 deposits, the gas fee paid by the sender
+// (including the OP-stack L1 fee and the blob fee) and the priority fee
+// credited to the coinbase; token moves come from
+// ERC20/721/1155 transfer logs. Mints and burns only credit or debit the
+// counterparty, never the zero address.
+type BalanceDeltaEnricher struct {
+	// WrappedNative is the chain's WETH-style token; its Deposit and
+	// Withdrawal events mint and burn without emitting a Transfer.
+	WrappedNative string
+}
+
+func (e BalanceDeltaEnricher) Enrich(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+	book := newBalanceBook()
+	nativeMoves(tx, book)
+	for _, t := range TokenTransfers(tx) {
+		standard := domain.AssetERC20
+		if t.Standard == StandardERC721 {
+			standard = domain.AssetERC721
+		}
+		book.move(t.From, t.To, standard, t.Token, t.TokenID, t.Amount)
+	}
+	for _, t := range erc1155Transfers(tx) {
+		book.move(t.From, t.To, domain.AssetERC1155, t.Token, t.TokenID, t.Amount)
+	}
+	if e.WrappedNative != "" {
+		wrappedNativeMoves(tx, strings.ToLower(e.WrappedNative), book)
+	}
+
+	deltas := book.deltas()
+	if len(deltas) == 0 {
+		return current, false, nil
+	}
+	updated := current
+	updated.Balances = deltas
+	return updated, true, nil
+}
+
+func nativeMoves(tx domain.Tx, book *balanceBook) {
+	if tx.L2 != nil && tx.L2.Mint != nil {
+		// L2 deposits create ETH for the sender before executing.
+		book.move("", tx.From, domain.AssetNative, "", nil, tx.L2.Mint)
+	}
+	if tx.Calls != nil {
+		// The root frame is the tx itself, so its value is included here.
+		WalkCalls(tx.Calls, func(call domain.Call, depth int) bool {
+			switch call.Type {
+			case domain.CallTypeCall, domain.CallTypeCallCode, domain.CallTypeCreate,
+				domain.CallTypeCreate2, domain.CallTypeSelfDestruct:
+				book.move(call.From, call.To, domain.AssetNative, "", nil, call.Value)
+			}
+			return true
+		})
+	} else if tx.Receipt == nil || tx.Receipt.Success {
+		to := ""
+		if tx.To != nil {
+			to = *tx.To
+		} else if tx.Receipt != nil {
+			to = tx.Receipt.ContractAddress
+		}
+		book.move(tx.From, to, domain.AssetNative, "", nil, tx.Value)
+	}
+
+	if tx.L2 != nil && tx.L2.Kind == domain.L2KindOPDeposit {
+		// Deposits buy their L2 gas on L1.
+		return
+	}
+	if tx.Receipt != nil && tx.Receipt.EffectiveGasPrice != nil {
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(tx.Receipt.GasUsed), tx.Receipt.EffectiveGasPrice)
+		if l1 := tx.Receipt.L1Fee; l1 != nil && l1.Fee != nil {
+			// OP-stack charges the L1 data fee on top of the L2 gas.
+			fee.Add(fee, l1.Fee)
+		}
+		if tx.Receipt.BlobGasPrice != nil {
+			fee.Add(fee, new(big.Int).Mul(new(big.Int).SetUint64(tx.Receipt.BlobGasUsed), tx.Receipt.BlobGasPrice))
+		}
+		book.move(tx.From, "", domain.AssetNative, "", nil, fee)
+		if tx.Receipt.Coinbase != "" {
+			book.move("", tx.Receipt.Coinbase, domain.AssetNative, "", nil, priorityFee(*tx.Receipt))
+		}
+	}
+}
+
+// priorityFee is the part of the gas fee paid to the block's coinbase; the
+// base fee, blob fee and L1 fee are burned or go elsewhere.
+func priorityFee(receipt domain.Receipt) *big.Int {
+	tip := new(big.Int).Set(receipt.EffectiveGasPrice)
+	if receipt.BaseFee != nil {
+		tip.Sub(tip, receipt.BaseFee)
+	}
+	if tip.Sign() <= 0 {
+		return nil
+	}
+	return tip.Mul(tip, new(big.Int).SetUint64(receipt.GasUsed))
+}
+
+func wrappedNativeMoves(tx domain.Tx, token string, book *balanceBook) {
+	for _, log := range tx.Logs {
+		if len(log.Topics) != 2 || strings.ToLower(log.Address) != token {
+			continue
+		}
+		amount, ok := abiUint(log.Data, 0)
+		if !ok {
+			continue
+		}
+		holder := topicToAddress(log.Topics[1])
+		switch log.Topics[0] {
+		case wethDepositTopic:
+			book.move("", holder, domain.AssetERC20, token, nil, amount)
+		case wethWithdrawalTopic:
+			book.move(holder, "", domain.AssetERC20, token, nil, amount)
+		}
+	}
+}
+
+func erc1155Transfers(tx domain.Tx) []domain.TokenTransfer {
+	var out []domain.TokenTransfer
+	for _, log := range tx.Logs {
+		if len(log.Topics) != 4 {
+			continue
+		}
+		from := topicToAddress(log.Topics[2])
+		to := topicToAddress(log.Topics[3])
+		token := strings.ToLower(log.Address)
+		switch log.Topics[0] {
+		case transferSingleTopic:
+			id, ok1 := abiUint(log.Data, 0)
+			amount, ok2 := abiUint(log.Data, 1)
+			if !ok1 || !ok2 {
+				continue
+			}
+			out = append(out, domain.TokenTransfer{LogIndex: log.Index, Token: token, From: from, To: to, TokenID: id, Amount: amount})
+		case transferBatchTopic:
+			ids, ok1 := abiUintArray(log.Data, 0)
+			amounts, ok2 := abiUintArray(log.Data, 1)
+			if !ok1 || !ok2 || len(ids) != len(amounts) {
+				continue
+			}
+			for i := range ids {
+				out = append(out, domain.TokenTransfer{LogIndex: log.Index, Token: token, From: from, To: to, TokenID: ids[i], Amount: amounts[i]})
+			}
+		}
+	}
+	return out
+}
+
+// balanceBook accumulates signed deltas keyed by address and asset, keeping
+// first-seen order so output is stable.
+type balanceBook struct {
+	entries map[string]*domain.BalanceDelta
+	order   []string
+}
+
+func newBalanceBook() *balanceBook {
+	return &balanceBook{entries: make(map[string]*domain.BalanceDelta)}
+}
+
+// move debits from and credits to; an empty address means the amount leaves
+// or enters the tracked set (gas burned or paid, mint/burn counterparty).
+func (b *balanceBook) move(from, to, standard, token string, tokenID, amount *big.Int) {
+	if amount == nil || amount.Sign() == 0 {
+		return
+	}
+	b.add(from, standard, token, tokenID, new(big.Int).Neg(amount))
+	b.add(to, standard, token, tokenID, amount)
+}
+
+func (b *balanceBook) add(addr, standard, token string, tokenID, amount *big.Int) {
+	if addr == "" {
+		return
+	}
+	addr = strings.ToLower(common.HexToAddress(addr).Hex())
+	if addr == zeroAddress {
+		return
+	}
+	key := addr + "|" + standard + "|" + token
+	if tokenID != nil {
+		key += "|" + tokenID.String()
+	}
+	entry, ok := b.entries[key]
+	if !ok {
+		entry = &domain.BalanceDelta{
+			Address:  addr,
+			Standard: standard,
+			Token:    token,
+			TokenID:  tokenID,
+			Delta:    new(big.Int),
+		}
+		b.entries[key] = entry
+		b.order = append(b.order, key)
+	}
+	entry.Delta.Add(entry.Delta, amount)
+}
+
+func (b *balanceBook) deltas() []domain.BalanceDelta {
+	var out []domain.BalanceDelta
+	for _, key := range b.order {
+		if entry := b.entries[key]; entry.Delta.Sign() != 0 {
+			out = append(out, *entry)
+		}
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package classifier
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+func nativeDeltas(t *testing.T, tx domain.Tx) map[string]int64 {
+	t.Helper()
+	res, _, err := BalanceDeltaEnricher{}.Enrich(context.Background(), tx, domain.TxResult{})
+	if err != nil {
+		t.Fatalf("Enrich: %v", err)
+	}
+	out := map[string]int64{}
+	for _, d := range res.Balances {
+		if d.Standard == domain.AssetNative {
+			out[d.Address] = d.Delta.Int64()
+		}
+	}
+	return out
+}
+
+func TestBalanceDeltaGasFee(t *testing.T) {
+	const (
+		sender   = "0x1111111111111111111111111111111111111111"
+		to       = "0x2222222222222222222222222222222222222222"
+		coinbase = "0x3333333333333333333333333333333333333333"
+	)
+	recipient := to
+	tests := []struct {
+		name    string
+		receipt domain.Receipt
+		want    map[string]int64
+	}{
+		{
+			name:    "tip to coinbase",
+			receipt: domain.Receipt{Success: true, GasUsed: 21000, EffectiveGasPrice: big.NewInt(12), BaseFee: big.NewInt(10), Coinbase: coinbase},
+			want:    map[string]int64{sender: -100 - 21000*12, to: 100, coinbase: 21000 * 2},
+		},
+		{
+			name:    "pre-London",
+			receipt: domain.Receipt{Success: true, GasUsed: 21000, EffectiveGasPrice: big.NewInt(12), Coinbase: coinbase},
+			want:    map[string]int64{sender: -100 - 21000*12, to: 100, coinbase: 21000 * 12},
+		},
+		{
+			name:    "blob fee is not credited",
+			receipt: domain.Receipt{Success: true, GasUsed: 21000, EffectiveGasPrice: big.NewInt(10), BaseFee: big.NewInt(10), Coinbase: coinbase, BlobGasUsed: 131072, BlobGasPrice: big.NewInt(1)},
+			want:    map[string]int64{sender: -100 - 21000*10 - 131072, to: 100},
+		},
+		{
+			name:    "no block context",
+			receipt: domain.Receipt{Success: true, GasUsed: 21000, EffectiveGasPrice: big.NewInt(12)},
+			want:    map[string]int64{sender: -100 - 21000*12, to: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := tt.receipt
+			got := nativeDeltas(t, domain.Tx{From: sender, To: &recipient, Value: big.NewInt(100), Receipt: &receipt})
+			if len(got) != len(tt.want) {
+				t.Fatalf("deltas = %v, want %v", got, tt.want)
+			}
+			for addr, want := range tt.want {
+				if got[addr] != want {
+					t.Fatalf("delta of %s = %d, want %d (all: %v)", addr, got[addr], want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,502 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	opETHDepositInitiatedTopic         = "0x35d79ab81f2b2017e19afb5c5571778877782d7a8786f5907f93b0f4702f4f23"
+	opERC20DepositInitiatedTopic       = "0x718594027abd4eaed59f95162563e0cc6d0e8d5b86b1c7be8b1b0ac3343d0396"
+	opETHWithdrawalFinalizedTopic      = "0x2ac69ee804d9a7a0984249f508dfab7cb2534b465b6ce1580f99a38ba9c5e631"
+	opERC20WithdrawalFinalizedTopic    = "0x3ceee06c1e37648fcbb6ed52e17b3e1f275a1f8c7b22a84b2b84732431e046b3"
+	arbInboxMessageDeliveredTopic      = "0xff64905f73a67fb594e0f940a8075a860db489ad991e032f48c81123eb52d60b"
+	arbGatewayDepositInitiatedTopic    = "0xb8910b9960c443aac3240b98585384e3a6f109fbf6969e264c3f183d69aba7e1"
+	arbGatewayWithdrawalFinalizedTopic = "0x891afe029c75c4f8c5855fc3480598bc5a53739344f6ae575bdb7ea2a79f56b3"
+	arbOutboxTxExecutedTopic           = "0x20af7f3bbfe38132b8900ae295cd9c8d1914be7052d061a511f3f728dab18964"
+	zkBridgehubDepositTopic            = "0x8768405a01370685449c74c293804d6c9cc216d170acdbdba50b33ed4144447f"
+	zkBridgehubBaseTokenDepositTopic   = "0x249bc8a55d0c4a0034b9aaa6be739bec2d4466e5d859bec9566a8553c405c838"
+	zkLegacyDepositInitiatedTopic      = "0xdd341179f4edc78148d894d0213a96d212af2cbaf223d19ef6d483bdd47ab81d"
+	zkWithdrawalFinalizedTopic         = "0xac1b18083978656d557d6e91c88203585cfda1031bdb14538327121ef140d383"
+	zkSharedWithdrawalFinalizedTopic   = "0x05518b128f0a9b11ddddebd5211a7fc2f4a689dab3a3e258d93eb13049983c3e"
+	polygonLockedERC20Topic            = "0x9b217a401a5ddf7c4d474074aff9958a18d48690d77cc2151c4706aa7348b401"
+	polygonLockedEtherTopic            = "0x3e799b2d61372379e767ef8f04d65089179b7a6f63f9be3065806456c7309f1b"
+	polygonExitedEtherTopic            = "0x0fc0eed41f72d3da77d0f53b9594fc7073acd15ee9d7c536819a70a67c57ef3c"
+	polygonExitedERC20Topic            = "0xbb61bd1b26b3684c7c028ff1a8f6dabcac2fac8ac57b66fa6b1efb6edeab03c4"
+	acrossV3FundsDepositedTopic        = "0xa123dc29aebf7d0c3322c8eeb5b999e859f39937950ed31056532713d0de396f"
+	acrossFundsDepositedTopic          = "0x32ed1a409ef04c7b0227189c3a103dc5ac10e775a15b785dcc510201f7c25ad3"
+	acrossFilledV3RelayTopic           = "0x94fa2c9fb2f56053305b2bc485c1eafde6d43378f8088ab520497ca1ffb9ea23"
+	hopTransferSentToL2Topic           = "0x0a0607688c86ec1775abcdbab7b33a3a35a6c9cde677c9be880150c231cc6b0b"
+
+	arbOutboxExecuteTransactionSelector = "08635a95"
+	zkSyncETHTokenAddress               = "0x0000000000000000000000000000000000000001"
+)
+
+// bridgeChainNames names the chain ids carried by bridge events. Ids missing
+// here are reported as chain-<id>, like the generic chain profile.
+var bridgeChainNames = map[uint64]string{
+	1:       "ethereum",
+	10:      "optimism",
+	56:      "bsc",
+	137:     "polygon",
+	324:     "zksync",
+	8453:    "base",
+	42161:   "arbitrum",
+	59144:   "linea",
+	534352:  "scroll",
+	7777777: "zora",
+}
+
+type bridgeEvent struct {
+	protocol  string
+	rule      string
+	direction domain.ClassificationType
+	// fallback events only classify the tx when no other bridge event did:
+	// the Arbitrum inbox and outbox also relay the gateways' token messages,
+	// which the gateway events describe better.
+	fallback bool
+	decode   func(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool
+}
+
+var bridgeEvents = map[string]bridgeEvent{
+	opETHDepositInitiatedTopic: {
+		protocol: domain.BridgeProtocolOPStandard, rule: "OP standard bridge ETHDepositInitiated event",
+		direction: domain.ClassificationBridgeDeposit, decode: decodeOPETHBridge,
+	},
+	opERC20DepositInitiatedTopic: {
+		protocol: domain.BridgeProtocolOPStandard, rule: "OP standard bridge ERC20DepositInitiated event",
+		direction: domain.ClassificationBridgeDeposit, decode: decodeOPERC20Bridge,
+	},
+	opETHWithdrawalFinalizedTopic: {
+		protocol: domain.BridgeProtocolOPStandard, rule: "OP standard bridge ETHWithdrawalFinalized event",
+		direction: domain.ClassificationBridgeWithdrawal, decode: decodeOPETHBridge,
+	},
+	opERC20WithdrawalFinalizedTopic: {
+		protocol: domain.BridgeProtocolOPStandard, rule: "OP standard bridge ERC20WithdrawalFinalized event",
+		direction: domain.ClassificationBridgeWithdrawal, decode: decodeOPERC20Bridge,
+	},
+	arbInboxMessageDeliveredTopic: {
+		protocol: domain.BridgeProtocolArbitrumInbox, rule: "Arbitrum InboxMessageDelivered event",
+		direction: domain.ClassificationBridgeDeposit, fallback: true, decode: decodeArbInboxMessage,
+	},
+	arbGatewayDepositInitiatedTopic: {
+		protocol: domain.BridgeProtocolArbitrumGateway, rule: "Arbitrum gateway DepositInitiated event",
+		direction: domain.ClassificationBridgeDeposit, decode: decodeArbGateway,
+	},
+	arbGatewayWithdrawalFinalizedTopic: {
+		protocol: domain.BridgeProtocolArbitrumGateway, rule: "Arbitrum gateway WithdrawalFinalized event",
+		direction: domain.ClassificationBridgeWithdrawal, decode: decodeArbGateway,
+	},
+	arbOutboxTxExecutedTopic: {
+		protocol: domain.BridgeProtocolArbitrumOutbox, rule: "Arbitrum OutBoxTransactionExecuted event",
+		direction: domain.ClassificationBridgeWithdrawal, fallback: true, decode: decodeArbOutbox,
+	},
+	zkBridgehubDepositTopic: {
+		protocol: domain.BridgeProtocolZkSync, rule: "zkSync BridgehubDepositInitiated event",
+		direction: domain.ClassificationBridgeDeposit, decode: decodeZkBridgehubDeposit,
+	},
+	zkBridgehubBaseTokenDepositTopic: {
+		protocol: domain.BridgeProtocolZkSync, rule: "zkSync BridgehubDepositBaseTokenInitiated event",
+		direction: domain.ClassificationBridgeDeposit, decode: decodeZkBaseTokenDeposit,
+	},
+	zkLegacyDepositInitiatedTopic: {
+		protocol: domain.BridgeProtocolZkSync, rule: "zkSync DepositInitiated event",
+		direction: domain.ClassificationBridgeDeposit, decode: decodeZkLegacyDeposit,
+	},
+	zkWithdrawalFinalizedTopic: {
+		protocol: domain.BridgeProtocolZkSync, rule: "zkSync WithdrawalFinalized event",
+		direction: domain.ClassificationBridgeWithdrawal, decode: decodeZkWithdrawal,
+	},
+	zkSharedWithdrawalFinalizedTopic: {
+		protocol: domain.BridgeProtocolZkSync, rule: "zkSync WithdrawalFinalizedSharedBridge event",
+		direction: domain.ClassificationBridgeWithdrawal, decode: decodeZkSharedWithdrawal,
+	},
+	polygonLockedERC20Topic: {
+		protocol: domain.BridgeProtocolPolygonPoS, rule: "Polygon PoS LockedERC20 event",
+		direction: domain.ClassificationBridgeDeposit, decode: decodePolygonLockedERC20,
+	},
+	polygonLockedEtherTopic: {
+		protocol: domain.BridgeProtocolPolygonPoS, rule: "Polygon PoS LockedEther event",
+		direction: domain.ClassificationBridgeDeposit, decode: decodePolygonLockedEther,
+	},
+	polygonExitedEtherTopic: {
+		protocol: domain.BridgeProtocolPolygonPoS, rule: "Polygon PoS ExitedEther event",
+		direction: domain.ClassificationBridgeWithdrawal, decode: decodePolygonExitedEther,
+	},
+	polygonExitedERC20Topic: {
+		protocol: domain.BridgeProtocolPolygonPoS, rule: "Polygon PoS ExitedERC20 event",
+		direction: domain.ClassificationBridgeWithdrawal, decode: decodePolygonExitedERC20,
+	},
+	acrossV3FundsDepositedTopic: {
+		protocol: domain.BridgeProtocolAcross, rule: "Across V3FundsDeposited event",
+		direction: domain.ClassificationBridgeDeposit, decode: decodeAcrossDeposit,
+	},
+	acrossFundsDepositedTopic: {
+		protocol: domain.BridgeProtocolAcross, rule: "Across FundsDeposited event",
+		direction: domain.ClassificationBridgeDeposit, decode: decodeAcrossDeposit,
+	},
+	acrossFilledV3RelayTopic: {
+		protocol: domain.BridgeProtocolAcross, rule: "Across FilledV3Relay event",
+		direction: domain.ClassificationBridgeWithdrawal, decode: decodeAcrossFill,
+	},
+	hopTransferSentToL2Topic: {
+		protocol: domain.BridgeProtocolHop, rule: "Hop TransferSentToL2 event",
+		direction: domain.ClassificationBridgeDeposit, decode: decodeHopTransfer,
+	},
+}
+
+// BridgeLogResolver detects deposits into and withdrawals out of the known
+// bridge contracts from their events. Only logs emitted by one of Bridges
+// count, so look-alike events from unrelated contracts are ignored.
+type BridgeLogResolver struct {
+	Bridges []domain.BridgeContract
+}
+
+func (r BridgeLogResolver) Resolve(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+	switch current.Type {
+	case domain.ClassificationContractCall, domain.ClassificationTransfer, domain.ClassificationUnknown:
+	default:
+		return current, false, nil
+	}
+	if len(r.Bridges) == 0 {
+		return current, false, nil
+	}
+
+	var fallback *domain.TxResult
+	for _, log := range tx.Logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		event, ok := bridgeEvents[log.Topics[0]]
+		if !ok {
+			continue
+		}
+		contract, ok := r.contract(log.Address)
+		if !ok || contract.Protocol != event.protocol {
+			continue
+		}
+		transfer := domain.BridgeTransfer{Bridge: contract.Name, Chain: contract.Chain}
+		if !event.decode(tx, log, &transfer) {
+			continue
+		}
+		updated := current
+		updated.Type = event.direction
+		updated.Bridge = &transfer
+		updated.Details = formatBridgeDetails(event.direction, transfer)
+		updated.Evidence = logEvidence(event.rule, current.Selector, log)
+		if !event.fallback {
+			return updated, true, nil
+		}
+		if fallback == nil {
+			fallback = &updated
+		}
+	}
+	if fallback != nil {
+		return *fallback, true, nil
+	}
+	return current, false, nil
+}
+
+func (r BridgeLogResolver) contract(address string) (domain.BridgeContract, bool) {
+	for _, bridge := range r.Bridges {
+		if strings.EqualFold(bridge.Address, address) {
+			return bridge, true
+		}
+	}
+	return domain.BridgeContract{}, false
+}
+
+func formatBridgeDetails(direction domain.ClassificationType, transfer domain.BridgeTransfer) string {
+	asset := "wei"
+	if transfer.Token != "" {
+		asset = transfer.Token
+	}
+	if direction == domain.ClassificationBridgeWithdrawal {
+		return fmt.Sprintf("%s withdrawal from %s: %s %s %s -> %s",
+			transfer.Bridge, transfer.Chain, formatOptional(transfer.Amount), asset, transfer.From, transfer.To)
+	}
+	return fmt.Sprintf("%s deposit to %s: %s %s %s -> %s",
+		transfer.Bridge, transfer.Chain, formatOptional(transfer.Amount), asset, transfer.From, transfer.To)
+}
+
+func bridgeChainName(id *big.Int) string {
+	if id == nil || !id.IsUint64() {
+		return "?"
+	}
+	if name, ok := bridgeChainNames[id.Uint64()]; ok {
+		return name
+	}
+	return fmt.Sprintf("chain-%d", id.Uint64())
+}
+
+// bridgeToken maps the placeholder addresses bridges use for ETH to "".
+func bridgeToken(token string) string {
+	if token == zkSyncETHTokenAddress {
+		return ""
+	}
+	return token
+}
+
+// ETHDepositInitiated / ETHWithdrawalFinalized(address indexed from, address indexed to, uint256 amount, bytes extraData)
+func decodeOPETHBridge(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 3 {
+		return false
+	}
+	transfer.From = topicToAddress(log.Topics[1])
+	transfer.To = topicToAddress(log.Topics[2])
+	transfer.Amount = amount
+	return true
+}
+
+// ERC20DepositInitiated / ERC20WithdrawalFinalized(address indexed l1Token, address indexed l2Token,
+// address indexed from, address to, uint256 amount, bytes extraData)
+func decodeOPERC20Bridge(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	to, ok1 := abiAddress(log.Data, 0)
+	amount, ok2 := abiUint(log.Data, 1)
+	if !ok1 || !ok2 || len(log.Topics) < 4 {
+		return false
+	}
+	transfer.Token = topicToAddress(log.Topics[1])
+	transfer.From = topicToAddress(log.Topics[3])
+	transfer.To = to
+	transfer.Amount = amount
+	return true
+}
+
+// InboxMessageDelivered(uint256 indexed messageNum, bytes data). An ETH
+// deposit packs the destination and value into 52 bytes; a retryable ticket
+// starts with the destination, the L2 call value and the total deposit.
+func decodeArbInboxMessage(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	data, ok := abiBytes(log.Data, 0)
+	if !ok {
+		return false
+	}
+	transfer.From = strings.ToLower(tx.From)
+	if len(data) == 52 {
+		transfer.To = strings.ToLower(common.BytesToAddress(data[:20]).Hex())
+		transfer.Amount = new(big.Int).SetBytes(data[20:52])
+		return true
+	}
+	to, ok1 := abiAddress(data, 0)
+	deposit, ok2 := abiUint(data, 2)
+	if !ok1 || !ok2 {
+		return false
+	}
+	transfer.To = to
+	transfer.Amount = deposit
+	return true
+}
+
+// DepositInitiated / WithdrawalFinalized(address l1Token, address indexed from,
+// address indexed to, uint256 indexed sequenceNumber|exitNum, uint256 amount)
+func decodeArbGateway(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	token, ok1 := abiAddress(log.Data, 0)
+	amount, ok2 := abiUint(log.Data, 1)
+	if !ok1 || !ok2 || len(log.Topics) < 3 {
+		return false
+	}
+	transfer.Token = token
+	transfer.From = topicToAddress(log.Topics[1])
+	transfer.To = topicToAddress(log.Topics[2])
+	transfer.Amount = amount
+	return true
+}
+
+// OutBoxTransactionExecuted(address indexed to, address indexed l2Sender,
+// uint256 indexed zero, uint256 transactionIndex). The event has no value, so
+// it is read from executeTransaction calldata when the tx called the outbox.
+func decodeArbOutbox(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	if len(log.Topics) < 3 {
+		return false
+	}
+	transfer.To = topicToAddress(log.Topics[1])
+	transfer.From = topicToAddress(log.Topics[2])
+	if tx.To != nil && strings.EqualFold(*tx.To, log.Address) && selectorHex(tx.Data) == arbOutboxExecuteTransactionSelector {
+		if value, ok := abiUint(tx.Data[4:], 7); ok {
+			transfer.Amount = value
+		}
+	}
+	return true
+}
+
+// BridgehubDepositInitiated(uint256 indexed chainId, bytes32 indexed txDataHash,
+// address indexed from, address to, address l1Token, uint256 amount)
+func decodeZkBridgehubDeposit(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	to, ok1 := abiAddress(log.Data, 0)
+	token, ok2 := abiAddress(log.Data, 1)
+	amount, ok3 := abiUint(log.Data, 2)
+	if !ok1 || !ok2 || !ok3 || len(log.Topics) < 4 {
+		return false
+	}
+	transfer.Chain = bridgeChainName(topicToBig(log.Topics[1]))
+	transfer.From = topicToAddress(log.Topics[3])
+	transfer.To = to
+	transfer.Token = bridgeToken(token)
+	transfer.Amount = amount
+	return true
+}
+
+// BridgehubDepositBaseTokenInitiated(uint256 indexed chainId, address indexed from,
+// address l1Token, uint256 amount)
+func decodeZkBaseTokenDeposit(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	token, ok1 := abiAddress(log.Data, 0)
+	amount, ok2 := abiUint(log.Data, 1)
+	if !ok1 || !ok2 || len(log.Topics) < 3 {
+		return false
+	}
+	transfer.Chain = bridgeChainName(topicToBig(log.Topics[1]))
+	transfer.From = topicToAddress(log.Topics[2])
+	transfer.To = transfer.From
+	transfer.Token = bridgeToken(token)
+	transfer.Amount = amount
+	return true
+}
+
+// DepositInitiated(bytes32 indexed l2DepositTxHash, address indexed from,
+// address indexed to, address l1Token, uint256 amount)
+func decodeZkLegacyDeposit(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	token, ok1 := abiAddress(log.Data, 0)
+	amount, ok2 := abiUint(log.Data, 1)
+	if !ok1 || !ok2 || len(log.Topics) < 4 {
+		return false
+	}
+	transfer.From = topicToAddress(log.Topics[2])
+	transfer.To = topicToAddress(log.Topics[3])
+	transfer.Token = bridgeToken(token)
+	transfer.Amount = amount
+	return true
+}
+
+// WithdrawalFinalized(address indexed to, address indexed l1Token, uint256 amount)
+func decodeZkWithdrawal(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 3 {
+		return false
+	}
+	transfer.To = topicToAddress(log.Topics[1])
+	transfer.Token = bridgeToken(topicToAddress(log.Topics[2]))
+	transfer.Amount = amount
+	return true
+}
+
+// WithdrawalFinalizedSharedBridge(uint256 indexed chainId, address indexed to,
+// address indexed l1Token, uint256 amount)
+func decodeZkSharedWithdrawal(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 4 {
+		return false
+	}
+	transfer.Chain = bridgeChainName(topicToBig(log.Topics[1]))
+	transfer.To = topicToAddress(log.Topics[2])
+	transfer.Token = bridgeToken(topicToAddress(log.Topics[3]))
+	transfer.Amount = amount
+	return true
+}
+
+// LockedERC20(address indexed depositor, address indexed depositReceiver,
+// address indexed rootToken, uint256 amount)
+func decodePolygonLockedERC20(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 4 {
+		return false
+	}
+	transfer.From = topicToAddress(log.Topics[1])
+	transfer.To = topicToAddress(log.Topics[2])
+	transfer.Token = topicToAddress(log.Topics[3])
+	transfer.Amount = amount
+	return true
+}
+
+// LockedEther(address indexed depositor, address indexed depositReceiver, uint256 amount)
+func decodePolygonLockedEther(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 3 {
+		return false
+	}
+	transfer.From = topicToAddress(log.Topics[1])
+	transfer.To = topicToAddress(log.Topics[2])
+	transfer.Amount = amount
+	return true
+}
+
+// ExitedEther(address indexed exitor, uint256 amount)
+func decodePolygonExitedEther(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 2 {
+		return false
+	}
+	transfer.To = topicToAddress(log.Topics[1])
+	transfer.Amount = amount
+	return true
+}
+
+// ExitedERC20(address indexed exitor, address indexed rootToken, uint256 amount)
+func decodePolygonExitedERC20(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 3 {
+		return false
+	}
+	transfer.To = topicToAddress(log.Topics[1])
+	transfer.Token = topicToAddress(log.Topics[2])
+	transfer.Amount = amount
+	return true
+}
+
+// V3FundsDeposited / FundsDeposited: destinationChainId, depositId and depositor
+// are indexed; data starts with inputToken, outputToken, inputAmount, outputAmount,
+// quoteTimestamp, fillDeadline, exclusivityDeadline, recipient. The newer event
+// widens addresses to bytes32, which abiAddress reads the same way.
+func decodeAcrossDeposit(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	token, ok1 := abiAddress(log.Data, 0)
+	amount, ok2 := abiUint(log.Data, 2)
+	to, ok3 := abiAddress(log.Data, 7)
+	if !ok1 || !ok2 || !ok3 || len(log.Topics) < 4 {
+		return false
+	}
+	transfer.Chain = bridgeChainName(topicToBig(log.Topics[1]))
+	transfer.From = topicToAddress(log.Topics[3])
+	transfer.To = to
+	transfer.Token = token
+	transfer.Amount = amount
+	return true
+}
+
+// FilledV3Relay: originChainId, depositId and relayer are indexed; data holds
+// inputToken, outputToken, inputAmount, outputAmount, repaymentChainId,
+// fillDeadline, exclusivityDeadline, exclusiveRelayer, depositor, recipient.
+func decodeAcrossFill(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	token, ok1 := abiAddress(log.Data, 1)
+	amount, ok2 := abiUint(log.Data, 3)
+	from, ok3 := abiAddress(log.Data, 8)
+	to, ok4 := abiAddress(log.Data, 9)
+	if !ok1 || !ok2 || !ok3 || !ok4 || len(log.Topics) < 2 {
+		return false
+	}
+	transfer.Chain = bridgeChainName(topicToBig(log.Topics[1]))
+	transfer.From = from
+	transfer.To = to
+	transfer.Token = token
+	transfer.Amount = amount
+	return true
+}
+
+// TransferSentToL2(uint256 indexed chainId, address indexed recipient, uint256 amount,
+// uint256 amountOutMin, uint256 deadline, address indexed relayer, uint256 relayerFee)
+func decodeHopTransfer(tx domain.Tx, log domain.Log, transfer *domain.BridgeTransfer) bool {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 3 {
+		return false
+	}
+	transfer.Chain = bridgeChainName(topicToBig(log.Topics[1]))
+	transfer.From = strings.ToLower(tx.From)
+	transfer.To = topicToAddress(log.Topics[2])
+	transfer.Amount = amount
+	return true
+}
@@ -0,0 +1,130 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+const (
+	opBridge        = "0x99c9fc46f92e8a1c0dec1b1747d010903e884be1"
+	arbInbox        = "0x4dbd4fc535ac27206064b68ffcf827b0a60bab3f"
+	arbGateway      = "0xa3a7b6f88361f48403514059f1f16c8e78d60eec"
+	hopBridge       = "0xb8901acb165ed027e32754e0ffe830802919727f"
+	testUser        = "0x1111111111111111111111111111111111111111"
+	testRecipient   = "0x2222222222222222222222222222222222222222"
+	testBridgeToken = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+)
+
+var testBridges = []domain.BridgeContract{
+	{Name: "optimism-standard-bridge", Protocol: domain.BridgeProtocolOPStandard, Address: opBridge, Chain: "optimism"},
+	{Name: "arbitrum-inbox", Protocol: domain.BridgeProtocolArbitrumInbox, Address: arbInbox, Chain: "arbitrum"},
+	{Name: "arbitrum-erc20-gateway", Protocol: domain.BridgeProtocolArbitrumGateway, Address: arbGateway, Chain: "arbitrum"},
+	{Name: "hop", Protocol: domain.BridgeProtocolHop, Address: hopBridge},
+}
+
+func addrTopic(addr string) string {
+	return "0x000000000000000000000000" + addr[2:]
+}
+
+func intTopic(v int64) string {
+	return fmt.Sprintf("0x%064x", v)
+}
+
+func TestBridgeLogResolver(t *testing.T) {
+	opDeposit := domain.Log{
+		Address: opBridge,
+		Topics:  []string{opETHDepositInitiatedTopic, addrTopic(testUser), addrTopic(testRecipient)},
+		Data:    words(1000, 64, 0),
+	}
+	// The inbox message carries a retryable ticket: destination, L2 call value, deposit.
+	ticket := words(testRecipient, 0, 500)
+	inboxMessage := domain.Log{
+		Address: arbInbox,
+		Topics:  []string{arbInboxMessageDeliveredTopic, intTopic(7)},
+		Data:    words(32, len(ticket), ticket),
+	}
+	gatewayDeposit := domain.Log{
+		Address: arbGateway,
+		Topics:  []string{arbGatewayDepositInitiatedTopic, addrTopic(testUser), addrTopic(testRecipient), intTopic(7)},
+		Data:    words(testBridgeToken, 250),
+	}
+	hopTransfer := domain.Log{
+		Address: hopBridge,
+		Topics:  []string{hopTransferSentToL2Topic, intTopic(10), addrTopic(testRecipient), addrTopic(testUser)},
+		Data:    words(900, 890, 1700000000, 10),
+	}
+
+	tests := []struct {
+		name    string
+		current domain.ClassificationType
+		logs    []domain.Log
+		want    domain.ClassificationType
+		bridge  domain.BridgeTransfer
+	}{
+		{
+			name:    "OP ETH deposit via receive",
+			current: domain.ClassificationTransfer,
+			logs:    []domain.Log{opDeposit},
+			want:    domain.ClassificationBridgeDeposit,
+			bridge:  domain.BridgeTransfer{Bridge: "optimism-standard-bridge", Chain: "optimism", From: testUser, To: testRecipient, Amount: big.NewInt(1000)},
+		},
+		{
+			name:    "Arbitrum gateway beats the inbox fallback",
+			current: domain.ClassificationContractCall,
+			logs:    []domain.Log{inboxMessage, gatewayDeposit},
+			want:    domain.ClassificationBridgeDeposit,
+			bridge:  domain.BridgeTransfer{Bridge: "arbitrum-erc20-gateway", Chain: "arbitrum", Token: testBridgeToken, From: testUser, To: testRecipient, Amount: big.NewInt(250)},
+		},
+		{
+			name:    "Arbitrum inbox alone",
+			current: domain.ClassificationContractCall,
+			logs:    []domain.Log{inboxMessage},
+			want:    domain.ClassificationBridgeDeposit,
+			bridge:  domain.BridgeTransfer{Bridge: "arbitrum-inbox", Chain: "arbitrum", From: testUser, To: testRecipient, Amount: big.NewInt(500)},
+		},
+		{
+			name:    "Hop ETH send",
+			current: domain.ClassificationTransfer,
+			logs:    []domain.Log{hopTransfer},
+			want:    domain.ClassificationBridgeDeposit,
+			bridge:  domain.BridgeTransfer{Bridge: "hop", Chain: "optimism", From: testUser, To: testRecipient, Amount: big.NewInt(900)},
+		},
+		{
+			name:    "event from an unknown emitter",
+			current: domain.ClassificationContractCall,
+			logs:    []domain.Log{{Address: testRecipient, Topics: opDeposit.Topics, Data: opDeposit.Data}},
+		},
+		{
+			name:    "already classified",
+			current: domain.ClassificationDexSwap,
+			logs:    []domain.Log{opDeposit},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := domain.Tx{From: testUser, Logs: tt.logs}
+			res, ok, err := BridgeLogResolver{Bridges: testBridges}.Resolve(context.Background(), tx, domain.TxResult{Type: tt.current})
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			if ok != (tt.want != "") {
+				t.Fatalf("matched = %v, want %v", ok, tt.want != "")
+			}
+			if !ok {
+				return
+			}
+			if res.Type != tt.want || res.Bridge == nil {
+				t.Fatalf("result = %s %+v", res.Type, res.Bridge)
+			}
+			got := *res.Bridge
+			if got.Bridge != tt.bridge.Bridge || got.Chain != tt.bridge.Chain || got.Token != tt.bridge.Token ||
+				got.From != tt.bridge.From || got.To != tt.bridge.To || got.Amount.Cmp(tt.bridge.Amount) != 0 {
+				t.Fatalf("bridge = %+v, want %+v", got, tt.bridge)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package classifier
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Minimal ABI readers over calldata arguments (selector already stripped).
+// They report ok=false instead of panicking on truncated input, since
+// calldata of pending or arbitrary txs is untrusted.
+
+func abiWord(args []byte, i int) ([]byte, bool) {
+	start := i * 32
+	if i < 0 || start+32 > len(args) {
+		return nil, false
+	}
+	return args[start : start+32], true
+}
+
+func abiUint(args []byte, i int) (*big.Int, bool) {
+	word, ok := abiWord(args, i)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Int).SetBytes(word), true
+}
+
+func abiAddress(args []byte, i int) (string, bool) {
+	word, ok := abiWord(args, i)
+	if !ok {
+		return "", false
+	}
+	return strings.ToLower(common.BytesToAddress(word[12:]).Hex()), true
+}
+
+func abiOffset(args []byte, i int) (int, bool) {
+	v, ok := abiUint(args, i)
+	if !ok || !v.IsInt64() || v.Int64() > int64(len(args)) {
+		return 0, false
+	}
+	return int(v.Int64()), true
+}
+
+func abiAddressArray(args []byte, i int) ([]string, bool) {
+	offset, ok := abiOffset(args, i)
+	if !ok {
+		return nil, false
+	}
+	body := args[offset:]
+	n, ok := abiUint(body, 0)
+	if !ok || n.Cmp(big.NewInt(int64(len(body)/32))) > 0 {
+		return nil, false
+	}
+	out := make([]string, 0, n.Int64())
+	for j := 1; j <= int(n.Int64()); j++ {
+		addr, ok := abiAddress(body, j)
+		if !ok {
+			return nil, false
+		}
+		out = append(out, addr)
+	}
+	return out, true
+}
+
+// abiBytes reads a dynamic bytes argument whose head sits at word i of the
+// given tuple/argument block.
+func abiBytes(args []byte, i int) ([]byte, bool) {
+	offset, ok := abiOffset(args, i)
+	if !ok {
+		return nil, false
+	}
+	return abiBytesBody(args[offset:])
+}
+
+// abiBytesBody reads a length-prefixed bytes value starting at body[0].
+func abiBytesBody(body []byte) ([]byte, bool) {
+	n, ok := abiUint(body, 0)
+	if !ok || n.Cmp(big.NewInt(int64(len(body)-32))) > 0 {
+		return nil, false
+	}
+	return body[32 : 32+n.Int64()], true
+}
+
+// abiDynamicArray reads an array of dynamic elements (bytes or tuples with
+// dynamic fields) and returns each element's encoding, which runs to the end
+// of the array since offsets inside an element are relative to its start.
+func abiDynamicArray(args []byte, i int) ([][]byte, bool) {
+	offset, ok := abiOffset(args, i)
+	if !ok {
+		return nil, false
+	}
+	body := args[offset:]
+	n, ok := abiUint(body, 0)
+	if !ok || n.Cmp(big.NewInt(int64(len(body)/32))) > 0 {
+		return nil, false
+	}
+	heads := body[32:]
+	out := make([][]byte, 0, n.Int64())
+	next := int(n.Int64()) * 32
+	for j := 0; j < int(n.Int64()); j++ {
+		// Offsets must point past the heads and strictly increase, so
+		// elements cannot alias each other.
+		elem, ok := abiOffset(heads, j)
+		if !ok || elem < next {
+			return nil, false
+		}
+		next = elem + 32
+		out = append(out, heads[elem:])
+	}
+	return out, true
+}
+
+func abiUintArray(args []byte, i int) ([]*big.Int, bool) {
+	offset, ok := abiOffset(args, i)
+	if !ok {
+		return nil, false
+	}
+	body := args[offset:]
+	n, ok := abiUint(body, 0)
+	if !ok || n.Cmp(big.NewInt(int64(len(body)/32))) > 0 {
+		return nil, false
+	}
+	out := make([]*big.Int, 0, n.Int64())
+	for j := 1; j <= int(n.Int64()); j++ {
+		v, ok := abiUint(body, j)
+		if !ok {
+			return nil, false
+		}
+		out = append(out, v)
+	}
+	return out, true
+}
@@ -0,0 +1,146 @@
+package classifier
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// words joins 32-byte words: ints, left-padded hex strings and raw bytes.
+func words(parts ...any) []byte {
+	var out []byte
+	for _, p := range parts {
+		switch v := p.(type) {
+		case int:
+			out = append(out, big.NewInt(int64(v)).FillBytes(make([]byte, 32))...)
+		case string:
+			raw, err := hex.DecodeString(strings.TrimPrefix(v, "0x"))
+			if err != nil {
+				panic(err)
+			}
+			out = append(out, make([]byte, 32-len(raw))...)
+			out = append(out, raw...)
+		case []byte:
+			out = append(out, v...)
+		}
+	}
+	return out
+}
+
+func hugeWord() string {
+	return "0x" + strings.Repeat("ff", 32)
+}
+
+func TestAbiAddressArray(t *testing.T) {
+	const a, b = "0x1111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222"
+	tests := []struct {
+		name string
+		args []byte
+		want []string
+		ok   bool
+	}{
+		{"two addresses", words(32, 2, a, b), []string{a, b}, true},
+		{"empty", words(32, 0), []string{}, true},
+		{"truncated", words(32, 2, a), nil, false},
+		{"length beyond input", words(32, 1<<59), nil, false},
+		{"length beyond int64", words(32, hugeWord()), nil, false},
+		{"offset beyond input", words(4096), nil, false},
+		{"missing head", nil, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := abiAddressArray(tt.args, 0)
+			if ok != tt.ok || (ok && !reflect.DeepEqual(got, tt.want)) {
+				t.Fatalf("abiAddressArray = %v, %v; want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAbiBytes(t *testing.T) {
+	payload := []byte("hello")
+	padded := append(append([]byte{}, payload...), make([]byte, 27)...)
+	tests := []struct {
+		name string
+		args []byte
+		want []byte
+		ok   bool
+	}{
+		{"bytes", words(32, 5, padded), payload, true},
+		{"empty", words(32, 0), []byte{}, true},
+		{"length beyond input", words(32, 64, padded), nil, false},
+		{"length 2^59", words(32, 1<<59), nil, false},
+		{"length beyond int64", words(32, hugeWord()), nil, false},
+		{"offset beyond int64", words(hugeWord()), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := abiBytes(tt.args, 0)
+			if ok != tt.ok || (ok && !bytes.Equal(got, tt.want)) {
+				t.Fatalf("abiBytes = %x, %v; want %x, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAbiUintArray(t *testing.T) {
+	got, ok := abiUintArray(words(32, 3, 1, 2, 3), 0)
+	if !ok || len(got) != 3 || got[2].Int64() != 3 {
+		t.Fatalf("abiUintArray = %v, %v", got, ok)
+	}
+	if _, ok := abiUintArray(words(32, 1<<59), 0); ok {
+		t.Fatal("abiUintArray accepted a length beyond the input")
+	}
+}
+
+func TestAbiDynamicArray(t *testing.T) {
+	// bytes[] {"ab", "cd"}: heads 0x40 and 0x80 relative to the heads.
+	elem := func(s string) []byte {
+		return words(len(s), append([]byte(s), make([]byte, 32-len(s))...))
+	}
+	valid := words(32, 2, 64, 128, elem("ab"), elem("cd"))
+	got, ok := abiDynamicArray(valid, 0)
+	if !ok || len(got) != 2 {
+		t.Fatalf("abiDynamicArray = %d elements, %v", len(got), ok)
+	}
+	for i, want := range []string{"ab", "cd"} {
+		if body, ok := abiBytesBody(got[i]); !ok || string(body) != want {
+			t.Fatalf("element %d = %q, %v", i, body, ok)
+		}
+	}
+
+	if _, ok := abiDynamicArray(words(32, 1<<59), 0); ok {
+		t.Fatal("abiDynamicArray accepted a length beyond the input")
+	}
+	if _, ok := abiDynamicArray(words(32, 1, 4096), 0); ok {
+		t.Fatal("abiDynamicArray accepted an element offset beyond the input")
+	}
+	if _, ok := abiDynamicArray(words(32, 2, 64, 64, elem("ab")), 0); ok {
+		t.Fatal("abiDynamicArray accepted aliased elements")
+	}
+	if _, ok := abiDynamicArray(words(32, 2, 128, 64, elem("ab"), elem("cd")), 0); ok {
+		t.Fatal("abiDynamicArray accepted decreasing offsets")
+	}
+	if _, ok := abiDynamicArray(words(32, 2, 32, 64, elem("ab")), 0); ok {
+		t.Fatal("abiDynamicArray accepted an offset into the heads")
+	}
+}
+
+func FuzzAbiDecoders(f *testing.F) {
+	f.Add(words(32, 2, "0x11", "0x22"))
+	f.Add(words(32, 1<<59))
+	f.Add(words(32, hugeWord()))
+	f.Add(words(32, 2, 64, 128, 2, "0x6162", 2, "0x6364"))
+	f.Fuzz(func(t *testing.T, args []byte) {
+		for i := 0; i < 3; i++ {
+			abiAddressArray(args, i)
+			abiBytes(args, i)
+			abiUintArray(args, i)
+			abiDynamicArray(args, i)
+			abiOffset(args, i)
+		}
+	})
+}
@@ -26,6 +26,7 @@ func (DeployClassifier) Classify(ctx context.Context, tx domain.Tx) (domain.TxRe
 	return domain.TxResult{
 		Type:     domain.ClassificationDeploy,
 		Selector: selectorHex(tx.Data),
+		Evidence: &domain.Evidence{Rule: "tx has no recipient"},
 	}, true, nil
 }
 
@@ -41,6 +42,7 @@ func (NativeTransferClassifier) Classify(ctx context.Context, tx domain.Tx) (dom
 	return domain.TxResult{
 		Type:     domain.ClassificationTransfer,
 		Selector: "",
+		Evidence: &domain.Evidence{Rule: "positive value without calldata"},
 	}, true, nil
 }
 
@@ -58,6 +60,7 @@ func (ContractCallClassifier) Classify(ctx context.Context, tx domain.Tx) (domai
 	return domain.TxResult{
 		Type:     domain.ClassificationContractCall,
 		Selector: selector,
+		Evidence: &domain.Evidence{Rule: "calldata with function selector", Selector: selector},
 	}, true, nil
 }
 
@@ -80,11 +83,13 @@ func (ERC20LogResolver) Resolve(ctx context.Context, tx domain.Tx, current domai
 		if log.Topics[0] == transferEventTopic && len(log.Topics) == 3 {
 			updated := current
 			updated.Type = erc20TypeFromSelector(current.Selector, domain.ClassificationERC20Transfer)
+			updated.Evidence = logEvidence("ERC20 Transfer event", current.Selector, log)
 			return updated, true, nil
 		}
 		if log.Topics[0] == approvalEventTopic && len(log.Topics) == 3 {
 			updated := current
 			updated.Type = erc20TypeFromSelector(current.Selector, domain.ClassificationERC20Approve)
+			updated.Evidence = logEvidence("ERC20 Approval event", current.Selector, log)
 			return updated, true, nil
 		}
 	}
@@ -106,18 +111,21 @@ func (ERC721LogResolver) Resolve(ctx context.Context, tx domain.Tx, current doma
 			if len(log.Topics) == 4 {
 				updated := current
 				updated.Type = domain.ClassificationERC721Transfer
+				updated.Evidence = logEvidence("ERC721 Transfer event", current.Selector, log)
 				return updated, true, nil
 			}
 		case approvalEventTopic:
 			if len(log.Topics) == 4 {
 				updated := current
 				updated.Type = domain.ClassificationERC721Approval
+				updated.Evidence = logEvidence("ERC721 Approval event", current.Selector, log)
 				return updated, true, nil
 			}
 		case approvalForAllEventTopic:
 			if len(log.Topics) == 3 {
 				updated := current
 				updated.Type = domain.ClassificationERC721ApprovalForAll
+				updated.Evidence = logEvidence("ApprovalForAll event", current.Selector, log)
 				return updated, true, nil
 			}
 		}
@@ -125,9 +133,14 @@ func (ERC721LogResolver) Resolve(ctx context.Context, tx domain.Tx, current doma
 	return current, false, nil
 }
 
-type DexSwapLogResolver struct{}
+// DexSwapLogResolver detects Uniswap V2/V3 style Swap events. Dexes, when
+// set, names the swap after the deployment whose router the tx called, so
+// forks emitting the same event are not all reported as Uniswap.
+type DexSwapLogResolver struct {
+	Dexes []domain.DexDeployment
+}
 
-func (DexSwapLogResolver) Resolve(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+func (r DexSwapLogResolver) Resolve(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
 	if current.Type != domain.ClassificationContractCall && current.Type != domain.ClassificationUnknown {
 		return current, false, nil
 	}
@@ -141,26 +154,49 @@ func (DexSwapLogResolver) Resolve(ctx context.Context, tx domain.Tx, current dom
 			if !ok {
 				continue
 			}
+			swap.Dex = r.dexName(tx, swap.Dex)
 			updated := current
 			updated.Type = domain.ClassificationDexSwap
 			updated.Swap = swap
 			updated.Details = formatSwapDetails(*swap)
+			updated.Evidence = logEvidence("Uniswap V2 Swap event", current.Selector, log)
 			return updated, true, nil
 		case uniswapV3SwapTopic:
 			swap, ok := parseUniswapV3Swap(log)
 			if !ok {
 				continue
 			}
+			swap.Dex = r.dexName(tx, swap.Dex)
 			updated := current
 			updated.Type = domain.ClassificationDexSwap
 			updated.Swap = swap
 			updated.Details = formatSwapDetails(*swap)
+			updated.Evidence = logEvidence("Uniswap V3 Swap event", current.Selector, log)
 			return updated, true, nil
 		}
 	}
 	return current, false, nil
 }
 
+// dexName returns the deployment whose router received the tx, provided it
+// speaks the protocol of the decoded event; otherwise the protocol itself.
+func (r DexSwapLogResolver) dexName(tx domain.Tx, protocol string) string {
+	if tx.To == nil {
+		return protocol
+	}
+	for _, dex := range r.Dexes {
+		if dex.Protocol != protocol {
+			continue
+		}
+		for _, router := range dex.Routers {
+			if strings.EqualFold(router, *tx.To) {
+				return dex.Name
+			}
+		}
+	}
+	return protocol
+}
+
 func erc20TypeFromSelector(selector string, fallback domain.ClassificationType) domain.ClassificationType {
 	if classType, ok := erc20SelectorMap[selector]; ok {
 		return classType
@@ -243,6 +279,17 @@ func parseUniswapV3Swap(log domain.Log) (*domain.SwapInfo, bool) {
 	}, true
 }
 
+func logEvidence(rule, selector string, log domain.Log) *domain.Evidence {
+	index := log.Index
+	return &domain.Evidence{
+		Rule:     rule,
+		Selector: selector,
+		Topic:    log.Topics[0],
+		Address:  strings.ToLower(log.Address),
+		LogIndex: &index,
+	}
+}
+
 func topicToAddress(topic string) string {
 	if len(topic) < 42 {
 		return ""
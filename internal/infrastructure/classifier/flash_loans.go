@@ -0,0 +1,355 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+const (
+	aaveV3FlashLoanTopic = "0xefefaba5e921573100900a3ad9cf29f222d995fb3b6045797eaea7521bd8d6f0"
+	aaveV2FlashLoanTopic = "0x631042c832b07452973831137f2d73e395028b44b250dedc5abb0ee766e168ac"
+	morphoFlashLoanTopic = "0xc76f1b4fe4396ac07a9fa55a415d4ca430e72651d37d3401f3bed7cb13fc4f12"
+	// Balancer V2 and Maker DssFlash both emit FlashLoan(address,address,uint256,uint256),
+	// indexing different arguments.
+	flashLoanTopic       = "0x0d7d75e01ab95780d3cd1c8ec0dd6c2ce19e3a20427eec8bf53283b6fb8e95f0"
+	uniswapV3FlashTopic  = "0xbdbdb71d7860376ba52b25a5028beea23581364a40522f6bcfb86bb1f2dca633"
+	dydxLogWithdrawTopic = "0xbc83c08f0b269b1726990c8348ffdf1ae1696244a14868d766e542a2f18cd7d4"
+	dydxLogDepositTopic  = "0x2bad8bc95088af2c247b30fa2b2e6a0886f88625e0945cd3051008e0e270198f"
+	dydxLogCallTopic     = "0xab38cdc4a831ebe6542bf277d36b65dbc5c66a4d03ec6cf56ac38de05dc30098"
+
+	uniswapV3FlashProvider = "uniswap-v3"
+)
+
+// dydxMarkets names the tokens of the dYdX SoloMargin market ids, which
+// only exists on Ethereum.
+var dydxMarkets = map[uint64]string{
+	0: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", // WETH
+	1: "0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359", // SAI
+	2: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", // USDC
+	3: "0x6b175474e89094c44da98b954eedeac495271d0f", // DAI
+}
+
+type flashEvent struct {
+	rule   string
+	decode func(tx domain.Tx, provider string, log domain.Log) ([]domain.FlashLoan, bool)
+}
+
+// flashEvents is keyed by the protocol of the lending market or flash
+// lender that emitted the log, and then by topic.
+var flashEvents = map[string]map[string]flashEvent{
+	domain.LendingProtocolAaveV3:     {aaveV3FlashLoanTopic: {rule: "Aave V3 FlashLoan event", decode: decodeAaveV3FlashLoan}},
+	domain.LendingProtocolAaveV2:     {aaveV2FlashLoanTopic: {rule: "Aave V2 FlashLoan event", decode: decodeAaveV2FlashLoan}},
+	domain.LendingProtocolMorphoBlue: {morphoFlashLoanTopic: {rule: "Morpho Blue FlashLoan event", decode: decodeMorphoFlashLoan}},
+	domain.FlashProtocolBalancerV2:   {flashLoanTopic: {rule: "Balancer Vault FlashLoan event", decode: decodeBalancerFlashLoan}},
+	domain.FlashProtocolMakerFlash:   {flashLoanTopic: {rule: "Maker DssFlash FlashLoan event", decode: decodeMakerFlashLoan}},
+	domain.FlashProtocolDydx:         {dydxLogWithdrawTopic: {rule: "dYdX LogWithdraw, LogCall and LogDeposit events", decode: decodeDydxFlashLoan}},
+}
+
+type flashMatch struct {
+	rule string
+	log  domain.Log
+}
+
+// flashLoanSources finds the flash loans of a tx. Logs of the lending
+// markets and flash lenders are trusted by address; Uniswap V3 Flash events
+// are taken from any emitter, like the Swap events of DexSwapLogResolver.
+type flashLoanSources struct {
+	markets []domain.LendingMarket
+	lenders []domain.FlashLender
+}
+
+func (s flashLoanSources) find(tx domain.Tx) ([]domain.FlashLoan, []flashMatch) {
+	var loans []domain.FlashLoan
+	var matches []flashMatch
+	for _, log := range tx.Logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		var event flashEvent
+		var provider string
+		if log.Topics[0] == uniswapV3FlashTopic {
+			event = flashEvent{rule: "Uniswap V3 Flash event", decode: decodeUniswapV3Flash}
+			provider = uniswapV3FlashProvider
+		} else {
+			protocol, name, ok := s.source(log.Address)
+			if !ok {
+				continue
+			}
+			if event, ok = flashEvents[protocol][log.Topics[0]]; !ok {
+				continue
+			}
+			provider = name
+		}
+		found, ok := event.decode(tx, provider, log)
+		if !ok {
+			continue
+		}
+		loans = append(loans, found...)
+		matches = append(matches, flashMatch{rule: event.rule, log: log})
+	}
+	return loans, matches
+}
+
+func (s flashLoanSources) source(address string) (protocol, name string, ok bool) {
+	for _, market := range s.markets {
+		if strings.EqualFold(market.Address, address) {
+			return market.Protocol, market.Name, true
+		}
+	}
+	for _, lender := range s.lenders {
+		if strings.EqualFold(lender.Address, address) {
+			return lender.Protocol, lender.Name, true
+		}
+	}
+	return "", "", false
+}
+
+// FlashLoanLogResolver classifies a tx as FLASH_LOAN when a flash loan is
+// all the earlier resolvers could find in it. It runs after the bridge,
+// lending and swap resolvers, so a flash-funded liquidation or arbitrage
+// keeps its type and FlashLoanEnricher adds the loans as a tag.
+type FlashLoanLogResolver struct {
+	Markets []domain.LendingMarket
+	Lenders []domain.FlashLender
+}
+
+func (r FlashLoanLogResolver) Resolve(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+	if current.Type != domain.ClassificationContractCall && current.Type != domain.ClassificationUnknown {
+		return current, false, nil
+	}
+	loans, matches := flashLoanSources{markets: r.Markets, lenders: r.Lenders}.find(tx)
+	if len(loans) == 0 {
+		return current, false, nil
+	}
+	updated := current
+	updated.Type = domain.ClassificationFlashLoan
+	updated.Flash = loans
+	updated.Details = formatFlashLoans(loans)
+	updated.Evidence = logEvidence(matches[0].rule, current.Selector, matches[0].log)
+	return updated, true, nil
+}
+
+// FlashLoanEnricher attaches the flash loans of a tx to its result whatever
+// the type, so swaps, liquidations and other calls paid for with borrowed
+// funds can be told apart.
+type FlashLoanEnricher struct {
+	Markets []domain.LendingMarket
+	Lenders []domain.FlashLender
+}
+
+func (e FlashLoanEnricher) Enrich(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+	loans, _ := flashLoanSources{markets: e.Markets, lenders: e.Lenders}.find(tx)
+	if len(loans) == 0 {
+		return current, false, nil
+	}
+	updated := current
+	updated.Flash = loans
+	return updated, true, nil
+}
+
+func formatFlashLoans(loans []domain.FlashLoan) string {
+	parts := make([]string, 0, len(loans))
+	for _, f := range loans {
+		asset := f.Asset
+		if asset == "" {
+			asset = "of an unknown token"
+		}
+		part := fmt.Sprintf("%s flash loan %s %s from %s to %s", f.Provider, formatOptional(f.Amount), asset, f.Lender, f.Borrower)
+		if f.Fee != nil {
+			part += fmt.Sprintf(" (fee %s)", f.Fee)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FlashLoan(address indexed target, address initiator, address indexed asset,
+// uint256 amount, uint8 interestRateMode, uint256 premium, uint16 indexed referralCode)
+func decodeAaveV3FlashLoan(tx domain.Tx, provider string, log domain.Log) ([]domain.FlashLoan, bool) {
+	amount, ok1 := abiUint(log.Data, 1)
+	premium, ok2 := abiUint(log.Data, 3)
+	if !ok1 || !ok2 || len(log.Topics) < 3 {
+		return nil, false
+	}
+	return []domain.FlashLoan{{
+		Provider: provider,
+		Lender:   strings.ToLower(log.Address),
+		Borrower: topicToAddress(log.Topics[1]),
+		Asset:    topicToAddress(log.Topics[2]),
+		Amount:   amount,
+		Fee:      premium,
+	}}, true
+}
+
+// FlashLoan(address indexed target, address indexed initiator, address indexed asset,
+// uint256 amount, uint256 premium, uint16 referralCode)
+func decodeAaveV2FlashLoan(tx domain.Tx, provider string, log domain.Log) ([]domain.FlashLoan, bool) {
+	amount, ok1 := abiUint(log.Data, 0)
+	premium, ok2 := abiUint(log.Data, 1)
+	if !ok1 || !ok2 || len(log.Topics) < 4 {
+		return nil, false
+	}
+	return []domain.FlashLoan{{
+		Provider: provider,
+		Lender:   strings.ToLower(log.Address),
+		Borrower: topicToAddress(log.Topics[1]),
+		Asset:    topicToAddress(log.Topics[3]),
+		Amount:   amount,
+		Fee:      premium,
+	}}, true
+}
+
+// FlashLoan(address indexed caller, address indexed token, uint256 assets).
+// Morpho Blue flash loans are free.
+func decodeMorphoFlashLoan(tx domain.Tx, provider string, log domain.Log) ([]domain.FlashLoan, bool) {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 3 {
+		return nil, false
+	}
+	return []domain.FlashLoan{{
+		Provider: provider,
+		Lender:   strings.ToLower(log.Address),
+		Borrower: topicToAddress(log.Topics[1]),
+		Asset:    topicToAddress(log.Topics[2]),
+		Amount:   amount,
+		Fee:      big.NewInt(0),
+	}}, true
+}
+
+// FlashLoan(IFlashLoanRecipient indexed recipient, IERC20 indexed token, uint256 amount, uint256 feeAmount)
+func decodeBalancerFlashLoan(tx domain.Tx, provider string, log domain.Log) ([]domain.FlashLoan, bool) {
+	amount, ok1 := abiUint(log.Data, 0)
+	fee, ok2 := abiUint(log.Data, 1)
+	if !ok1 || !ok2 || len(log.Topics) < 3 {
+		return nil, false
+	}
+	return []domain.FlashLoan{{
+		Provider: provider,
+		Lender:   strings.ToLower(log.Address),
+		Borrower: topicToAddress(log.Topics[1]),
+		Asset:    topicToAddress(log.Topics[2]),
+		Amount:   amount,
+		Fee:      fee,
+	}}, true
+}
+
+// FlashLoan(address indexed receiver, address token, uint256 amount, uint256 fee),
+// the ERC-3156 flash mint of DAI.
+func decodeMakerFlashLoan(tx domain.Tx, provider string, log domain.Log) ([]domain.FlashLoan, bool) {
+	token, ok1 := abiAddress(log.Data, 0)
+	amount, ok2 := abiUint(log.Data, 1)
+	fee, ok3 := abiUint(log.Data, 2)
+	if !ok1 || !ok2 || !ok3 || len(log.Topics) < 2 {
+		return nil, false
+	}
+	return []domain.FlashLoan{{
+		Provider: provider,
+		Lender:   strings.ToLower(log.Address),
+		Borrower: topicToAddress(log.Topics[1]),
+		Asset:    token,
+		Amount:   amount,
+		Fee:      fee,
+	}}, true
+}
+
+// Flash(address indexed sender, address indexed recipient, uint256 amount0,
+// uint256 amount1, uint256 paid0, uint256 paid1). The event does not name
+// the pool tokens, so each is taken from the pool's transfer of that
+// amount to the recipient.
+func decodeUniswapV3Flash(tx domain.Tx, provider string, log domain.Log) ([]domain.FlashLoan, bool) {
+	var words [4]*big.Int
+	for i := range words {
+		v, ok := abiUint(log.Data, i)
+		if !ok {
+			return nil, false
+		}
+		words[i] = v
+	}
+	if len(log.Topics) < 3 {
+		return nil, false
+	}
+	pool := strings.ToLower(log.Address)
+	recipient := topicToAddress(log.Topics[2])
+	var loans []domain.FlashLoan
+	used := ""
+	for i := 0; i < 2; i++ {
+		if words[i].Sign() == 0 {
+			continue
+		}
+		token := poolTransferToken(tx, pool, recipient, words[i], used)
+		used = token
+		loans = append(loans, domain.FlashLoan{
+			Provider: provider,
+			Lender:   pool,
+			Borrower: recipient,
+			Asset:    token,
+			Amount:   words[i],
+			Fee:      words[i+2],
+		})
+	}
+	return loans, len(loans) > 0
+}
+
+// poolTransferToken returns the token of the first transfer of amount from
+// pool to recipient, skipping the token already matched to the other side.
+func poolTransferToken(tx domain.Tx, pool, recipient string, amount *big.Int, skip string) string {
+	for _, t := range TokenTransfers(tx) {
+		if t.Standard == StandardERC20 && t.From == pool && t.To == recipient && t.Amount.Cmp(amount) == 0 && (skip == "" || t.Token != skip) {
+			return t.Token
+		}
+	}
+	return ""
+}
+
+// LogWithdraw(address indexed accountOwner, uint256 accountNumber, uint256 market,
+// ((bool sign, uint256 value) deltaWei, (bool sign, uint128 value) newPar) update, address to)
+//
+// SoloMargin has no flash loan function: a withdraw followed by a call and a
+// deposit of the same market by the same account is one. An account is the
+// owner and its account number, the first data word of every event. The fee
+// is what the deposit returned beyond the withdrawal.
+func decodeDydxFlashLoan(tx domain.Tx, provider string, log domain.Log) ([]domain.FlashLoan, bool) {
+	account, ok0 := abiUint(log.Data, 0)
+	market, ok1 := abiUint(log.Data, 1)
+	amount, ok2 := abiUint(log.Data, 3)
+	if !ok0 || !ok1 || !ok2 || len(log.Topics) < 2 {
+		return nil, false
+	}
+	called := false
+	for _, later := range tx.Logs {
+		if later.Index <= log.Index || len(later.Topics) < 2 || !strings.EqualFold(later.Address, log.Address) || later.Topics[1] != log.Topics[1] {
+			continue
+		}
+		if laterAccount, ok := abiUint(later.Data, 0); !ok || laterAccount.Cmp(account) != 0 {
+			continue
+		}
+		switch later.Topics[0] {
+		case dydxLogCallTopic:
+			called = true
+		case dydxLogDepositTopic:
+			depositMarket, ok1 := abiUint(later.Data, 1)
+			deposit, ok2 := abiUint(later.Data, 3)
+			if !called || !ok1 || !ok2 || depositMarket.Cmp(market) != 0 {
+				continue
+			}
+			loan := domain.FlashLoan{
+				Provider: provider,
+				Lender:   strings.ToLower(log.Address),
+				Borrower: topicToAddress(log.Topics[1]),
+				Amount:   amount,
+			}
+			if market.IsUint64() {
+				loan.Asset = dydxMarkets[market.Uint64()]
+			}
+			if deposit.Cmp(amount) >= 0 {
+				loan.Fee = new(big.Int).Sub(deposit, amount)
+			}
+			return []domain.FlashLoan{loan}, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,163 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+const (
+	balancerVault = "0xba12222222228d8ba445958a75a0704d566bf2c8"
+	dssFlash      = "0x60744434d6339a6b27d73d9eda62b6f66a0a04fa"
+	soloMargin    = "0x1e0447b19bb6ecfdae1e4ae1694b0c3659614e4e"
+	uniswapPool   = "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+	testDAI       = "0x6b175474e89094c44da98b954eedeac495271d0f"
+)
+
+var testLenders = []domain.FlashLender{
+	{Name: "balancer-vault", Protocol: domain.FlashProtocolBalancerV2, Address: balancerVault},
+	{Name: "maker-dss-flash", Protocol: domain.FlashProtocolMakerFlash, Address: dssFlash},
+	{Name: "dydx-solo", Protocol: domain.FlashProtocolDydx, Address: soloMargin},
+}
+
+// loanSummary renders a loan as "provider lender->borrower amount asset fee".
+func loanSummary(f domain.FlashLoan) string {
+	return fmt.Sprintf("%s %s->%s %s %s fee %s", f.Provider, f.Lender, f.Borrower, formatOptional(f.Amount), f.Asset, formatOptional(f.Fee))
+}
+
+func TestFlashLoanLogResolver(t *testing.T) {
+	aaveFlash := domain.Log{
+		Address: aavePool,
+		Topics:  []string{aaveV3FlashLoanTopic, addrTopic(testUser), addrTopic(testUSDC), intTopic(0)},
+		Data:    words(testUser, 1000, 0, 5),
+	}
+	balancerFlash := domain.Log{
+		Address: balancerVault,
+		Topics:  []string{flashLoanTopic, addrTopic(testUser), addrTopic(testWETH)},
+		Data:    words(2000, 0),
+	}
+	// DssFlash emits the same topic with only the receiver indexed.
+	makerFlash := domain.Log{
+		Address: dssFlash,
+		Topics:  []string{flashLoanTopic, addrTopic(testUser)},
+		Data:    words(testDAI, 3000, 0),
+	}
+	poolTransfer := domain.Log{
+		Address: testUSDC,
+		Topics:  []string{transferEventTopic, addrTopic(uniswapPool), addrTopic(testUser)},
+		Data:    words(4000),
+	}
+	uniswapFlash := domain.Log{
+		Address: uniswapPool,
+		Topics:  []string{uniswapV3FlashTopic, addrTopic(testUser), addrTopic(testUser)},
+		Data:    words(4000, 0, 2, 0),
+	}
+	// With equal amounts the second side must not reuse the first's token.
+	poolTransferWETH := domain.Log{
+		Address: testWETH,
+		Topics:  []string{transferEventTopic, addrTopic(uniswapPool), addrTopic(testUser)},
+		Data:    words(4000),
+	}
+	uniswapFlashBoth := domain.Log{
+		Address: uniswapPool,
+		Topics:  []string{uniswapV3FlashTopic, addrTopic(testUser), addrTopic(testUser)},
+		Data:    words(4000, 4000, 2, 3),
+	}
+	dydxLog := func(index uint, topic string, account, market, amount int) domain.Log {
+		return domain.Log{Address: soloMargin, Index: index, Topics: []string{topic, addrTopic(testUser)}, Data: words(account, market, 1, amount)}
+	}
+	dydxWithdraw := dydxLog(1, dydxLogWithdrawTopic, 0, 0, 5000)
+	dydxCall := domain.Log{Address: soloMargin, Index: 2, Topics: []string{dydxLogCallTopic, addrTopic(testUser)}, Data: words(0, testUser)}
+	dydxDeposit := dydxLog(3, dydxLogDepositTopic, 0, 0, 5002)
+	// The same owner repaying into another of its accounts is not a flash loan.
+	dydxOtherAccountDeposit := dydxLog(3, dydxLogDepositTopic, 1, 0, 5002)
+	foreignFlash := balancerFlash
+	foreignFlash.Address = testRecipient
+
+	tests := []struct {
+		name  string
+		logs  []domain.Log
+		loans []string
+	}{
+		{
+			name:  "aave v3",
+			logs:  []domain.Log{aaveFlash},
+			loans: []string{"aave-v3 " + aavePool + "->" + testUser + " 1000 " + testUSDC + " fee 5"},
+		},
+		{
+			name:  "balancer",
+			logs:  []domain.Log{balancerFlash},
+			loans: []string{"balancer-vault " + balancerVault + "->" + testUser + " 2000 " + testWETH + " fee 0"},
+		},
+		{
+			name:  "maker flash mint",
+			logs:  []domain.Log{makerFlash},
+			loans: []string{"maker-dss-flash " + dssFlash + "->" + testUser + " 3000 " + testDAI + " fee 0"},
+		},
+		{
+			name:  "uniswap v3 flash from any pool",
+			logs:  []domain.Log{poolTransfer, uniswapFlash},
+			loans: []string{"uniswap-v3 " + uniswapPool + "->" + testUser + " 4000 " + testUSDC + " fee 2"},
+		},
+		{
+			name:  "dydx withdraw, call and deposit",
+			logs:  []domain.Log{dydxWithdraw, dydxCall, dydxDeposit},
+			loans: []string{"dydx-solo " + soloMargin + "->" + testUser + " 5000 " + testWETH + " fee 2"},
+		},
+		{
+			name: "uniswap v3 flash of both tokens with equal amounts",
+			logs: []domain.Log{poolTransfer, poolTransferWETH, uniswapFlashBoth},
+			loans: []string{
+				"uniswap-v3 " + uniswapPool + "->" + testUser + " 4000 " + testUSDC + " fee 2",
+				"uniswap-v3 " + uniswapPool + "->" + testUser + " 4000 " + testWETH + " fee 3",
+			},
+		},
+		{name: "dydx withdraw without a call", logs: []domain.Log{dydxWithdraw, dydxDeposit}},
+		{name: "dydx withdraw without a deposit", logs: []domain.Log{dydxWithdraw, dydxCall}},
+		{name: "dydx deposit to another account", logs: []domain.Log{dydxWithdraw, dydxCall, dydxOtherAccountDeposit}},
+		{name: "unknown emitter", logs: []domain.Log{foreignFlash}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := domain.Tx{From: testUser, Logs: tt.logs}
+			current := domain.TxResult{Type: domain.ClassificationContractCall}
+			result, ok, err := FlashLoanLogResolver{Markets: testMarkets, Lenders: testLenders}.Resolve(context.Background(), tx, current)
+			if err != nil || ok != (tt.loans != nil) {
+				t.Fatalf("Resolve = %v, %v", ok, err)
+			}
+			if !ok {
+				return
+			}
+			if result.Type != domain.ClassificationFlashLoan {
+				t.Errorf("type = %s", result.Type)
+			}
+			if len(result.Flash) != len(tt.loans) {
+				t.Fatalf("loans = %d, want %d", len(result.Flash), len(tt.loans))
+			}
+			for i, want := range tt.loans {
+				if got := loanSummary(result.Flash[i]); got != want {
+					t.Errorf("loan %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFlashLoanEnricherTagsClassifiedTxs(t *testing.T) {
+	tx := domain.Tx{Logs: []domain.Log{{
+		Address: balancerVault,
+		Topics:  []string{flashLoanTopic, addrTopic(testUser), addrTopic(testWETH)},
+		Data:    words(2000, 0),
+	}}}
+	current := domain.TxResult{Type: domain.ClassificationLendingLiquidation}
+
+	if _, ok, _ := (FlashLoanLogResolver{Lenders: testLenders}).Resolve(context.Background(), tx, current); ok {
+		t.Fatal("resolver replaced the liquidation")
+	}
+	result, ok, err := FlashLoanEnricher{Lenders: testLenders}.Enrich(context.Background(), tx, current)
+	if err != nil || !ok || result.Type != domain.ClassificationLendingLiquidation || len(result.Flash) != 1 {
+		t.Fatalf("Enrich = %s with %d loans, %v, %v", result.Type, len(result.Flash), ok, err)
+	}
+}
@@ -0,0 +1,80 @@
+package classifier
+
+import (
+	"context"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+// SelfDestructClassifier matches txs whose call trace contains a successful
+// SELFDESTRUCT. Since EIP-6780 the opcode only deletes a contract created in
+// the same tx; any other contract keeps its code and just sends its balance
+// to the beneficiary, which is reported as BALANCE_SWEEP. It needs a reader
+// configured with a trace source; without a call tree it never matches.
+type SelfDestructClassifier struct{}
+
+func (SelfDestructClassifier) Classify(ctx context.Context, tx domain.Tx) (domain.TxResult, bool, error) {
+	var found *domain.Call
+	created := map[string]bool{}
+	WalkCalls(tx.Calls, func(call domain.Call, depth int) bool {
+		switch call.Type {
+		case domain.CallTypeCreate, domain.CallTypeCreate2:
+			created[strings.ToLower(call.To)] = true
+		case domain.CallTypeSelfDestruct:
+			found = &call
+			return false
+		}
+		return true
+	})
+	if found == nil {
+		return domain.TxResult{}, false, nil
+	}
+	contract := strings.ToLower(found.From)
+	if created[contract] {
+		return domain.TxResult{
+			Type:     domain.ClassificationSelfDestruct,
+			Selector: selectorHex(tx.Data),
+			Details:  "contract " + contract + " created and self-destructed, beneficiary " + strings.ToLower(found.To),
+			Evidence: &domain.Evidence{
+				Rule:     "SELFDESTRUCT of a contract created in the same tx",
+				Selector: selectorHex(tx.Data),
+				Address:  contract,
+			},
+		}, true, nil
+	}
+	return domain.TxResult{
+		Type:     domain.ClassificationBalanceSweep,
+		Selector: selectorHex(tx.Data),
+		Details:  "contract " + contract + " swept its balance to " + strings.ToLower(found.To) + " via SELFDESTRUCT (code kept, EIP-6780)",
+		Evidence: &domain.Evidence{
+			Rule:     "SELFDESTRUCT of a pre-existing contract",
+			Selector: selectorHex(tx.Data),
+			Address:  contract,
+		},
+	}, true, nil
+}
+
+// WalkCalls visits the successful frames of a call tree depth-first, skipping
+// reverted subtrees. Returning false from visit stops the walk.
+func WalkCalls(root *domain.Call, visit func(call domain.Call, depth int) bool) {
+	if root == nil {
+		return
+	}
+	var walk func(call domain.Call, depth int) bool
+	walk = func(call domain.Call, depth int) bool {
+		if call.Error != "" {
+			return true
+		}
+		if !visit(call, depth) {
+			return false
+		}
+		for _, child := range call.Calls {
+			if !walk(child, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(*root, 0)
+}
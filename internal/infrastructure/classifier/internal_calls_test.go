@@ -0,0 +1,54 @@
+package classifier
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+func TestSelfDestructClassifier(t *testing.T) {
+	const (
+		factory     = "0x1111111111111111111111111111111111111111"
+		contract    = "0x2222222222222222222222222222222222222222"
+		beneficiary = "0x3333333333333333333333333333333333333333"
+	)
+	destruct := domain.Call{Type: domain.CallTypeSelfDestruct, From: contract, To: beneficiary, Value: big.NewInt(5)}
+	tests := []struct {
+		name  string
+		calls *domain.Call
+		want  domain.ClassificationType
+		match bool
+	}{
+		{
+			name: "created in the same tx",
+			calls: &domain.Call{Type: domain.CallTypeCall, To: factory, Calls: []domain.Call{
+				{Type: domain.CallTypeCreate2, From: factory, To: contract, Calls: []domain.Call{destruct}},
+			}},
+			want:  domain.ClassificationSelfDestruct,
+			match: true,
+		},
+		{
+			name:  "pre-existing contract",
+			calls: &domain.Call{Type: domain.CallTypeCall, To: contract, Calls: []domain.Call{destruct}},
+			want:  domain.ClassificationBalanceSweep,
+			match: true,
+		},
+		{
+			name: "reverted frame",
+			calls: &domain.Call{Type: domain.CallTypeCall, To: contract, Calls: []domain.Call{
+				{Type: domain.CallTypeCall, To: contract, Error: "execution reverted", Calls: []domain.Call{destruct}},
+			}},
+		},
+		{name: "no trace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, ok, err := SelfDestructClassifier{}.Classify(context.Background(), domain.Tx{Calls: tt.calls})
+			if err != nil || ok != tt.match || (ok && res.Type != tt.want) {
+				t.Fatalf("Classify = %s, %v, %v; want %s, %v", res.Type, ok, err, tt.want, tt.match)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+// L2TxClassifier matches the rollup-specific tx types that only exist on L2s:
+// OP-stack deposits, Arbitrum L1 messages and retryables, and the system txs
+// both sequencers insert into every block. It must run before the generic
+// classifiers, which would otherwise see a plain call or transfer.
+type L2TxClassifier struct{}
+
+func (L2TxClassifier) Classify(ctx context.Context, tx domain.Tx) (domain.TxResult, bool, error) {
+	l2 := tx.L2
+	if l2 == nil {
+		return domain.TxResult{}, false, nil
+	}
+	result := domain.TxResult{
+		Selector: selectorHex(tx.Data),
+		Evidence: &domain.Evidence{Rule: fmt.Sprintf("tx type 0x%02x (%s)", tx.Type, l2.Kind)},
+	}
+
+	switch {
+	case l2.IsSystem && l2.Kind == domain.L2KindOPDeposit:
+		result.Type = domain.ClassificationL2System
+		result.Details = "OP-stack system deposit (L1 attributes)"
+	case l2.Kind == domain.L2KindArbInternal:
+		result.Type = domain.ClassificationL2System
+		result.Details = "ArbOS internal tx"
+	case l2.Kind == domain.L2KindOPDeposit:
+		result.Type = domain.ClassificationL2Deposit
+		result.Details = fmt.Sprintf("deposit from L1, mint %s wei, source %s", formatOptional(l2.Mint), l2.SourceHash)
+	case l2.Kind == domain.L2KindArbDeposit:
+		result.Type = domain.ClassificationL2Deposit
+		result.Details = fmt.Sprintf("ETH deposit from L1, %s wei to %s, request %s", formatOptional(l2.Mint), recipient(tx), l2.RequestID)
+	case l2.Kind == domain.L2KindArbUnsigned || l2.Kind == domain.L2KindArbContract:
+		result.Type = domain.ClassificationL2Deposit
+		result.Details = fmt.Sprintf("L1-to-L2 message to %s, request %s", recipient(tx), l2.RequestID)
+	case l2.Kind == domain.L2KindArbSubmitRetryable:
+		result.Type = domain.ClassificationL2RetryableSubmit
+		retryTo := "CONTRACT_CREATION"
+		if l2.RetryTo != nil {
+			retryTo = strings.ToLower(*l2.RetryTo)
+		}
+		result.Details = fmt.Sprintf("retryable ticket %s to %s, value %s wei, deposit %s wei, max submission fee %s wei",
+			l2.RequestID, retryTo, formatOptional(l2.RetryValue), formatOptional(l2.DepositValue), formatOptional(l2.MaxSubmissionFee))
+	case l2.Kind == domain.L2KindArbRetry:
+		result.Type = domain.ClassificationL2RetryableRedeem
+		result.Details = fmt.Sprintf("redeem of retryable ticket %s, refund to %s", l2.TicketID, strings.ToLower(l2.RefundTo))
+	default:
+		return domain.TxResult{}, false, nil
+	}
+	return result, true, nil
+}
+
+func recipient(tx domain.Tx) string {
+	if tx.To == nil {
+		return "CONTRACT_CREATION"
+	}
+	return strings.ToLower(*tx.To)
+}
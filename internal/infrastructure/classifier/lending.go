@@ -0,0 +1,555 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+const (
+	aaveV3SupplyTopic          = "0x2b627736bca15cd5381dcf80b0bf11fd197d01a037c52b927a881a10fb73ba61"
+	aaveWithdrawTopic          = "0x3115d1449a7b732c986cba18244e897a450f61e1bb8d589cd2e69e6c8924f9f7"
+	aaveV3BorrowTopic          = "0xb3d084820fb1a9decffb176436bd02558d15fac9b0ddfed8c465bc7359d7dce0"
+	aaveV3RepayTopic           = "0xa534c8dbe71f871f9f3530e97a74601fea17b426cae02e1c5aee42c96c784051"
+	aaveLiquidationCallTopic   = "0xe413a321e8681d831f4dbccbca790d2952b56f977908e45be37335533e005286"
+	aaveV2DepositTopic         = "0xde6857219544bb5b7746f48ed30be6386fefc61b2f864cacf559893bf50fd951"
+	aaveV2BorrowTopic          = "0xc6a898309e823ee50bac64e45ca8adba6690e99e7841c45d754e2a38e9019d9b"
+	aaveV2RepayTopic           = "0x4cdde6e09bb755c9a5589ebaec640bbfedff1362d4b255ebf8339782b9942faa"
+	compoundMintTopic          = "0x4c209b5fc8ad50758f13e2e1088ba56a560dff690a1c6fef26394f4c03821c4f"
+	compoundRedeemTopic        = "0xe5b754fb1abb7f01b499791d0b820ae3b6af3424ac1c59768edb53f4ec31a929"
+	compoundBorrowTopic        = "0x13ed6866d4e1ee6da46f845c46d7e54120883d75c5ea9a2dacc1c4ca8984ab80"
+	compoundRepayBorrowTopic   = "0x1a2a22cb034d26d1854bdc6666a5b91fe25efbbb5dcad3b0355478d6f5c362a1"
+	compoundLiquidateTopic     = "0x298637f684da70674f26509b10f07ec2fbc77a335ab1e7d6215a4b2484d8bb52"
+	cometSupplyTopic           = "0xd1cf3d156d5f8f0d50f6c122ed609cec09d35c9b9fb3fff6ea0959134dae424e"
+	cometWithdrawTopic         = "0x9b1bfa7fa9ee420a16e124f794c35ac9f90472acc99140eb2f6447c714cad8eb"
+	cometSupplyCollateralTopic = "0xfa56f7b24f17183d81894d3ac2ee654e3c26388d17a28dbd9549b8114304e1f4"
+	cometWithdrawCollatTopic   = "0xd6d480d5b3068db003533b170d67561494d72e3bf9fa40a266471351ebba9e16"
+	cometAbsorbDebtTopic       = "0x1547a878dc89ad3c367b6338b4be6a65a5dd74fb77ae044da1e8747ef1f4f62f"
+	cometAbsorbCollateralTopic = "0x9850ab1af75177e4a9201c65a2cf7976d5d28e40ef63494b44366f86b2f9412e"
+	morphoSupplyTopic          = "0xedf8870433c83823eb071d3df1caa8d008f12f6440918c20d75a3602cda30fe0"
+	morphoWithdrawTopic        = "0xa56fc0ad5702ec05ce63666221f796fb62437c32db1aa1aa075fc6484cf58fbf"
+	morphoBorrowTopic          = "0x570954540bed6b1304a87dfe815a5eda4a648f7097a16240dcd85c9b5fd42a43"
+	morphoRepayTopic           = "0x52acb05cebbd3cd39715469f22afbf5a17496295ef3bc9bb5944056c63ccaa09"
+	morphoSupplyCollatTopic    = "0xa3b9472a1399e17e123f3c2e6586c23e504184d504de59cdaa2b375e880c6184"
+	morphoWithdrawCollatTopic  = "0xe80ebd7cc9223d7382aab2e0d1d6155c65651f83d53c8b9b06901d167e321142"
+	morphoLiquidateTopic       = "0xa4946ede45d0c6f06a0f5ce92c9ad3b4751452d2fe0e25010783bcab57a67e41"
+)
+
+type lendingEvent struct {
+	rule   string
+	decode func(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool)
+}
+
+// lendingEvents is keyed by protocol and then topic: Aave V2 and V3 share
+// some topics, and look-alike events of the other protocols must not match.
+var lendingEvents = map[string]map[string]lendingEvent{
+	domain.LendingProtocolAaveV3: {
+		aaveV3SupplyTopic:        {rule: "Aave V3 Supply event", decode: decodeAaveSupply},
+		aaveWithdrawTopic:        {rule: "Aave Withdraw event", decode: decodeAaveWithdraw},
+		aaveV3BorrowTopic:        {rule: "Aave V3 Borrow event", decode: decodeAaveBorrow},
+		aaveV3RepayTopic:         {rule: "Aave V3 Repay event", decode: decodeAaveRepay},
+		aaveLiquidationCallTopic: {rule: "Aave LiquidationCall event", decode: decodeAaveLiquidation},
+	},
+	domain.LendingProtocolAaveV2: {
+		aaveV2DepositTopic:       {rule: "Aave V2 Deposit event", decode: decodeAaveSupply},
+		aaveWithdrawTopic:        {rule: "Aave Withdraw event", decode: decodeAaveWithdraw},
+		aaveV2BorrowTopic:        {rule: "Aave V2 Borrow event", decode: decodeAaveBorrow},
+		aaveV2RepayTopic:         {rule: "Aave V2 Repay event", decode: decodeAaveRepay},
+		aaveLiquidationCallTopic: {rule: "Aave LiquidationCall event", decode: decodeAaveLiquidation},
+	},
+	domain.LendingProtocolCompoundV2: {
+		compoundMintTopic:        {rule: "Compound Mint event", decode: decodeCompoundMint},
+		compoundRedeemTopic:      {rule: "Compound Redeem event", decode: decodeCompoundRedeem},
+		compoundBorrowTopic:      {rule: "Compound Borrow event", decode: decodeCompoundBorrow},
+		compoundRepayBorrowTopic: {rule: "Compound RepayBorrow event", decode: decodeCompoundRepay},
+		compoundLiquidateTopic:   {rule: "Compound LiquidateBorrow event", decode: decodeCompoundLiquidation},
+	},
+	domain.LendingProtocolCompoundV3: {
+		cometSupplyTopic:           {rule: "Comet Supply event", decode: decodeCometSupply},
+		cometWithdrawTopic:         {rule: "Comet Withdraw event", decode: decodeCometWithdraw},
+		cometSupplyCollateralTopic: {rule: "Comet SupplyCollateral event", decode: decodeCometCollateral(domain.LendingActionSupply)},
+		cometWithdrawCollatTopic:   {rule: "Comet WithdrawCollateral event", decode: decodeCometCollateral(domain.LendingActionWithdraw)},
+		cometAbsorbDebtTopic:       {rule: "Comet AbsorbDebt event", decode: decodeCometAbsorbDebt},
+		cometAbsorbCollateralTopic: {rule: "Comet AbsorbCollateral event", decode: decodeCometAbsorbCollateral},
+	},
+	domain.LendingProtocolMorphoBlue: {
+		morphoSupplyTopic:         {rule: "Morpho Blue Supply event", decode: decodeMorphoCallerFirst(domain.LendingActionSupply, false)},
+		morphoRepayTopic:          {rule: "Morpho Blue Repay event", decode: decodeMorphoCallerFirst(domain.LendingActionRepay, false)},
+		morphoSupplyCollatTopic:   {rule: "Morpho Blue SupplyCollateral event", decode: decodeMorphoCallerFirst(domain.LendingActionSupply, true)},
+		morphoWithdrawTopic:       {rule: "Morpho Blue Withdraw event", decode: decodeMorphoReceiver(domain.LendingActionWithdraw, false)},
+		morphoBorrowTopic:         {rule: "Morpho Blue Borrow event", decode: decodeMorphoReceiver(domain.LendingActionBorrow, false)},
+		morphoWithdrawCollatTopic: {rule: "Morpho Blue WithdrawCollateral event", decode: decodeMorphoReceiver(domain.LendingActionWithdraw, true)},
+		morphoLiquidateTopic:      {rule: "Morpho Blue Liquidate event", decode: decodeMorphoLiquidation},
+	},
+}
+
+// lendingPriority picks the tx type among its lending actions: a liquidation
+// usually repays and withdraws too, and a leveraged position supplies before
+// it borrows, so the rarer action describes the tx best.
+var lendingPriority = []struct {
+	action string
+	class  domain.ClassificationType
+}{
+	{domain.LendingActionLiquidation, domain.ClassificationLendingLiquidation},
+	{domain.LendingActionBorrow, domain.ClassificationLendingBorrow},
+	{domain.LendingActionRepay, domain.ClassificationLendingRepay},
+	{domain.LendingActionWithdraw, domain.ClassificationLendingWithdraw},
+	{domain.LendingActionSupply, domain.ClassificationLendingSupply},
+}
+
+// LendingLogResolver detects supplies, withdrawals, borrows, repays and
+// liquidations from the events of the known lending markets. Like
+// BridgeLogResolver it only trusts logs emitted by one of Markets. Every
+// event is kept in Lending and the type follows lendingPriority. Flash
+// loans are left to FlashLoanLogResolver and FlashLoanEnricher.
+type LendingLogResolver struct {
+	Markets []domain.LendingMarket
+}
+
+type lendingMatch struct {
+	name string
+	rule string
+	log  domain.Log
+}
+
+func (r LendingLogResolver) Resolve(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+	if current.Type != domain.ClassificationContractCall && current.Type != domain.ClassificationUnknown {
+		return current, false, nil
+	}
+	if len(r.Markets) == 0 {
+		return current, false, nil
+	}
+
+	var actions []domain.LendingAction
+	var matches []lendingMatch
+	for _, log := range tx.Logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		market, ok := r.market(log.Address)
+		if !ok {
+			continue
+		}
+		event, ok := lendingEvents[market.Protocol][log.Topics[0]]
+		if !ok {
+			continue
+		}
+		action, ok := event.decode(tx, market, log)
+		if !ok || mergeLiquidation(actions, action) {
+			continue
+		}
+		actions = append(actions, action)
+		matches = append(matches, lendingMatch{name: market.Name, rule: event.rule, log: log})
+	}
+	if len(actions) == 0 {
+		return current, false, nil
+	}
+
+	updated := current
+	updated.Lending = actions
+	updated.Details = formatLendingDetails(actions, matches)
+	for _, p := range lendingPriority {
+		for i, action := range actions {
+			if action.Action != p.action {
+				continue
+			}
+			updated.Type = p.class
+			updated.Evidence = logEvidence(matches[i].rule, current.Selector, matches[i].log)
+			return updated, true, nil
+		}
+	}
+	return current, false, nil
+}
+
+func (r LendingLogResolver) market(address string) (domain.LendingMarket, bool) {
+	for _, market := range r.Markets {
+		if strings.EqualFold(market.Address, address) {
+			return market, true
+		}
+	}
+	return domain.LendingMarket{}, false
+}
+
+// mergeLiquidation folds a Comet AbsorbDebt into the first AbsorbCollateral
+// of the same borrower, which Comet emits before it, so one absorb reads as
+// one liquidation. Further collateral assets stay separate liquidations.
+func mergeLiquidation(actions []domain.LendingAction, action domain.LendingAction) bool {
+	if action.Liquidation == nil || action.Liquidation.DebtRepaid == nil || action.Liquidation.CollateralSeized != nil {
+		return false
+	}
+	for i := range actions {
+		prev := actions[i].Liquidation
+		if prev == nil || prev.DebtRepaid != nil || actions[i].Market != action.Market || prev.Borrower != action.Liquidation.Borrower {
+			continue
+		}
+		prev.DebtAsset = action.Liquidation.DebtAsset
+		prev.DebtRepaid = action.Liquidation.DebtRepaid
+		actions[i].Amount = action.Amount
+		return true
+	}
+	return false
+}
+
+func formatLendingDetails(actions []domain.LendingAction, matches []lendingMatch) string {
+	parts := make([]string, 0, len(actions))
+	for i, a := range actions {
+		if l := a.Liquidation; l != nil {
+			part := fmt.Sprintf("%s liquidation of %s by %s:", matches[i].name, l.Borrower, l.Liquidator)
+			if l.DebtRepaid != nil {
+				part += fmt.Sprintf(" repaid %s,", formatLendingAmount(a, l.DebtRepaid, l.DebtAsset))
+			}
+			parts = append(parts, fmt.Sprintf("%s seized %s", part, formatLendingAmount(a, l.CollateralSeized, l.CollateralAsset)))
+			continue
+		}
+		action := a.Action
+		if a.Collateral {
+			action += " collateral"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s for %s", matches[i].name, action, formatLendingAmount(a, a.Amount, a.Asset), a.User))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatLendingAmount names the asset of an amount: Morpho Blue events
+// only carry the market id, and an empty asset elsewhere is the native
+// currency (Compound cETH).
+func formatLendingAmount(a domain.LendingAction, amount *big.Int, asset string) string {
+	switch {
+	case asset != "":
+		return fmt.Sprintf("%s %s", formatOptional(amount), asset)
+	case a.Protocol == domain.LendingProtocolMorphoBlue:
+		return fmt.Sprintf("%s of market %s", formatOptional(amount), a.Market)
+	default:
+		return fmt.Sprintf("%s wei", formatOptional(amount))
+	}
+}
+
+func newLendingAction(market domain.LendingMarket, log domain.Log, action string) domain.LendingAction {
+	return domain.LendingAction{
+		Protocol: market.Protocol,
+		Market:   strings.ToLower(log.Address),
+		Action:   action,
+		Asset:    strings.ToLower(market.Asset),
+	}
+}
+
+// Supply (V3) / Deposit (V2)(address indexed reserve, address user,
+// address indexed onBehalfOf, uint256 amount, uint16 indexed referralCode)
+func decodeAaveSupply(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	caller, ok1 := abiAddress(log.Data, 0)
+	amount, ok2 := abiUint(log.Data, 1)
+	if !ok1 || !ok2 || len(log.Topics) < 3 {
+		return domain.LendingAction{}, false
+	}
+	action := newLendingAction(market, log, domain.LendingActionSupply)
+	action.Asset = topicToAddress(log.Topics[1])
+	action.User = topicToAddress(log.Topics[2])
+	action.Caller = caller
+	action.Amount = amount
+	return action, true
+}
+
+// Withdraw(address indexed reserve, address indexed user, address indexed to, uint256 amount)
+func decodeAaveWithdraw(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 3 {
+		return domain.LendingAction{}, false
+	}
+	action := newLendingAction(market, log, domain.LendingActionWithdraw)
+	action.Asset = topicToAddress(log.Topics[1])
+	action.User = topicToAddress(log.Topics[2])
+	action.Amount = amount
+	return action, true
+}
+
+// Borrow(address indexed reserve, address user, address indexed onBehalfOf,
+// uint256 amount, rateMode, uint256 borrowRate, uint16 indexed referralCode)
+func decodeAaveBorrow(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	action, ok := decodeAaveSupply(tx, market, log)
+	action.Action = domain.LendingActionBorrow
+	return action, ok
+}
+
+// Repay(address indexed reserve, address indexed user, address indexed repayer,
+// uint256 amount[, bool useATokens])
+func decodeAaveRepay(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 4 {
+		return domain.LendingAction{}, false
+	}
+	action := newLendingAction(market, log, domain.LendingActionRepay)
+	action.Asset = topicToAddress(log.Topics[1])
+	action.User = topicToAddress(log.Topics[2])
+	action.Caller = topicToAddress(log.Topics[3])
+	action.Amount = amount
+	return action, true
+}
+
+// LiquidationCall(address indexed collateralAsset, address indexed debtAsset,
+// address indexed user, uint256 debtToCover, uint256 liquidatedCollateralAmount,
+// address liquidator, bool receiveAToken)
+func decodeAaveLiquidation(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	debt, ok1 := abiUint(log.Data, 0)
+	seized, ok2 := abiUint(log.Data, 1)
+	liquidator, ok3 := abiAddress(log.Data, 2)
+	if !ok1 || !ok2 || !ok3 || len(log.Topics) < 4 {
+		return domain.LendingAction{}, false
+	}
+	action := newLendingAction(market, log, domain.LendingActionLiquidation)
+	action.Asset = topicToAddress(log.Topics[2])
+	action.Amount = debt
+	action.User = topicToAddress(log.Topics[3])
+	action.Caller = liquidator
+	action.Liquidation = &domain.Liquidation{
+		Borrower:         action.User,
+		Liquidator:       liquidator,
+		DebtAsset:        action.Asset,
+		DebtRepaid:       debt,
+		CollateralAsset:  topicToAddress(log.Topics[1]),
+		CollateralSeized: seized,
+	}
+	return action, true
+}
+
+// Mint(address minter, uint256 mintAmount, uint256 mintTokens)
+func decodeCompoundMint(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	return decodeCompoundAccountAmount(market, log, domain.LendingActionSupply)
+}
+
+// Redeem(address redeemer, uint256 redeemAmount, uint256 redeemTokens)
+func decodeCompoundRedeem(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	return decodeCompoundAccountAmount(market, log, domain.LendingActionWithdraw)
+}
+
+// Borrow(address borrower, uint256 borrowAmount, uint256 accountBorrows, uint256 totalBorrows)
+func decodeCompoundBorrow(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	return decodeCompoundAccountAmount(market, log, domain.LendingActionBorrow)
+}
+
+func decodeCompoundAccountAmount(market domain.LendingMarket, log domain.Log, kind string) (domain.LendingAction, bool) {
+	user, ok1 := abiAddress(log.Data, 0)
+	amount, ok2 := abiUint(log.Data, 1)
+	if !ok1 || !ok2 {
+		return domain.LendingAction{}, false
+	}
+	action := newLendingAction(market, log, kind)
+	action.User = user
+	action.Amount = amount
+	return action, true
+}
+
+// RepayBorrow(address payer, address borrower, uint256 repayAmount,
+// uint256 accountBorrows, uint256 totalBorrows)
+func decodeCompoundRepay(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	payer, ok1 := abiAddress(log.Data, 0)
+	borrower, ok2 := abiAddress(log.Data, 1)
+	amount, ok3 := abiUint(log.Data, 2)
+	if !ok1 || !ok2 || !ok3 {
+		return domain.LendingAction{}, false
+	}
+	action := newLendingAction(market, log, domain.LendingActionRepay)
+	action.User = borrower
+	action.Caller = payer
+	action.Amount = amount
+	return action, true
+}
+
+// LiquidateBorrow(address liquidator, address borrower, uint256 repayAmount,
+// address cTokenCollateral, uint256 seizeTokens), emitted by the borrowed cToken.
+func decodeCompoundLiquidation(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	liquidator, ok1 := abiAddress(log.Data, 0)
+	borrower, ok2 := abiAddress(log.Data, 1)
+	debt, ok3 := abiUint(log.Data, 2)
+	collateral, ok4 := abiAddress(log.Data, 3)
+	seized, ok5 := abiUint(log.Data, 4)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 {
+		return domain.LendingAction{}, false
+	}
+	action := newLendingAction(market, log, domain.LendingActionLiquidation)
+	action.User = borrower
+	action.Caller = liquidator
+	action.Amount = debt
+	action.Liquidation = &domain.Liquidation{
+		Borrower:         borrower,
+		Liquidator:       liquidator,
+		DebtAsset:        action.Asset,
+		DebtRepaid:       debt,
+		CollateralAsset:  collateral,
+		CollateralSeized: seized,
+	}
+	return action, true
+}
+
+// Supply(address indexed from, address indexed dst, uint256 amount). Comet
+// first repays any debt of dst and only mints (a Transfer from zero) for
+// what is left, so a Supply without that mint is a repay.
+func decodeCometSupply(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 3 {
+		return domain.LendingAction{}, false
+	}
+	action := newLendingAction(market, log, domain.LendingActionRepay)
+	action.Caller = topicToAddress(log.Topics[1])
+	action.User = topicToAddress(log.Topics[2])
+	action.Amount = amount
+	if cometTransfer(tx, log.Address, zeroAddress, action.User) {
+		action.Action = domain.LendingActionSupply
+	}
+	return action, true
+}
+
+// Withdraw(address indexed src, address indexed to, uint256 amount). The
+// part of a withdrawal beyond the supplied balance is borrowed, and only the
+// supplied part burns (a Transfer to zero).
+func decodeCometWithdraw(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	amount, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 3 {
+		return domain.LendingAction{}, false
+	}
+	action := newLendingAction(market, log, domain.LendingActionBorrow)
+	action.User = topicToAddress(log.Topics[1])
+	action.Amount = amount
+	if cometTransfer(tx, log.Address, action.User, zeroAddress) {
+		action.Action = domain.LendingActionWithdraw
+	}
+	return action, true
+}
+
+func cometTransfer(tx domain.Tx, comet, from, to string) bool {
+	for _, log := range tx.Logs {
+		if len(log.Topics) == 3 && log.Topics[0] == transferEventTopic && strings.EqualFold(log.Address, comet) &&
+			topicToAddress(log.Topics[1]) == from && topicToAddress(log.Topics[2]) == to {
+			return true
+		}
+	}
+	return false
+}
+
+// SupplyCollateral(address indexed from, address indexed dst, address indexed asset, uint256 amount)
+// WithdrawCollateral(address indexed src, address indexed to, address indexed asset, uint256 amount)
+func decodeCometCollateral(kind string) func(domain.Tx, domain.LendingMarket, domain.Log) (domain.LendingAction, bool) {
+	return func(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+		amount, ok := abiUint(log.Data, 0)
+		if !ok || len(log.Topics) < 4 {
+			return domain.LendingAction{}, false
+		}
+		action := newLendingAction(market, log, kind)
+		action.Asset = topicToAddress(log.Topics[3])
+		action.Amount = amount
+		action.Collateral = true
+		if kind == domain.LendingActionSupply {
+			action.Caller = topicToAddress(log.Topics[1])
+			action.User = topicToAddress(log.Topics[2])
+		} else {
+			action.User = topicToAddress(log.Topics[1])
+		}
+		return action, true
+	}
+}
+
+// AbsorbDebt(address indexed absorber, address indexed borrower, uint256 basePaidOut, uint256 usdValue)
+func decodeCometAbsorbDebt(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	debt, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 3 {
+		return domain.LendingAction{}, false
+	}
+	action := newLendingAction(market, log, domain.LendingActionLiquidation)
+	action.Caller = topicToAddress(log.Topics[1])
+	action.User = topicToAddress(log.Topics[2])
+	action.Amount = debt
+	action.Liquidation = &domain.Liquidation{
+		Borrower:   action.User,
+		Liquidator: action.Caller,
+		DebtAsset:  action.Asset,
+		DebtRepaid: debt,
+	}
+	return action, true
+}
+
+// AbsorbCollateral(address indexed absorber, address indexed borrower,
+// address indexed asset, uint256 collateralAbsorbed, uint256 usdValue)
+func decodeCometAbsorbCollateral(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	seized, ok := abiUint(log.Data, 0)
+	if !ok || len(log.Topics) < 4 {
+		return domain.LendingAction{}, false
+	}
+	action := newLendingAction(market, log, domain.LendingActionLiquidation)
+	action.Caller = topicToAddress(log.Topics[1])
+	action.User = topicToAddress(log.Topics[2])
+	action.Liquidation = &domain.Liquidation{
+		Borrower:         action.User,
+		Liquidator:       action.Caller,
+		CollateralAsset:  topicToAddress(log.Topics[3]),
+		CollateralSeized: seized,
+	}
+	return action, true
+}
+
+// newMorphoAction keys the action by market id, since a single Morpho Blue
+// contract holds every market.
+func newMorphoAction(market domain.LendingMarket, log domain.Log, kind string, collateral bool) domain.LendingAction {
+	action := newLendingAction(market, log, kind)
+	action.Market = strings.ToLower(log.Topics[1])
+	action.Collateral = collateral
+	return action
+}
+
+// Supply / Repay(Id indexed id, address indexed caller, address indexed onBehalf, uint256 assets, uint256 shares)
+// SupplyCollateral(Id indexed id, address indexed caller, address indexed onBehalf, uint256 assets)
+func decodeMorphoCallerFirst(kind string, collateral bool) func(domain.Tx, domain.LendingMarket, domain.Log) (domain.LendingAction, bool) {
+	return func(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+		amount, ok := abiUint(log.Data, 0)
+		if !ok || len(log.Topics) < 4 {
+			return domain.LendingAction{}, false
+		}
+		action := newMorphoAction(market, log, kind, collateral)
+		action.Caller = topicToAddress(log.Topics[2])
+		action.User = topicToAddress(log.Topics[3])
+		action.Amount = amount
+		return action, true
+	}
+}
+
+// Withdraw / Borrow(Id indexed id, address caller, address indexed onBehalf,
+// address indexed receiver, uint256 assets, uint256 shares)
+// WithdrawCollateral(Id indexed id, address caller, address indexed onBehalf,
+// address indexed receiver, uint256 assets)
+func decodeMorphoReceiver(kind string, collateral bool) func(domain.Tx, domain.LendingMarket, domain.Log) (domain.LendingAction, bool) {
+	return func(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+		caller, ok1 := abiAddress(log.Data, 0)
+		amount, ok2 := abiUint(log.Data, 1)
+		if !ok1 || !ok2 || len(log.Topics) < 4 {
+			return domain.LendingAction{}, false
+		}
+		action := newMorphoAction(market, log, kind, collateral)
+		action.Caller = caller
+		action.User = topicToAddress(log.Topics[2])
+		action.Amount = amount
+		return action, true
+	}
+}
+
+// Liquidate(Id indexed id, address indexed caller, address indexed borrower,
+// uint256 repaidAssets, uint256 repaidShares, uint256 seizedAssets,
+// uint256 badDebtAssets, uint256 badDebtShares)
+func decodeMorphoLiquidation(tx domain.Tx, market domain.LendingMarket, log domain.Log) (domain.LendingAction, bool) {
+	repaid, ok1 := abiUint(log.Data, 0)
+	seized, ok2 := abiUint(log.Data, 2)
+	if !ok1 || !ok2 || len(log.Topics) < 4 {
+		return domain.LendingAction{}, false
+	}
+	action := newMorphoAction(market, log, domain.LendingActionLiquidation, false)
+	action.Caller = topicToAddress(log.Topics[2])
+	action.User = topicToAddress(log.Topics[3])
+	action.Amount = repaid
+	action.Liquidation = &domain.Liquidation{
+		Borrower:         action.User,
+		Liquidator:       action.Caller,
+		DebtRepaid:       repaid,
+		CollateralSeized: seized,
+	}
+	return action, true
+}
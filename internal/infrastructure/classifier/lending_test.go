@@ -0,0 +1,188 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+const (
+	aavePool      = "0x87870bca3f3fd6335c3f4ce8392d69350b4fa4e2"
+	cUSDC         = "0x39aa39c021dfbae8fac545936693ac917d5e7563"
+	cometUSDC     = "0xc3d688b66703497daa19211eedff47f25384cdc3"
+	morphoBlue    = "0xbbbbbbbbbb9cc5e90e3b3af64bdaf62c37eeffcb"
+	testUSDC      = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testWETH      = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	testBorrower  = "0x3333333333333333333333333333333333333333"
+	testLiquidate = "0x4444444444444444444444444444444444444444"
+)
+
+var testMarkets = []domain.LendingMarket{
+	{Name: "aave-v3", Protocol: domain.LendingProtocolAaveV3, Address: aavePool},
+	{Name: "compound-cusdc", Protocol: domain.LendingProtocolCompoundV2, Address: cUSDC, Asset: testUSDC},
+	{Name: "compound-v3-usdc", Protocol: domain.LendingProtocolCompoundV3, Address: cometUSDC, Asset: testUSDC},
+	{Name: "morpho-blue", Protocol: domain.LendingProtocolMorphoBlue, Address: morphoBlue},
+}
+
+// summary renders an action as "action user amount asset".
+func summary(a domain.LendingAction) string {
+	s := fmt.Sprintf("%s %s %s %s", a.Action, a.User, formatOptional(a.Amount), a.Asset)
+	if a.Collateral {
+		s += " collateral"
+	}
+	if l := a.Liquidation; l != nil {
+		s += fmt.Sprintf(" by %s repaid %s seized %s %s", l.Liquidator, formatOptional(l.DebtRepaid), formatOptional(l.CollateralSeized), l.CollateralAsset)
+	}
+	return s
+}
+
+func TestLendingLogResolver(t *testing.T) {
+	aaveSupply := domain.Log{
+		Address: aavePool,
+		Topics:  []string{aaveV3SupplyTopic, addrTopic(testWETH), addrTopic(testUser), intTopic(0)},
+		Data:    words(testUser, 1000),
+	}
+	aaveBorrow := domain.Log{
+		Address: aavePool,
+		Topics:  []string{aaveV3BorrowTopic, addrTopic(testUSDC), addrTopic(testUser), intTopic(0)},
+		Data:    words(testUser, 500, 2, 0),
+	}
+	aaveLiquidation := domain.Log{
+		Address: aavePool,
+		Topics:  []string{aaveLiquidationCallTopic, addrTopic(testWETH), addrTopic(testUSDC), addrTopic(testBorrower)},
+		Data:    words(400, 7, testLiquidate, 0),
+	}
+	compoundRepay := domain.Log{
+		Address: cUSDC,
+		Topics:  []string{compoundRepayBorrowTopic},
+		Data:    words(testUser, testBorrower, 300, 0, 0),
+	}
+	cometSupply := domain.Log{
+		Address: cometUSDC,
+		Topics:  []string{cometSupplyTopic, addrTopic(testUser), addrTopic(testUser)},
+		Data:    words(200),
+	}
+	cometMint := domain.Log{
+		Address: cometUSDC,
+		Topics:  []string{transferEventTopic, addrTopic(zeroAddress), addrTopic(testUser)},
+		Data:    words(200),
+	}
+	absorbCollateral := domain.Log{
+		Address: cometUSDC,
+		Topics:  []string{cometAbsorbCollateralTopic, addrTopic(testLiquidate), addrTopic(testBorrower), addrTopic(testWETH)},
+		Data:    words(3, 0),
+	}
+	absorbDebt := domain.Log{
+		Address: cometUSDC,
+		Topics:  []string{cometAbsorbDebtTopic, addrTopic(testLiquidate), addrTopic(testBorrower)},
+		Data:    words(900, 0),
+	}
+	marketID := "0x" + strings.Repeat("12", 32)
+	morphoBorrow := domain.Log{
+		Address: morphoBlue,
+		Topics:  []string{morphoBorrowTopic, marketID, addrTopic(testUser), addrTopic(testRecipient)},
+		Data:    words(testUser, 100, 99),
+	}
+	lookAlike := aaveSupply
+	lookAlike.Topics = append([]string{aaveV2DepositTopic}, aaveSupply.Topics[1:]...)
+	foreign := aaveSupply
+	foreign.Address = testRecipient
+
+	tests := []struct {
+		name    string
+		current domain.ClassificationType
+		logs    []domain.Log
+		want    domain.ClassificationType
+		actions []string
+		market  string
+	}{
+		{
+			name:    "aave supply",
+			logs:    []domain.Log{aaveSupply},
+			want:    domain.ClassificationLendingSupply,
+			actions: []string{"supply " + testUser + " 1000 " + testWETH},
+		},
+		{
+			name: "aave supply then borrow",
+			logs: []domain.Log{aaveSupply, aaveBorrow},
+			want: domain.ClassificationLendingBorrow,
+			actions: []string{
+				"supply " + testUser + " 1000 " + testWETH,
+				"borrow " + testUser + " 500 " + testUSDC,
+			},
+		},
+		{
+			name:    "aave liquidation",
+			logs:    []domain.Log{aaveLiquidation},
+			want:    domain.ClassificationLendingLiquidation,
+			actions: []string{"liquidation " + testBorrower + " 400 " + testUSDC + " by " + testLiquidate + " repaid 400 seized 7 " + testWETH},
+		},
+		{
+			name:    "compound repay on behalf",
+			logs:    []domain.Log{compoundRepay},
+			want:    domain.ClassificationLendingRepay,
+			actions: []string{"repay " + testBorrower + " 300 " + testUSDC},
+		},
+		{
+			name:    "comet supply that mints",
+			logs:    []domain.Log{cometMint, cometSupply},
+			want:    domain.ClassificationLendingSupply,
+			actions: []string{"supply " + testUser + " 200 " + testUSDC},
+		},
+		{
+			name:    "comet supply that repays",
+			logs:    []domain.Log{cometSupply},
+			want:    domain.ClassificationLendingRepay,
+			actions: []string{"repay " + testUser + " 200 " + testUSDC},
+		},
+		{
+			name:    "comet absorb merges debt into collateral",
+			logs:    []domain.Log{absorbCollateral, absorbDebt},
+			want:    domain.ClassificationLendingLiquidation,
+			actions: []string{"liquidation " + testBorrower + " 900 " + testUSDC + " by " + testLiquidate + " repaid 900 seized 3 " + testWETH},
+		},
+		{
+			name:    "morpho borrow keyed by market id",
+			logs:    []domain.Log{morphoBorrow},
+			want:    domain.ClassificationLendingBorrow,
+			actions: []string{"borrow " + testUser + " 100 "},
+			market:  marketID,
+		},
+		{name: "other protocol's topic", logs: []domain.Log{lookAlike}},
+		{name: "unknown emitter", logs: []domain.Log{foreign}},
+		{name: "already classified", current: domain.ClassificationDexSwap, logs: []domain.Log{aaveSupply}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := domain.TxResult{Type: domain.ClassificationContractCall}
+			if tt.current != "" {
+				current.Type = tt.current
+			}
+			tx := domain.Tx{From: testUser, Logs: tt.logs}
+			result, ok, err := LendingLogResolver{Markets: testMarkets}.Resolve(context.Background(), tx, current)
+			if err != nil || ok != (tt.want != "") {
+				t.Fatalf("Resolve = %v, %v", ok, err)
+			}
+			if !ok {
+				return
+			}
+			if result.Type != tt.want {
+				t.Errorf("type = %s, want %s", result.Type, tt.want)
+			}
+			if len(result.Lending) != len(tt.actions) {
+				t.Fatalf("actions = %d, want %d", len(result.Lending), len(tt.actions))
+			}
+			for i, want := range tt.actions {
+				if got := summary(result.Lending[i]); got != want {
+					t.Errorf("action %d = %q, want %q", i, got, want)
+				}
+			}
+			if tt.market != "" && result.Lending[0].Market != tt.market {
+				t.Errorf("market = %s, want %s", result.Lending[0].Market, tt.market)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package classifier
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+const (
+	multicallSelector                = "ac9650d8" // multicall(bytes[])
+	multicallDeadlineSelector        = "5ae401dc" // multicall(uint256,bytes[]), SwapRouter02
+	multicallBlockhashSelector       = "1f0464d1" // multicall(bytes32,bytes[]), SwapRouter02
+	aggregateSelector                = "252dba42" // aggregate((address,bytes)[])
+	blockAndAggregateSelector        = "c3077fa9" // blockAndAggregate((address,bytes)[])
+	tryAggregateSelector             = "bce38bd7" // tryAggregate(bool,(address,bytes)[])
+	aggregate3Selector               = "82ad56cb" // aggregate3((address,bool,bytes)[])
+	aggregate3ValueSelector          = "174dea71" // aggregate3Value((address,bool,uint256,bytes)[])
+	universalExecuteSelector         = "24856bc3" // execute(bytes,bytes[])
+	universalExecuteDeadlineSelector = "3593564c" // execute(bytes,bytes[],uint256)
+
+	universalCommandMask    = 0x3f
+	universalExecuteSubPlan = 0x21
+)
+
+// universalCommands maps Universal Router command types to the selector of
+// an equivalent function taking the command's ABI-encoded input, so the
+// input can be handed to the calldata classifiers as an ordinary call.
+var universalCommands = map[byte]string{
+	0x00: "5a58ec2d", // v3SwapExactIn(address,uint256,uint256,bytes,bool)
+	0x01: "14cd4424", // v3SwapExactOut(address,uint256,uint256,bytes,bool)
+	0x02: "f7223d79", // permit2TransferFrom(address,address,uint160)
+	0x04: "62c06767", // sweep(address,address,uint256)
+	0x05: "beabacc8", // transfer(address,address,uint256)
+	0x06: "d5092a81", // payPortion(address,address,uint256)
+	0x08: "dcd5db1f", // v2SwapExactIn(address,uint256,uint256,address[],bool)
+	0x09: "e868b367", // v2SwapExactOut(address,uint256,uint256,address[],bool)
+	0x0b: "29793f7d", // wrapETH(address,uint256)
+	0x0c: "5869dba8", // unwrapWETH(address,uint256)
+	0x10: "8fc74d54", // v4Swap(bytes)
+	// EXECUTE_SUB_PLAN takes (bytes commands, bytes[] inputs), which is
+	// execute(bytes,bytes[]) calldata and unwraps again.
+	universalExecuteSubPlan: universalExecuteSelector,
+}
+
+// MulticallUnwrapper splits batched calls into inner calls:
+//   - multicall(bytes[]) and its SwapRouter02 variants, where the contract
+//     delegatecalls itself with each payload;
+//   - the Multicall3 aggregate family, which calls each target in turn;
+//   - Universal Router execute(commands, inputs), one call per command.
+//
+// Multicall3 batches become BATCH_CALL and each call gets the logs emitted by
+// its target, as with smart account batches. Self multicalls and Universal
+// Router plans keep the outer classification so the log resolvers still see
+// the whole swap; their inner calls are classified from calldata alone, since
+// their logs come from pools and tokens shared by every call. Every inner
+// call carries the tx value, as each delegatecall or command runs with the
+// same msg.value (e.g. a WRAP_ETH of the router's balance).
+type MulticallUnwrapper struct{}
+
+func (MulticallUnwrapper) Unwrap(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, []domain.InnerCall, bool, error) {
+	if tx.To == nil || len(tx.Data) < 4 {
+		return current, nil, false, nil
+	}
+	contract := strings.ToLower(*tx.To)
+	var receipt *domain.Receipt
+	if tx.Receipt != nil {
+		receipt = &domain.Receipt{Success: tx.Receipt.Success}
+	}
+	args := tx.Data[4:]
+	updated := current
+	var calls []domain.InnerCall
+	switch selectorHex(tx.Data) {
+	case multicallSelector, multicallDeadlineSelector, multicallBlockhashSelector:
+		index := 0
+		if selectorHex(tx.Data) != multicallSelector {
+			index = 1
+		}
+		payloads, ok := multicallPayloads(args, index)
+		if !ok {
+			return current, nil, false, nil
+		}
+		for _, data := range payloads {
+			call := accountCall{to: contract, value: txValue(tx), data: data}
+			calls = append(calls, domain.InnerCall{Tx: innerTx(tx, tx.From, call, nil, receipt)})
+		}
+		updated.Details = fmt.Sprintf("multicall of %d calls to %s", len(calls), contract)
+		updated.Evidence = &domain.Evidence{Rule: "multicall calldata", Selector: current.Selector, Address: contract}
+	case aggregateSelector, blockAndAggregateSelector, tryAggregateSelector, aggregate3Selector, aggregate3ValueSelector:
+		decoded, ok := decodeAggregate(tx.Data)
+		if !ok {
+			return current, nil, false, nil
+		}
+		for _, call := range decoded {
+			calls = append(calls, domain.InnerCall{Tx: innerTx(tx, contract, call, logsFrom(tx.Logs, call.to), receipt)})
+		}
+		updated.Type = domain.ClassificationBatchCall
+		updated.Details = fmt.Sprintf("multicall %s aggregates %d calls", contract, len(calls))
+		updated.Evidence = &domain.Evidence{Rule: "Multicall3 aggregate calldata", Selector: current.Selector, Address: contract}
+	case universalExecuteSelector, universalExecuteDeadlineSelector:
+		decoded, commands, ok := decodeUniversalPlan(args, contract, txValue(tx))
+		if !ok {
+			return current, nil, false, nil
+		}
+		for _, call := range decoded {
+			calls = append(calls, domain.InnerCall{Tx: innerTx(tx, tx.From, call, nil, receipt)})
+		}
+		updated.Details = fmt.Sprintf("universal router plan of %d commands (%d decoded): %s", len(commands), len(calls), hex.EncodeToString(commands))
+		updated.Evidence = &domain.Evidence{Rule: "Universal Router execute calldata", Selector: current.Selector, Address: contract}
+	default:
+		return current, nil, false, nil
+	}
+	if len(calls) == 0 {
+		return current, nil, false, nil
+	}
+	return updated, calls, true, nil
+}
+
+func multicallPayloads(args []byte, i int) ([][]byte, bool) {
+	elems, ok := abiDynamicArray(args, i)
+	if !ok {
+		return nil, false
+	}
+	payloads := make([][]byte, 0, len(elems))
+	for _, elem := range elems {
+		data, ok := abiBytesBody(elem)
+		if !ok {
+			return nil, false
+		}
+		payloads = append(payloads, data)
+	}
+	return payloads, true
+}
+
+// decodeAggregate reads the Multicall3 call tuples: (target, callData) for
+// aggregate, blockAndAggregate and tryAggregate (after its requireSuccess
+// flag), (target, allowFailure, callData) for aggregate3 and
+// (target, allowFailure, value, callData) for aggregate3Value.
+func decodeAggregate(data []byte) ([]accountCall, bool) {
+	args := data[4:]
+	index, dataField, valueField := 0, 1, -1
+	switch selectorHex(data) {
+	case tryAggregateSelector:
+		index = 1
+	case aggregate3Selector:
+		dataField = 2
+	case aggregate3ValueSelector:
+		dataField, valueField = 3, 2
+	}
+	elems, ok := abiDynamicArray(args, index)
+	if !ok {
+		return nil, false
+	}
+	calls := make([]accountCall, 0, len(elems))
+	for _, elem := range elems {
+		target, ok1 := abiAddress(elem, 0)
+		callData, ok2 := abiBytes(elem, dataField)
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		value := big.NewInt(0)
+		if valueField >= 0 {
+			if value, ok = abiUint(elem, valueField); !ok {
+				return nil, false
+			}
+		}
+		calls = append(calls, accountCall{to: target, value: value, data: callData})
+	}
+	return calls, true
+}
+
+// decodeUniversalPlan pairs each command byte with its input. Commands
+// without a known equivalent function are skipped; the allow-revert flag is
+// ignored since only the outcome of the whole tx is known.
+func decodeUniversalPlan(args []byte, router string, value *big.Int) ([]accountCall, []byte, bool) {
+	commands, ok1 := abiBytes(args, 0)
+	inputs, ok2 := abiDynamicArray(args, 1)
+	if !ok1 || !ok2 || len(commands) != len(inputs) {
+		return nil, nil, false
+	}
+	var calls []accountCall
+	for i, command := range commands {
+		selector, ok := universalCommands[command&universalCommandMask]
+		if !ok {
+			continue
+		}
+		input, ok := abiBytesBody(inputs[i])
+		if !ok {
+			return nil, nil, false
+		}
+		prefix, _ := hex.DecodeString(selector)
+		data := append(prefix, input...)
+		calls = append(calls, accountCall{to: router, value: value, data: data})
+	}
+	return calls, commands, true
+}
@@ -0,0 +1,105 @@
+package classifier
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+func TestMulticallUnwrapper(t *testing.T) {
+	const (
+		router    = "0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad"
+		multicall = "0xca11bde05977b3631167028862be2a173976ca11"
+		user      = "0x1111111111111111111111111111111111111111"
+		tokenA    = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+		tokenB    = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	)
+	exactInputSingle := calldata("04e45aaf", words(tokenB, tokenA, 500, user, 1000, 900, 0))
+	wrapETH := words(router, 1000)
+	v2Swap := words(user, 1000, 900, 160, 0, 2, tokenB, tokenA)
+	approve := calldata("095ea7b3", words(router, 1))
+	tokenLog := domain.Log{Address: tokenA}
+
+	tests := []struct {
+		name     string
+		to       string
+		data     []byte
+		class    domain.ClassificationType
+		from     string
+		payloads [][]byte
+		logs     []int
+	}{
+		{
+			name:     "multicall",
+			to:       router,
+			data:     calldata(multicallSelector, words(32, dynArray(abiBytesTail(exactInputSingle)))),
+			class:    domain.ClassificationContractCall,
+			from:     user,
+			payloads: [][]byte{exactInputSingle},
+			logs:     []int{0},
+		},
+		{
+			name: "universal router plan",
+			to:   router,
+			// execute(bytes commands, bytes[] inputs, uint256 deadline) with
+			// WRAP_ETH, V2_SWAP_EXACT_IN and an unknown command.
+			data: calldata(universalExecuteDeadlineSelector, words(96, 160, 1,
+				3, []byte{0x0b, 0x08, 0x3f}, make([]byte, 29),
+				dynArray(abiBytesTail(wrapETH), abiBytesTail(v2Swap), abiBytesTail(nil)))),
+			class:    domain.ClassificationContractCall,
+			from:     user,
+			payloads: [][]byte{calldata("29793f7d", wrapETH), calldata("dcd5db1f", v2Swap)},
+			logs:     []int{0, 0},
+		},
+		{
+			name: "aggregate3",
+			to:   multicall,
+			data: calldata(aggregate3Selector, words(32, dynArray(
+				words(tokenA, 1, 96, abiBytesTail(approve)),
+				words(tokenB, 0, 96, abiBytesTail(approve)),
+			))),
+			class:    domain.ClassificationBatchCall,
+			from:     multicall,
+			payloads: [][]byte{approve, approve},
+			logs:     []int{1, 0},
+		},
+		{name: "universal router plan with missing inputs", to: router, data: calldata(universalExecuteSelector, words(64, 128, 2, []byte{0x0b, 0x08}, make([]byte, 30), dynArray(abiBytesTail(wrapETH))))},
+		{name: "other selector", to: router, data: approve},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := tt.to
+			tx := domain.Tx{Hash: "0x01", From: user, To: &to, Value: big.NewInt(1000), Data: tt.data, Logs: []domain.Log{tokenLog}}
+			current := domain.TxResult{Tx: tx, Type: domain.ClassificationContractCall}
+			result, calls, ok, err := MulticallUnwrapper{}.Unwrap(context.Background(), tx, current)
+			if err != nil || ok != (tt.payloads != nil) {
+				t.Fatalf("Unwrap = %v, %v", ok, err)
+			}
+			if !ok {
+				return
+			}
+			if result.Type != tt.class {
+				t.Errorf("type = %s, want %s", result.Type, tt.class)
+			}
+			if len(calls) != len(tt.payloads) {
+				t.Fatalf("got %d calls, want %d", len(calls), len(tt.payloads))
+			}
+			for i, call := range calls {
+				if call.Tx.From != tt.from || !bytes.Equal(call.Tx.Data, tt.payloads[i]) || len(call.Tx.Logs) != tt.logs[i] {
+					t.Errorf("call %d = from %s data %x logs %d", i, call.Tx.From, call.Tx.Data, len(call.Tx.Logs))
+				}
+				// Delegatecalls and router commands see the outer msg.value.
+				wantValue := int64(1000)
+				if tt.class == domain.ClassificationBatchCall {
+					wantValue = 0
+				}
+				if call.Tx.Value.Int64() != wantValue {
+					t.Errorf("call %d value = %s, want %d", i, call.Tx.Value, wantValue)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+const blobGasPerBlob = 1 << 17
+
+// RollupBatchClassifier matches batch posts of the known rollups, sent to
+// their inbox or from one of their batchers, and any other EIP-4844 blob tx.
+// Other calls to a shared inbox, like a Linea sendMessage, are left to the
+// remaining classifiers.
+// Calldata batches carry no blobs; blob batches also report the blob gas and
+// fee, which the receipt prices (without receipts only the gas is known).
+type RollupBatchClassifier struct {
+	Rollups []domain.RollupInbox
+}
+
+func (c RollupBatchClassifier) Classify(ctx context.Context, tx domain.Tx) (domain.TxResult, bool, error) {
+	rollup, rule := c.match(tx)
+	if rollup == "" && len(tx.BlobHashes) == 0 {
+		return domain.TxResult{}, false, nil
+	}
+	batch := blobBatch(tx, rollup)
+	result := domain.TxResult{
+		Selector: selectorHex(tx.Data),
+		Batch:    batch,
+		Details:  formatBatchDetails(tx, *batch),
+	}
+	if rollup != "" {
+		result.Type = domain.ClassificationRollupBatch
+		result.Evidence = &domain.Evidence{Rule: rule}
+	} else {
+		result.Type = domain.ClassificationBlobTx
+		result.Evidence = &domain.Evidence{Rule: fmt.Sprintf("tx type 0x%02x with %d blobs from unknown sender", tx.Type, len(tx.BlobHashes))}
+	}
+	return result, true, nil
+}
+
+func (c RollupBatchClassifier) match(tx domain.Tx) (string, string) {
+	selector := selectorHex(tx.Data)
+	for _, r := range c.Rollups {
+		for _, batcher := range r.Batchers {
+			if strings.EqualFold(batcher, tx.From) {
+				return r.Name, fmt.Sprintf("tx from %s batcher", r.Name)
+			}
+		}
+		if tx.To == nil || !strings.EqualFold(r.Inbox, *tx.To) {
+			continue
+		}
+		switch {
+		case !r.SharedInbox:
+			return r.Name, fmt.Sprintf("tx to %s batch inbox", r.Name)
+		case len(tx.BlobHashes) > 0:
+			return r.Name, fmt.Sprintf("blob tx to %s rollup contract", r.Name)
+		case selector != "" && slices.Contains(r.BatchSelectors, selector):
+			return r.Name, fmt.Sprintf("batch submission 0x%s to %s rollup contract", selector, r.Name)
+		}
+	}
+	return "", ""
+}
+
+func blobBatch(tx domain.Tx, rollup string) *domain.RollupBatch {
+	batch := &domain.RollupBatch{
+		Rollup:      rollup,
+		Blobs:       len(tx.BlobHashes),
+		BlobGasUsed: uint64(len(tx.BlobHashes)) * blobGasPerBlob,
+	}
+	if tx.Receipt != nil && tx.Receipt.BlobGasPrice != nil {
+		if tx.Receipt.BlobGasUsed > 0 {
+			batch.BlobGasUsed = tx.Receipt.BlobGasUsed
+		}
+		batch.BlobGasPrice = new(big.Int).Set(tx.Receipt.BlobGasPrice)
+		batch.BlobFee = new(big.Int).Mul(new(big.Int).SetUint64(batch.BlobGasUsed), batch.BlobGasPrice)
+	}
+	return batch
+}
+
+func formatBatchDetails(tx domain.Tx, batch domain.RollupBatch) string {
+	if batch.Blobs == 0 {
+		return fmt.Sprintf("%s calldata batch, %d bytes", batch.Rollup, len(tx.Data))
+	}
+	kind := batch.Rollup + " blob batch"
+	if batch.Rollup == "" {
+		kind = "blob tx"
+	}
+	return fmt.Sprintf("%s, %d blobs, blob gas %d, blob fee %s wei",
+		kind, batch.Blobs, batch.BlobGasUsed, formatOptional(batch.BlobFee))
+}
@@ -0,0 +1,52 @@
+package classifier
+
+import (
+	"context"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+func TestRollupBatchClassifier(t *testing.T) {
+	const (
+		opInbox     = "0xff00000000000000000000000000000000000010"
+		opBatcher   = "0x6887246668a3b87f54deb3b94ba47a6f63f32985"
+		lineaRollup = "0xd19d4b5d358258f05d7b411e21a1460d11b0876f"
+		scrollChain = "0xa13baf47339d63b743e7da8741db5456dac1e556"
+		user        = "0x1111111111111111111111111111111111111111"
+	)
+	c := RollupBatchClassifier{Rollups: []domain.RollupInbox{
+		{Name: "optimism", Inbox: opInbox, Batchers: []string{opBatcher}},
+		{Name: "linea", Inbox: lineaRollup, SharedInbox: true},
+		{Name: "scroll", Inbox: scrollChain, SharedInbox: true, BatchSelectors: []string{"1325aca0"}},
+	}}
+	to := func(addr string) *string { return &addr }
+	blobs := []string{"0x01aa"}
+
+	tests := []struct {
+		name   string
+		tx     domain.Tx
+		want   domain.ClassificationType
+		rollup string
+	}{
+		{"calldata to plain inbox", domain.Tx{From: user, To: to(opInbox), Data: []byte{0x00, 0x01}}, domain.ClassificationRollupBatch, "optimism"},
+		{"blobs from batcher", domain.Tx{From: opBatcher, To: to(opInbox), BlobHashes: blobs}, domain.ClassificationRollupBatch, "optimism"},
+		{"blobs to shared inbox", domain.Tx{From: user, To: to(lineaRollup), BlobHashes: blobs}, domain.ClassificationRollupBatch, "linea"},
+		{"sendMessage to shared inbox", domain.Tx{From: user, To: to(lineaRollup), Data: []byte{0x9f, 0x3c, 0xe5, 0x5a}}, "", ""},
+		{"batch selector on shared inbox", domain.Tx{From: user, To: to(scrollChain), Data: []byte{0x13, 0x25, 0xac, 0xa0}}, domain.ClassificationRollupBatch, "scroll"},
+		{"other call on shared inbox", domain.Tx{From: user, To: to(scrollChain), Data: []byte{0x31, 0xfa, 0x74, 0x2d}}, "", ""},
+		{"unknown blob tx", domain.Tx{From: user, To: to(user), BlobHashes: blobs}, domain.ClassificationBlobTx, ""},
+		{"plain transfer", domain.Tx{From: user, To: to(user)}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, ok, err := c.Classify(context.Background(), tt.tx)
+			if err != nil || ok != (tt.want != "") {
+				t.Fatalf("Classify = %v, %v; want match %v", ok, err, tt.want != "")
+			}
+			if ok && (res.Type != tt.want || res.Batch.Rollup != tt.rollup) {
+				t.Fatalf("result = %s %q, want %s %q", res.Type, res.Batch.Rollup, tt.want, tt.rollup)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RouterSwapClassifier predicts swaps from Uniswap V2/V3 router calldata
+// (and forks sharing their ABI). It only needs the tx itself, so it covers
+// pending txs, where no Swap event exists yet, and the inner calls of router
+// multicalls, which get no logs. Txs with logs are left to DexSwapLogResolver.
+type RouterSwapClassifier struct{}
+
+type routerMethod struct {
+	name   string
+	decode func(tx domain.Tx, args []byte) (*domain.SwapIntent, bool)
+}
+
+var routerMethods = map[string]routerMethod{
+	// Uniswap V2 Router02.
+	"38ed1739": {"swapExactTokensForTokens", decodeV2ExactIn},
+	"18cbafe5": {"swapExactTokensForETH", decodeV2ExactIn},
+	"5c11d795": {"swapExactTokensForTokensSupportingFeeOnTransferTokens", decodeV2ExactIn},
+	"791ac947": {"swapExactTokensForETHSupportingFeeOnTransferTokens", decodeV2ExactIn},
+	"8803dbee": {"swapTokensForExactTokens", decodeV2ExactOut},
+	"4a25d94a": {"swapTokensForExactETH", decodeV2ExactOut},
+	"7ff36ab5": {"swapExactETHForTokens", decodeV2ExactETHIn},
+	"b6f9de95": {"swapExactETHForTokensSupportingFeeOnTransferTokens", decodeV2ExactETHIn},
+	"fb3bdb41": {"swapETHForExactTokens", decodeV2ETHExactOut},
+	// Uniswap V3 SwapRouter.
+	"414bf389": {"exactInputSingle", decodeV3Single(true, true)},
+	"db3e2198": {"exactOutputSingle", decodeV3Single(false, true)},
+	"c04b8d59": {"exactInput", decodeV3Path(true, true)},
+	"f28c0498": {"exactOutput", decodeV3Path(false, true)},
+	// Uniswap V3 SwapRouter02 (no deadline in the params struct).
+	"04e45aaf": {"exactInputSingle", decodeV3Single(true, false)},
+	"5023b4df": {"exactOutputSingle", decodeV3Single(false, false)},
+	"b858183f": {"exactInput", decodeV3Path(true, false)},
+	"09b81346": {"exactOutput", decodeV3Path(false, false)},
+	// Universal Router swap commands, as unwrapped by MulticallUnwrapper.
+	"5a58ec2d": {"V3_SWAP_EXACT_IN", decodeUniversalV3(true)},
+	"14cd4424": {"V3_SWAP_EXACT_OUT", decodeUniversalV3(false)},
+	"dcd5db1f": {"V2_SWAP_EXACT_IN", decodeUniversalV2(true)},
+	"e868b367": {"V2_SWAP_EXACT_OUT", decodeUniversalV2(false)},
+}
+
+func (RouterSwapClassifier) Classify(ctx context.Context, tx domain.Tx) (domain.TxResult, bool, error) {
+	if tx.To == nil || len(tx.Data) < 4 || len(tx.Logs) > 0 {
+		return domain.TxResult{}, false, nil
+	}
+	selector := selectorHex(tx.Data)
+	method, ok := routerMethods[selector]
+	if !ok {
+		return domain.TxResult{}, false, nil
+	}
+	intent, ok := method.decode(tx, tx.Data[4:])
+	if !ok {
+		return domain.TxResult{}, false, nil
+	}
+	intent.Router = strings.ToLower(*tx.To)
+	intent.Method = method.name
+
+	return domain.TxResult{
+		Type:     domain.ClassificationDexSwapIntent,
+		Selector: selector,
+		Intent:   intent,
+		Details:  formatIntentDetails(*intent),
+		Evidence: &domain.Evidence{
+			Rule:     "router swap calldata (" + method.name + ")",
+			Selector: selector,
+			Address:  intent.Router,
+		},
+	}, true, nil
+}
+
+// (amountIn, amountOutMin, path, to, deadline)
+func decodeV2ExactIn(tx domain.Tx, args []byte) (*domain.SwapIntent, bool) {
+	amountIn, ok1 := abiUint(args, 0)
+	amountOutMin, ok2 := abiUint(args, 1)
+	path, ok3 := abiAddressArray(args, 2)
+	to, ok4 := abiAddress(args, 3)
+	deadline, ok5 := abiUint(args, 4)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || len(path) < 2 {
+		return nil, false
+	}
+	return &domain.SwapIntent{Path: path, AmountIn: amountIn, AmountOutMin: amountOutMin, Recipient: to, Deadline: deadline}, true
+}
+
+// (amountOut, amountInMax, path, to, deadline)
+func decodeV2ExactOut(tx domain.Tx, args []byte) (*domain.SwapIntent, bool) {
+	amountOut, ok1 := abiUint(args, 0)
+	amountInMax, ok2 := abiUint(args, 1)
+	path, ok3 := abiAddressArray(args, 2)
+	to, ok4 := abiAddress(args, 3)
+	deadline, ok5 := abiUint(args, 4)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || len(path) < 2 {
+		return nil, false
+	}
+	return &domain.SwapIntent{Path: path, AmountOut: amountOut, AmountInMax: amountInMax, Recipient: to, Deadline: deadline}, true
+}
+
+// (amountOutMin, path, to, deadline), paying tx.Value.
+func decodeV2ExactETHIn(tx domain.Tx, args []byte) (*domain.SwapIntent, bool) {
+	amountOutMin, ok1 := abiUint(args, 0)
+	path, ok2 := abiAddressArray(args, 1)
+	to, ok3 := abiAddress(args, 2)
+	deadline, ok4 := abiUint(args, 3)
+	if !ok1 || !ok2 || !ok3 || !ok4 || len(path) < 2 {
+		return nil, false
+	}
+	return &domain.SwapIntent{Path: path, AmountIn: txValue(tx), AmountOutMin: amountOutMin, Recipient: to, Deadline: deadline}, true
+}
+
+// (amountOut, path, to, deadline), paying at most tx.Value.
+func decodeV2ETHExactOut(tx domain.Tx, args []byte) (*domain.SwapIntent, bool) {
+	amountOut, ok1 := abiUint(args, 0)
+	path, ok2 := abiAddressArray(args, 1)
+	to, ok3 := abiAddress(args, 2)
+	deadline, ok4 := abiUint(args, 3)
+	if !ok1 || !ok2 || !ok3 || !ok4 || len(path) < 2 {
+		return nil, false
+	}
+	return &domain.SwapIntent{Path: path, AmountOut: amountOut, AmountInMax: txValue(tx), Recipient: to, Deadline: deadline}, true
+}
+
+// ((tokenIn, tokenOut, fee, recipient, [deadline,] amount, limit, sqrtPriceLimitX96))
+func decodeV3Single(exactIn, withDeadline bool) func(domain.Tx, []byte) (*domain.SwapIntent, bool) {
+	return func(tx domain.Tx, args []byte) (*domain.SwapIntent, bool) {
+		tokenIn, ok1 := abiAddress(args, 0)
+		tokenOut, ok2 := abiAddress(args, 1)
+		recipient, ok3 := abiAddress(args, 3)
+		if !ok1 || !ok2 || !ok3 {
+			return nil, false
+		}
+		intent := &domain.SwapIntent{Path: []string{tokenIn, tokenOut}, Recipient: recipient}
+		next := 4
+		if withDeadline {
+			deadline, ok := abiUint(args, next)
+			if !ok {
+				return nil, false
+			}
+			intent.Deadline = deadline
+			next++
+		}
+		amount, ok1 := abiUint(args, next)
+		limit, ok2 := abiUint(args, next+1)
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		setIntentAmounts(intent, exactIn, amount, limit)
+		return intent, true
+	}
+}
+
+// ((path, recipient, [deadline,] amount, limit)); the tuple is dynamic so the
+// first argument word is its offset.
+func decodeV3Path(exactIn, withDeadline bool) func(domain.Tx, []byte) (*domain.SwapIntent, bool) {
+	return func(tx domain.Tx, args []byte) (*domain.SwapIntent, bool) {
+		offset, ok := abiOffset(args, 0)
+		if !ok {
+			return nil, false
+		}
+		params := args[offset:]
+		encoded, ok1 := abiBytes(params, 0)
+		recipient, ok2 := abiAddress(params, 1)
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		path, ok := decodeV3EncodedPath(encoded)
+		if !ok {
+			return nil, false
+		}
+		if !exactIn {
+			// exactOutput paths are encoded from tokenOut back to tokenIn.
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+		}
+		intent := &domain.SwapIntent{Path: path, Recipient: recipient}
+		next := 2
+		if withDeadline {
+			deadline, ok := abiUint(params, next)
+			if !ok {
+				return nil, false
+			}
+			intent.Deadline = deadline
+			next++
+		}
+		amount, ok1 := abiUint(params, next)
+		limit, ok2 := abiUint(params, next+1)
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		setIntentAmounts(intent, exactIn, amount, limit)
+		return intent, true
+	}
+}
+
+// (recipient, amount, limit, path, payerIsUser); the plan's deadline is not
+// part of the command input.
+func decodeUniversalV3(exactIn bool) func(domain.Tx, []byte) (*domain.SwapIntent, bool) {
+	return func(tx domain.Tx, args []byte) (*domain.SwapIntent, bool) {
+		recipient, ok1 := abiAddress(args, 0)
+		amount, ok2 := abiUint(args, 1)
+		limit, ok3 := abiUint(args, 2)
+		encoded, ok4 := abiBytes(args, 3)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return nil, false
+		}
+		path, ok := decodeV3EncodedPath(encoded)
+		if !ok {
+			return nil, false
+		}
+		if !exactIn {
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+		}
+		intent := &domain.SwapIntent{Path: path, Recipient: recipient}
+		setIntentAmounts(intent, exactIn, amount, limit)
+		return intent, true
+	}
+}
+
+// (recipient, amount, limit, path, payerIsUser)
+func decodeUniversalV2(exactIn bool) func(domain.Tx, []byte) (*domain.SwapIntent, bool) {
+	return func(tx domain.Tx, args []byte) (*domain.SwapIntent, bool) {
+		recipient, ok1 := abiAddress(args, 0)
+		amount, ok2 := abiUint(args, 1)
+		limit, ok3 := abiUint(args, 2)
+		path, ok4 := abiAddressArray(args, 3)
+		if !ok1 || !ok2 || !ok3 || !ok4 || len(path) < 2 {
+			return nil, false
+		}
+		intent := &domain.SwapIntent{Path: path, Recipient: recipient}
+		setIntentAmounts(intent, exactIn, amount, limit)
+		return intent, true
+	}
+}
+
+// decodeV3EncodedPath splits token(20) | fee(3) | token(20) | ... into tokens.
+func decodeV3EncodedPath(encoded []byte) ([]string, bool) {
+	if len(encoded) < 43 || (len(encoded)-20)%23 != 0 {
+		return nil, false
+	}
+	var path []string
+	for i := 0; i+20 <= len(encoded); i += 23 {
+		path = append(path, strings.ToLower(common.BytesToAddress(encoded[i:i+20]).Hex()))
+	}
+	return path, true
+}
+
+func setIntentAmounts(intent *domain.SwapIntent, exactIn bool, amount, limit *big.Int) {
+	if exactIn {
+		intent.AmountIn = amount
+		intent.AmountOutMin = limit
+		return
+	}
+	intent.AmountOut = amount
+	intent.AmountInMax = limit
+}
+
+func txValue(tx domain.Tx) *big.Int {
+	if tx.Value == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(tx.Value)
+}
+
+func formatIntentDetails(intent domain.SwapIntent) string {
+	tokenIn := intent.Path[0]
+	tokenOut := intent.Path[len(intent.Path)-1]
+	if intent.AmountIn != nil {
+		return fmt.Sprintf("%s %s in=%s %s minOut=%s %s hops=%d",
+			intent.Method, intent.Router, intent.AmountIn, tokenIn,
+			formatOptional(intent.AmountOutMin), tokenOut, len(intent.Path)-1)
+	}
+	return fmt.Sprintf("%s %s out=%s %s maxIn=%s %s hops=%d",
+		intent.Method, intent.Router, formatOptional(intent.AmountOut), tokenOut,
+		formatOptional(intent.AmountInMax), tokenIn, len(intent.Path)-1)
+}
+
+func formatOptional(v *big.Int) string {
+	if v == nil {
+		return "?"
+	}
+	return v.String()
+}
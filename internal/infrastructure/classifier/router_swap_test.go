@@ -0,0 +1,32 @@
+package classifier
+
+import (
+	"context"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+func TestRouterSwapClassifierSkipsTxsWithLogs(t *testing.T) {
+	const router = "0x68b3465833fb72a70ecdf485e0e4c7bd8665fc45"
+	to := router
+	tx := domain.Tx{
+		From: "0x1111111111111111111111111111111111111111",
+		To:   &to,
+		Data: calldata("04e45aaf", words(
+			"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			500, "0x1111111111111111111111111111111111111111", 1000, 900, 0)),
+	}
+	result, ok, err := RouterSwapClassifier{}.Classify(context.Background(), tx)
+	if err != nil || !ok || result.Type != domain.ClassificationDexSwapIntent {
+		t.Fatalf("Classify = %s, %v, %v", result.Type, ok, err)
+	}
+	if result.Intent.AmountIn.Int64() != 1000 || len(result.Intent.Path) != 2 {
+		t.Fatalf("intent = %+v", result.Intent)
+	}
+
+	tx.Logs = []domain.Log{{Address: router}}
+	if _, ok, _ := (RouterSwapClassifier{}).Classify(context.Background(), tx); ok {
+		t.Fatal("classified a tx whose Swap logs are available")
+	}
+}
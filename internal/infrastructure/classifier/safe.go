@@ -0,0 +1,206 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	safeExecTransactionSelector = "6a761202"
+	safeMultiSendSelector       = "8d80ff0a"
+	safeExecutionSuccessTopic   = "0x442e715f626346e8c54381002da614f62bee8d27386535b2521ec8540898556e"
+	safeExecutionFailureTopic   = "0x23428b18acfb3ea64b08dc0c1d296ea9c09702c09083ca5272e64d115b687d23"
+
+	safeOperationCall         = "call"
+	safeOperationDelegateCall = "delegatecall"
+)
+
+// SafeUnwrapper decodes Safe execTransaction calls into SAFE_EXEC with the
+// Safe's inner call as child; a delegatecall to MultiSend becomes one child
+// per batched call instead. The Safe's ExecutionSuccess/ExecutionFailure
+// event gives the outcome and the safeTxHash, from which the ECDSA signers
+// are recovered.
+type SafeUnwrapper struct{}
+
+func (SafeUnwrapper) Unwrap(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, []domain.InnerCall, bool, error) {
+	if tx.To == nil || selectorHex(tx.Data) != safeExecTransactionSelector {
+		return current, nil, false, nil
+	}
+	args := tx.Data[4:]
+	to, ok1 := abiAddress(args, 0)
+	value, ok2 := abiUint(args, 1)
+	data, ok3 := abiBytes(args, 2)
+	operation, ok4 := abiUint(args, 3)
+	signatures, ok5 := abiBytes(args, 9)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || operation.Cmp(big.NewInt(1)) > 0 {
+		return current, nil, false, nil
+	}
+
+	safe := strings.ToLower(*tx.To)
+	exec := &domain.SafeExecution{
+		Safe:      safe,
+		To:        to,
+		Value:     value,
+		Operation: safeOperationCall,
+	}
+	if operation.Sign() != 0 {
+		exec.Operation = safeOperationDelegateCall
+	}
+	var receipt *domain.Receipt
+	if log, ok := safeExecutionLog(tx.Logs, safe); ok {
+		succeeded := log.Topics[0] == safeExecutionSuccessTopic
+		exec.Success = &succeeded
+		exec.SafeTxHash, exec.Payment = safeExecutionData(log)
+		receipt = &domain.Receipt{Success: succeeded}
+	}
+	exec.Signatures = safeSigners(signatures, exec.SafeTxHash, tx.From)
+
+	var calls []domain.InnerCall
+	if batch, ok := decodeMultiSend(data); ok && exec.Operation == safeOperationDelegateCall {
+		exec.MultiSend = true
+		for _, call := range batch {
+			calls = append(calls, domain.InnerCall{Tx: innerTx(tx, safe, call, logsFrom(tx.Logs, call.to), receipt)})
+		}
+	} else {
+		call := accountCall{to: to, value: value, data: data}
+		calls = append(calls, domain.InnerCall{Tx: innerTx(tx, safe, call, tx.Logs, receipt)})
+	}
+
+	updated := current
+	updated.Type = domain.ClassificationSafeExec
+	updated.Safe = exec
+	if updated.ToLabel == "" {
+		updated.ToLabel = "Safe"
+	}
+	updated.Details = formatSafeDetails(*exec, len(calls))
+	updated.Evidence = &domain.Evidence{Rule: "Safe execTransaction calldata", Selector: current.Selector, Address: safe}
+	return updated, calls, true, nil
+}
+
+func safeExecutionLog(logs []domain.Log, safe string) (domain.Log, bool) {
+	for _, log := range logs {
+		if len(log.Topics) == 0 || strings.ToLower(log.Address) != safe {
+			continue
+		}
+		if log.Topics[0] == safeExecutionSuccessTopic || log.Topics[0] == safeExecutionFailureTopic {
+			return log, true
+		}
+	}
+	return domain.Log{}, false
+}
+
+// safeExecutionData reads (bytes32 txHash, uint256 payment); Safe v1.4
+// indexes the hash, earlier versions keep both fields in data.
+func safeExecutionData(log domain.Log) (string, *big.Int) {
+	if len(log.Topics) > 1 {
+		payment, _ := abiUint(log.Data, 0)
+		return log.Topics[1], payment
+	}
+	word, ok := abiWord(log.Data, 0)
+	if !ok {
+		return "", nil
+	}
+	payment, _ := abiUint(log.Data, 1)
+	return common.BytesToHash(word).Hex(), payment
+}
+
+// safeSigners splits the packed 65-byte {r, s, v} signatures. v selects the
+// scheme: 0 is an EIP-1271 contract signature whose s points at dynamic data
+// appended after the static part, 1 an approved hash (r is the owner), above
+// 30 an eth_sign signature with v+4, anything else plain ECDSA.
+func safeSigners(signatures []byte, safeTxHash, executor string) []domain.SafeSignature {
+	var out []domain.SafeSignature
+	limit := len(signatures)
+	for i := 0; (i+1)*65 <= limit; i++ {
+		sig := signatures[i*65 : (i+1)*65]
+		r, s, v := sig[:32], sig[32:64], sig[64]
+		owner := strings.ToLower(common.BytesToAddress(r).Hex())
+		switch {
+		case v == 0:
+			out = append(out, domain.SafeSignature{Signer: owner, Method: domain.SafeSignatureContract})
+			if offset := new(big.Int).SetBytes(s); offset.IsInt64() && offset.Int64() < int64(limit) {
+				limit = int(offset.Int64())
+			}
+		case v == 1:
+			method := domain.SafeSignatureApprovedHash
+			if strings.EqualFold(owner, executor) {
+				method = domain.SafeSignatureExecutor
+			}
+			out = append(out, domain.SafeSignature{Signer: owner, Method: method})
+		case v > 30:
+			out = append(out, domain.SafeSignature{Signer: recoverSafeSigner(sig, safeTxHash, true), Method: domain.SafeSignatureEthSign})
+		default:
+			out = append(out, domain.SafeSignature{Signer: recoverSafeSigner(sig, safeTxHash, false), Method: domain.SafeSignatureECDSA})
+		}
+	}
+	return out
+}
+
+func recoverSafeSigner(sig []byte, safeTxHash string, ethSign bool) string {
+	if safeTxHash == "" {
+		return ""
+	}
+	hash := common.HexToHash(safeTxHash).Bytes()
+	v := sig[64] - 27
+	if ethSign {
+		hash = crypto.Keccak256([]byte("\x19Ethereum Signed Message:\n32"), hash)
+		v -= 4
+	}
+	normalized := append(append([]byte(nil), sig[:64]...), v)
+	pub, err := crypto.SigToPub(hash, normalized)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(crypto.PubkeyToAddress(*pub).Hex())
+}
+
+// decodeMultiSend reads multiSend(bytes transactions), a concatenation of
+// (uint8 operation, address to, uint256 value, uint256 dataLength, bytes data).
+func decodeMultiSend(data []byte) ([]accountCall, bool) {
+	if selectorHex(data) != safeMultiSendSelector {
+		return nil, false
+	}
+	packed, ok := abiBytes(data[4:], 0)
+	if !ok {
+		return nil, false
+	}
+	var calls []accountCall
+	for len(packed) > 0 {
+		if len(packed) < 85 {
+			return nil, false
+		}
+		to := strings.ToLower(common.BytesToAddress(packed[1:21]).Hex())
+		value := new(big.Int).SetBytes(packed[21:53])
+		size := new(big.Int).SetBytes(packed[53:85])
+		if !size.IsInt64() || size.Int64() > int64(len(packed)-85) {
+			return nil, false
+		}
+		end := 85 + int(size.Int64())
+		calls = append(calls, accountCall{to: to, value: value, data: packed[85:end]})
+		packed = packed[end:]
+	}
+	return calls, len(calls) > 0
+}
+
+func formatSafeDetails(exec domain.SafeExecution, calls int) string {
+	signers := make([]string, 0, len(exec.Signatures))
+	for _, sig := range exec.Signatures {
+		signer := sig.Signer
+		if signer == "" {
+			signer = "?"
+		}
+		signers = append(signers, fmt.Sprintf("%s(%s)", signer, sig.Method))
+	}
+	target := fmt.Sprintf("%s to %s", exec.Operation, exec.To)
+	if exec.MultiSend {
+		target = fmt.Sprintf("MultiSend batch of %d calls", calls)
+	}
+	return fmt.Sprintf("Safe %s executes %s, signed by %s", exec.Safe, target, strings.Join(signers, ", "))
+}
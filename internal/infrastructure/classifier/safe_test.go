@@ -0,0 +1,136 @@
+package classifier
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSafeUnwrapper(t *testing.T) {
+	const (
+		safe      = "0x6666666666666666666666666666666666666666"
+		multiSend = "0x40a2accbd92bca938b02010e17a5b8929b49130d"
+		executor  = "0x1111111111111111111111111111111111111111"
+		owner     = "0x2222222222222222222222222222222222222222"
+		token     = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+		other     = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	)
+	safeTxHash := "0x" + strings.Repeat("ab", 32)
+	approve := calldata("095ea7b3", words(owner, 1))
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+	sign := func(hash []byte, vOffset byte) []byte {
+		sig, err := crypto.Sign(hash, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig[64] += vOffset
+		return sig
+	}
+	approved := func(addr string) []byte {
+		return append(words(addr, 0), 1)
+	}
+	ethSignHash := crypto.Keccak256([]byte("\x19Ethereum Signed Message:\n32"), common.HexToHash(safeTxHash).Bytes())
+
+	exec := func(to string, operation int, data, signatures []byte) []byte {
+		return calldata(safeExecTransactionSelector, tuple(to, 0, tail(abiBytesTail(data)), operation,
+			0, 0, 0, zeroAddress, zeroAddress, tail(abiBytesTail(signatures))))
+	}
+	packed := func(to string, data []byte) []byte {
+		out := append([]byte{0}, addressBytes(to)...)
+		out = append(out, words(0, len(data))...)
+		return append(out, data...)
+	}
+	batch := calldata(safeMultiSendSelector, tuple(tail(abiBytesTail(append(packed(token, approve), packed(other, approve)...)))))
+	success := domain.Log{Address: safe, Topics: []string{safeExecutionSuccessTopic}, Data: words(safeTxHash, 0)}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		targets []string
+		logs    []int
+		signers []domain.SafeSignature
+		batch   bool
+		unwraps bool
+	}{
+		{
+			name:    "call signed by approvals",
+			data:    exec(token, 0, approve, append(approved(executor), approved(owner)...)),
+			targets: []string{token},
+			logs:    []int{2},
+			signers: []domain.SafeSignature{
+				{Signer: executor, Method: domain.SafeSignatureExecutor},
+				{Signer: owner, Method: domain.SafeSignatureApprovedHash},
+			},
+			unwraps: true,
+		},
+		{
+			name:    "call signed by ECDSA and eth_sign",
+			data:    exec(token, 0, approve, append(sign(common.HexToHash(safeTxHash).Bytes(), 27), sign(ethSignHash, 31)...)),
+			targets: []string{token},
+			logs:    []int{2},
+			signers: []domain.SafeSignature{
+				{Signer: signer, Method: domain.SafeSignatureECDSA},
+				{Signer: signer, Method: domain.SafeSignatureEthSign},
+			},
+			unwraps: true,
+		},
+		{
+			name:    "MultiSend batch",
+			data:    exec(multiSend, 1, batch, approved(executor)),
+			targets: []string{token, other},
+			logs:    []int{1, 0},
+			signers: []domain.SafeSignature{{Signer: executor, Method: domain.SafeSignatureExecutor}},
+			batch:   true,
+			unwraps: true,
+		},
+		{name: "unknown operation", data: exec(token, 2, approve, approved(executor))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := safe
+			tx := domain.Tx{Hash: "0x01", From: executor, To: &to, Value: big.NewInt(0), Data: tt.data, Logs: []domain.Log{{Address: token}, success}}
+			current := domain.TxResult{Tx: tx, Type: domain.ClassificationContractCall}
+			result, calls, ok, err := SafeUnwrapper{}.Unwrap(context.Background(), tx, current)
+			if err != nil || ok != tt.unwraps {
+				t.Fatalf("Unwrap = %v, %v", ok, err)
+			}
+			if !ok {
+				return
+			}
+			if result.Type != domain.ClassificationSafeExec || result.Safe == nil {
+				t.Fatalf("result = %s %+v", result.Type, result.Safe)
+			}
+			if result.Safe.MultiSend != tt.batch || result.Safe.SafeTxHash != safeTxHash || result.Safe.Success == nil || !*result.Safe.Success {
+				t.Errorf("execution = %+v", *result.Safe)
+			}
+			if len(result.Safe.Signatures) != len(tt.signers) {
+				t.Fatalf("signatures = %+v", result.Safe.Signatures)
+			}
+			for i, want := range tt.signers {
+				if result.Safe.Signatures[i] != want {
+					t.Errorf("signature %d = %+v, want %+v", i, result.Safe.Signatures[i], want)
+				}
+			}
+			if len(calls) != len(tt.targets) {
+				t.Fatalf("got %d calls, want %d", len(calls), len(tt.targets))
+			}
+			for i, call := range calls {
+				if call.Tx.From != safe || *call.Tx.To != tt.targets[i] || !bytes.Equal(call.Tx.Data, approve) || len(call.Tx.Logs) != tt.logs[i] {
+					t.Errorf("call %d = %s -> %s data %x with %d logs", i, call.Tx.From, *call.Tx.To, call.Tx.Data, len(call.Tx.Logs))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+// knownDelegates are account implementations EOAs commonly delegate to,
+// deployed at the same address on every chain.
+var knownDelegates = map[string]string{
+	"0x63c0c19a282a1b52b07dd5a65b58948a07dae32b": "metamask-delegator",
+	"0x4cd241e8d1510e30b2076397afc7508ae59c66c9": "simple-7702-account",
+	"0x000000009b1d0af20d8c6d0a44e162d11f9b8f00": "uniswap-calibur",
+	"0x5a7fc11397e9a8ad41bf10bf13f22b0a63f96f6d": "ambire-7702",
+	"0x7702cb554e6bfb442cb743a7df23154544a7176c": "coinbase-7702-proxy",
+}
+
+// SetCodeClassifier matches EIP-7702 txs carrying an authorization list.
+// Each authorization is flagged when its delegate is neither a known account
+// implementation nor labelled, or when it targets another chain. The call
+// the tx makes with the new code is left to SetCodeUnwrapper.
+type SetCodeClassifier struct {
+	ChainID uint64
+	Labels  map[string]string
+}
+
+func (c SetCodeClassifier) Classify(ctx context.Context, tx domain.Tx) (domain.TxResult, bool, error) {
+	if len(tx.Authorizations) == 0 {
+		return domain.TxResult{}, false, nil
+	}
+	delegations := make([]domain.CodeDelegation, 0, len(tx.Authorizations))
+	for _, auth := range tx.Authorizations {
+		delegations = append(delegations, c.delegation(auth))
+	}
+	return domain.TxResult{
+		Type:     domain.ClassificationSetCode,
+		Selector: selectorHex(tx.Data),
+		SetCode:  delegations,
+		Details:  formatSetCodeDetails(delegations),
+		Evidence: &domain.Evidence{Rule: fmt.Sprintf("tx type 0x%02x with %d authorizations", tx.Type, len(delegations))},
+	}, true, nil
+}
+
+func (c SetCodeClassifier) delegation(auth domain.SetCodeAuthorization) domain.CodeDelegation {
+	delegate := strings.ToLower(auth.Delegate)
+	out := domain.CodeDelegation{
+		Authority: strings.ToLower(auth.Authority),
+		Delegate:  delegate,
+		ChainID:   auth.ChainID,
+		Nonce:     auth.Nonce,
+	}
+	if auth.ChainID != nil && auth.ChainID.Sign() != 0 && c.ChainID != 0 {
+		out.WrongChain = auth.ChainID.Cmp(new(big.Int).SetUint64(c.ChainID)) != 0
+	}
+	if delegate == zeroAddress {
+		out.Revoked = true
+		return out
+	}
+	if name, ok := knownDelegates[delegate]; ok {
+		out.DelegateName = name
+	} else if label, ok := c.Labels[delegate]; ok {
+		out.DelegateName = label
+	} else {
+		out.Unknown = true
+	}
+	return out
+}
+
+func formatSetCodeDetails(delegations []domain.CodeDelegation) string {
+	parts := make([]string, 0, len(delegations))
+	for _, d := range delegations {
+		authority := d.Authority
+		if authority == "" {
+			authority = "invalid signature"
+		}
+		var part string
+		switch {
+		case d.Revoked:
+			part = fmt.Sprintf("%s clears its delegation", authority)
+		case d.Unknown:
+			part = fmt.Sprintf("%s delegates to unknown %s", authority, d.Delegate)
+		default:
+			part = fmt.Sprintf("%s delegates to %s (%s)", authority, d.Delegate, d.DelegateName)
+		}
+		if d.WrongChain {
+			part += fmt.Sprintf(" for chain %s", d.ChainID)
+		}
+		parts = append(parts, part)
+	}
+	return fmt.Sprintf("%d authorizations: %s", len(delegations), strings.Join(parts, "; "))
+}
+
+// SetCodeUnwrapper makes the call of a SET_CODE tx its only child, so the
+// call is classified as if sent without the authorization list (often an
+// execute or executeBatch on the freshly delegated account itself).
+type SetCodeUnwrapper struct{}
+
+func (SetCodeUnwrapper) Unwrap(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, []domain.InnerCall, bool, error) {
+	if current.Type != domain.ClassificationSetCode || tx.To == nil {
+		return current, nil, false, nil
+	}
+	if len(tx.Data) == 0 && (tx.Value == nil || tx.Value.Sign() == 0) {
+		return current, nil, false, nil
+	}
+	var receipt *domain.Receipt
+	if tx.Receipt != nil {
+		receipt = &domain.Receipt{Success: tx.Receipt.Success}
+	}
+	call := accountCall{to: *tx.To, value: txValue(tx), data: tx.Data}
+	return current, []domain.InnerCall{{Tx: innerTx(tx, tx.From, call, tx.Logs, receipt)}}, true, nil
+}
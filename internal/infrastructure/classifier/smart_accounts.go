@@ -0,0 +1,150 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+const (
+	accountExecuteSelector           = "b61d27f6" // execute(address,uint256,bytes)
+	accountExecuteNcCSelector        = "0000189a" // execute_ncC(address,uint256,bytes), Biconomy
+	safeExecuteUserOpSelector        = "7bb37428" // executeUserOp(address,uint256,bytes,uint8), Safe 4337 module
+	safeExecuteUserOpErrSelector     = "541d63c8" // executeUserOpWithErrorString(address,uint256,bytes,uint8)
+	accountExecuteBatchSelector      = "18dfb3c7" // executeBatch(address[],bytes[])
+	accountExecuteBatchValueSelector = "47e1da2a" // executeBatch(address[],uint256[],bytes[])
+)
+
+type accountCall struct {
+	to    string
+	value *big.Int
+	data  []byte
+}
+
+// SmartAccountUnwrapper splits the execute and executeBatch calls of smart
+// contract accounts into the calls they make. A single execute keeps its
+// classification and passes all the tx logs on; a batch becomes BATCH_CALL
+// and each call only gets the logs emitted by its target, since the logs
+// carry no call boundaries.
+type SmartAccountUnwrapper struct{}
+
+func (SmartAccountUnwrapper) Unwrap(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, []domain.InnerCall, bool, error) {
+	if tx.To == nil {
+		return current, nil, false, nil
+	}
+	decoded, ok := accountCalls(tx.Data)
+	if !ok {
+		return current, nil, false, nil
+	}
+	var receipt *domain.Receipt
+	if tx.Receipt != nil {
+		receipt = &domain.Receipt{Success: tx.Receipt.Success}
+	}
+	calls := make([]domain.InnerCall, 0, len(decoded))
+	for _, call := range decoded {
+		logs := tx.Logs
+		if len(decoded) > 1 {
+			logs = logsFrom(tx.Logs, call.to)
+		}
+		calls = append(calls, domain.InnerCall{Tx: innerTx(tx, *tx.To, call, logs, receipt)})
+	}
+	updated := current
+	if len(calls) > 1 {
+		updated.Type = domain.ClassificationBatchCall
+	}
+	updated.Details = fmt.Sprintf("smart account %s executes %d calls", strings.ToLower(*tx.To), len(calls))
+	updated.Evidence = &domain.Evidence{Rule: "smart account execute calldata", Selector: current.Selector}
+	return updated, calls, true, nil
+}
+
+// accountCalls decodes the common account execution methods. Delegate calls
+// of the Safe module are not unwrapped: they run foreign code as the account.
+func accountCalls(data []byte) ([]accountCall, bool) {
+	if len(data) < 4 {
+		return nil, false
+	}
+	args := data[4:]
+	switch selectorHex(data) {
+	case accountExecuteSelector, accountExecuteNcCSelector:
+		return singleAccountCall(args)
+	case safeExecuteUserOpSelector, safeExecuteUserOpErrSelector:
+		operation, ok := abiUint(args, 3)
+		if !ok || operation.Sign() != 0 {
+			return nil, false
+		}
+		return singleAccountCall(args)
+	case accountExecuteBatchSelector:
+		targets, ok1 := abiAddressArray(args, 0)
+		payloads, ok2 := abiDynamicArray(args, 1)
+		if !ok1 || !ok2 || len(targets) != len(payloads) {
+			return nil, false
+		}
+		return batchAccountCalls(targets, nil, payloads)
+	case accountExecuteBatchValueSelector:
+		targets, ok1 := abiAddressArray(args, 0)
+		values, ok2 := abiUintArray(args, 1)
+		payloads, ok3 := abiDynamicArray(args, 2)
+		if !ok1 || !ok2 || !ok3 || len(targets) != len(payloads) || (len(values) != 0 && len(values) != len(targets)) {
+			return nil, false
+		}
+		return batchAccountCalls(targets, values, payloads)
+	}
+	return nil, false
+}
+
+func singleAccountCall(args []byte) ([]accountCall, bool) {
+	to, ok1 := abiAddress(args, 0)
+	value, ok2 := abiUint(args, 1)
+	data, ok3 := abiBytes(args, 2)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, false
+	}
+	return []accountCall{{to: to, value: value, data: data}}, true
+}
+
+// batchAccountCalls pairs targets with payloads; an empty values array means
+// every call sends no value.
+func batchAccountCalls(targets []string, values []*big.Int, payloads [][]byte) ([]accountCall, bool) {
+	calls := make([]accountCall, 0, len(targets))
+	for i, to := range targets {
+		data, ok := abiBytesBody(payloads[i])
+		if !ok {
+			return nil, false
+		}
+		value := big.NewInt(0)
+		if len(values) > 0 {
+			value = values[i]
+		}
+		calls = append(calls, accountCall{to: to, value: value, data: data})
+	}
+	return calls, true
+}
+
+// innerTx builds the tx an account would have sent to make the call itself.
+// It shares the wrapper's hash; receipt only carries the success flag, since
+// the gas was paid by the wrapper.
+func innerTx(tx domain.Tx, from string, call accountCall, logs []domain.Log, receipt *domain.Receipt) domain.Tx {
+	to := call.to
+	return domain.Tx{
+		Hash:    tx.Hash,
+		From:    strings.ToLower(from),
+		To:      &to,
+		Value:   call.value,
+		Data:    call.data,
+		Logs:    logs,
+		Receipt: receipt,
+	}
+}
+
+func logsFrom(logs []domain.Log, address string) []domain.Log {
+	var out []domain.Log
+	for _, log := range logs {
+		if strings.EqualFold(log.Address, address) {
+			out = append(out, log)
+		}
+	}
+	return out
+}
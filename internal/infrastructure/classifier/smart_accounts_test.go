@@ -0,0 +1,93 @@
+package classifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+// abiBytesTail encodes b as the tail of a bytes argument.
+func abiBytesTail(b []byte) []byte {
+	padded := append(append([]byte{}, b...), make([]byte, (32-len(b)%32)%32)...)
+	return words(len(b), padded)
+}
+
+func calldata(selector string, args []byte) []byte {
+	sel, err := hex.DecodeString(selector)
+	if err != nil {
+		panic(err)
+	}
+	return append(sel, args...)
+}
+
+func TestSmartAccountUnwrapper(t *testing.T) {
+	const account = "0x9999999999999999999999999999999999999999"
+	const a, b = "0x1111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222"
+	payloadA, payloadB := []byte{0xa9, 0x05, 0x9c, 0xbb}, []byte{0x09, 0x5e, 0xa7, 0xb3}
+
+	// executeBatch(address[],bytes[]): targets at 0x40, payloads at 0xa0.
+	batch := func(first, second int) []byte {
+		return calldata(accountExecuteBatchSelector, words(64, 160,
+			2, a, b,
+			2, first, second, abiBytesTail(payloadA), abiBytesTail(payloadB)))
+	}
+	tests := []struct {
+		name  string
+		data  []byte
+		want  []accountCall
+		class domain.ClassificationType
+		ok    bool
+	}{
+		{
+			name:  "execute",
+			data:  calldata(accountExecuteSelector, words(a, 5, 96, abiBytesTail(payloadA))),
+			want:  []accountCall{{to: a, value: big.NewInt(5), data: payloadA}},
+			class: domain.ClassificationContractCall,
+			ok:    true,
+		},
+		{
+			name: "executeBatch",
+			data: batch(64, 128),
+			want: []accountCall{
+				{to: a, value: big.NewInt(0), data: payloadA},
+				{to: b, value: big.NewInt(0), data: payloadB},
+			},
+			class: domain.ClassificationBatchCall,
+			ok:    true,
+		},
+		{name: "executeBatch with aliased payloads", data: batch(64, 64)},
+		{name: "executeBatch with a short payload list", data: calldata(accountExecuteBatchSelector, words(64, 160, 2, a, b, 1, 32, abiBytesTail(payloadA)))},
+		{name: "execute with a truncated payload", data: calldata(accountExecuteSelector, words(a, 0, 96, 64))},
+		{name: "other selector", data: calldata("a9059cbb", words(a, 1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := account
+			tx := domain.Tx{Hash: "0x01", From: "0x8888888888888888888888888888888888888888", To: &to, Value: big.NewInt(0), Data: tt.data}
+			current := domain.TxResult{Tx: tx, Type: domain.ClassificationContractCall}
+			result, calls, ok, err := SmartAccountUnwrapper{}.Unwrap(context.Background(), tx, current)
+			if err != nil || ok != tt.ok {
+				t.Fatalf("Unwrap = %v, %v; want ok=%v", ok, err, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if result.Type != tt.class {
+				t.Errorf("type = %s, want %s", result.Type, tt.class)
+			}
+			if len(calls) != len(tt.want) {
+				t.Fatalf("got %d calls, want %d", len(calls), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := calls[i].Tx
+				if got.From != account || *got.To != want.to || got.Value.Cmp(want.value) != 0 || !bytes.Equal(got.Data, want.data) {
+					t.Errorf("call %d = %s -> %s value %s data %x", i, got.From, *got.To, got.Value, got.Data)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package classifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+const (
+	eip1967ImplementationSlot = "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"
+	eip1967BeaconSlot         = "0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50"
+	eip1967AdminSlot          = "0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103"
+	// keccak256("org.zeppelinos.proxy.implementation"), used by pre-1967
+	// OpenZeppelin proxies.
+	zeppelinOSImplementationSlot = "0x7050c9e0f4ca769c69bd3a8ef740bc37934f8e2c036e5a723fd8ee048ed3f8c3"
+)
+
+// delegationPrefix marks EIP-7702 delegated EOA code: 0xef0100 || address.
+var delegationPrefix = []byte{0xef, 0x01, 0x00}
+
+var proxySlots = []struct {
+	slot      string
+	name      string
+	classType domain.ClassificationType
+}{
+	{eip1967ImplementationSlot, "EIP-1967 implementation", domain.ClassificationProxyUpgrade},
+	{eip1967BeaconSlot, "EIP-1967 beacon", domain.ClassificationProxyUpgrade},
+	{zeppelinOSImplementationSlot, "ZeppelinOS implementation", domain.ClassificationProxyUpgrade},
+	{eip1967AdminSlot, "EIP-1967 admin", domain.ClassificationProxyAdminChange},
+}
+
+// ProxyUpgradeResolver detects writes to the well-known proxy slots of an
+// existing contract, whether or not an Upgraded/AdminChanged event was
+// emitted. Proxies created in the same tx are initialised, not upgraded.
+type ProxyUpgradeResolver struct{}
+
+func (ProxyUpgradeResolver) ResolveState(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+	for _, known := range proxySlots {
+		for _, account := range tx.StateDiff {
+			if deployedInTx(account) {
+				continue
+			}
+			for _, slot := range account.Storage {
+				if !strings.EqualFold(slot.Slot, known.slot) {
+					continue
+				}
+				proxy := strings.ToLower(account.Address)
+				updated := current
+				updated.Type = known.classType
+				updated.Details = fmt.Sprintf("proxy %s %s %s -> %s", proxy, known.name, slotAddress(slot.Before), slotAddress(slot.After))
+				updated.Evidence = &domain.Evidence{
+					Rule:     known.name + " slot changed",
+					Selector: current.Selector,
+					Address:  proxy,
+					Slot:     known.slot,
+				}
+				return updated, true, nil
+			}
+		}
+	}
+	return current, false, nil
+}
+
+// CodeChangeResolver detects code replaced on an account that already had
+// code or that gets an EIP-7702 delegation: code cleared by a self-destruct
+// and delegations set or cleared on an EOA. Code deployed where there was
+// none is a deployment, even if the address already held a balance.
+type CodeChangeResolver struct{}
+
+func (CodeChangeResolver) ResolveState(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+	for _, account := range tx.StateDiff {
+		if deployedInTx(account) || !account.CodeChanged {
+			continue
+		}
+		addr := strings.ToLower(account.Address)
+		updated := current
+		updated.Type = domain.ClassificationCodeChange
+		updated.Details = fmt.Sprintf("code of %s changed: %s -> %s", addr, describeCode(account.CodeBefore), describeCode(account.CodeAfter))
+		updated.Evidence = &domain.Evidence{
+			Rule:     "account code changed",
+			Selector: current.Selector,
+			Address:  addr,
+		}
+		return updated, true, nil
+	}
+	return current, false, nil
+}
+
+// deployedInTx reports a contract deployed by the tx: the account is new or
+// had no code before. Prefunded addresses exist before their deployment.
+func deployedInTx(account domain.AccountDiff) bool {
+	if account.Created {
+		return true
+	}
+	return account.CodeChanged && len(account.CodeBefore) == 0 && len(account.CodeAfter) > 0 && !isDelegation(account.CodeAfter)
+}
+
+func isDelegation(code []byte) bool {
+	return len(code) == 23 && bytes.HasPrefix(code, delegationPrefix)
+}
+
+func describeCode(code []byte) string {
+	switch {
+	case len(code) == 0:
+		return "empty"
+	case isDelegation(code):
+		return fmt.Sprintf("delegation to 0x%x", code[3:])
+	default:
+		return fmt.Sprintf("%d bytes", len(code))
+	}
+}
+
+func slotAddress(value string) string {
+	if len(value) < 42 {
+		return value
+	}
+	return strings.ToLower("0x" + value[len(value)-40:])
+}
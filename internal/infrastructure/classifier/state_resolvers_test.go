@@ -0,0 +1,57 @@
+package classifier
+
+import (
+	"context"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+func TestCodeChangeResolver(t *testing.T) {
+	const account = "0x1111111111111111111111111111111111111111"
+	runtime := []byte{0x60, 0x80, 0x60, 0x40, 0x52}
+	delegation := append([]byte{0xef, 0x01, 0x00}, make([]byte, 20)...)
+	tests := []struct {
+		name  string
+		diff  domain.AccountDiff
+		match bool
+	}{
+		{"new account", domain.AccountDiff{Created: true, CodeChanged: true, CodeAfter: runtime}, false},
+		{"prefunded deployment", domain.AccountDiff{CodeChanged: true, BalanceChanged: true, CodeAfter: runtime}, false},
+		{"code cleared", domain.AccountDiff{CodeChanged: true, CodeBefore: runtime}, true},
+		{"code replaced", domain.AccountDiff{CodeChanged: true, CodeBefore: runtime, CodeAfter: []byte{0x00}}, true},
+		{"delegation set", domain.AccountDiff{CodeChanged: true, CodeAfter: delegation}, true},
+		{"delegation cleared", domain.AccountDiff{CodeChanged: true, CodeBefore: delegation}, true},
+		{"code unchanged", domain.AccountDiff{BalanceChanged: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := tt.diff
+			diff.Address = account
+			current := domain.TxResult{Type: domain.ClassificationContractCall}
+			res, ok, err := CodeChangeResolver{}.ResolveState(context.Background(), domain.Tx{StateDiff: []domain.AccountDiff{diff}}, current)
+			if err != nil || ok != tt.match {
+				t.Fatalf("ResolveState = %v, %v; want match %v", ok, err, tt.match)
+			}
+			if ok && res.Type != domain.ClassificationCodeChange {
+				t.Fatalf("type = %s", res.Type)
+			}
+		})
+	}
+}
+
+func TestProxyUpgradeResolverSkipsPrefundedDeployment(t *testing.T) {
+	diff := domain.AccountDiff{
+		Address:     "0x1111111111111111111111111111111111111111",
+		CodeChanged: true,
+		CodeAfter:   []byte{0x60, 0x80},
+		Storage:     []domain.SlotDiff{{Slot: eip1967ImplementationSlot, After: "0x0000000000000000000000002222222222222222222222222222222222222222"}},
+	}
+	if _, ok, _ := (ProxyUpgradeResolver{}).ResolveState(context.Background(), domain.Tx{StateDiff: []domain.AccountDiff{diff}}, domain.TxResult{}); ok {
+		t.Fatal("proxy initialised at deployment reported as upgrade")
+	}
+	diff.CodeChanged, diff.CodeAfter = false, nil
+	if _, ok, _ := (ProxyUpgradeResolver{}).ResolveState(context.Background(), domain.Tx{StateDiff: []domain.AccountDiff{diff}}, domain.TxResult{}); !ok {
+		t.Fatal("implementation slot write on an existing proxy not reported")
+	}
+}
@@ -0,0 +1,55 @@
+package classifier
+
+import (
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+const (
+	StandardERC20  = "ERC20"
+	StandardERC721 = "ERC721"
+)
+
+func TokenTransfers(tx domain.Tx) []domain.TokenTransfer {
+	var out []domain.TokenTransfer
+	for _, log := range tx.Logs {
+		if len(log.Topics) == 0 || log.Topics[0] != transferEventTopic {
+			continue
+		}
+		switch len(log.Topics) {
+		case 3:
+			if len(log.Data) < 32 {
+				continue
+			}
+			out = append(out, domain.TokenTransfer{
+				LogIndex: log.Index,
+				Standard: StandardERC20,
+				Token:    strings.ToLower(log.Address),
+				From:     topicToAddress(log.Topics[1]),
+				To:       topicToAddress(log.Topics[2]),
+				Amount:   new(big.Int).SetBytes(log.Data[:32]),
+			})
+		case 4:
+			out = append(out, domain.TokenTransfer{
+				LogIndex: log.Index,
+				Standard: StandardERC721,
+				Token:    strings.ToLower(log.Address),
+				From:     topicToAddress(log.Topics[1]),
+				To:       topicToAddress(log.Topics[2]),
+				Amount:   big.NewInt(1),
+				TokenID:  topicToBig(log.Topics[3]),
+			})
+		}
+	}
+	return out
+}
+
+func topicToBig(topic string) *big.Int {
+	v, ok := new(big.Int).SetString(strings.TrimPrefix(topic, "0x"), 16)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return v
+}
@@ -0,0 +1,222 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	userOperationEventTopic = "0x49628fd1471006c1482da88028e9ce4dbb080b815c9b0344d39e5a8e6ec1419f"
+	beforeExecutionTopic    = "0xbb47ee3e183a558b1a2ff0874b079f3fc5478b7454eacf2bfc5af2ff5878f972"
+
+	handleOpsV06Selector = "1fad948c"
+	handleOpsV07Selector = "765e827f"
+)
+
+// entryPoints maps the canonical EntryPoint deployments, which share their
+// address on every chain, to their version. v0.8 keeps the v0.7 ABI.
+var entryPoints = map[string]string{
+	"0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789": "v0.6",
+	"0x0000000071727de22e5e9d8baf0edac6f37da032": "v0.7",
+	"0x4337084d9e255ff0702461cf8895ce9e3b5ff108": "v0.8",
+}
+
+// UserOpUnwrapper splits an ERC-4337 bundle into one inner call per user
+// operation. Operations come from handleOps calldata when the bundler called
+// the EntryPoint directly and are matched by sender and nonce to their
+// UserOperationEvent for success and gas cost; otherwise the events alone
+// describe them. Each inner call gets the logs emitted while the operation
+// executed, i.e. since the previous UserOperationEvent.
+type UserOpUnwrapper struct{}
+
+type userOpEvent struct {
+	op   domain.UserOperation
+	logs []domain.Log
+	log  domain.Log
+}
+
+type decodedUserOp struct {
+	sender    string
+	nonce     *big.Int
+	callData  []byte
+	paymaster string
+}
+
+func (UserOpUnwrapper) Unwrap(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, []domain.InnerCall, bool, error) {
+	events := userOpEvents(tx.Logs)
+	var ops []decodedUserOp
+	entryPoint, version := "", ""
+	if tx.To != nil {
+		if v, ok := entryPoints[strings.ToLower(*tx.To)]; ok {
+			entryPoint, version = strings.ToLower(*tx.To), v
+			ops, _ = decodeHandleOps(tx.Data, version)
+		}
+	}
+	if len(ops) == 0 && len(events) == 0 {
+		return current, nil, false, nil
+	}
+
+	updated := current
+	updated.Type = domain.ClassificationUserOpBundle
+	var calls []domain.InnerCall
+	if len(ops) > 0 {
+		for _, op := range ops {
+			userOp := domain.UserOperation{
+				EntryPoint: entryPoint,
+				Version:    version,
+				Sender:     op.sender,
+				Nonce:      op.nonce,
+				Paymaster:  op.paymaster,
+			}
+			var logs []domain.Log
+			if event, ok := matchUserOpEvent(events, op); ok {
+				userOp = event.op
+				logs = event.logs
+			}
+			calls = append(calls, userOpCall(tx, userOp, op.callData, logs))
+		}
+		beneficiary, _ := abiAddress(tx.Data[4:], 1)
+		updated.Details = fmt.Sprintf("%d user operations via EntryPoint %s, beneficiary %s", len(ops), version, beneficiary)
+		updated.Evidence = &domain.Evidence{Rule: "EntryPoint handleOps calldata", Selector: current.Selector, Address: entryPoint}
+	} else {
+		for _, event := range events {
+			calls = append(calls, userOpCall(tx, event.op, nil, event.logs))
+		}
+		updated.Details = fmt.Sprintf("%d user operations via EntryPoint %s", len(events), events[0].op.Version)
+		updated.Evidence = logEvidence("UserOperationEvent logs", current.Selector, events[0].log)
+	}
+	return updated, calls, true, nil
+}
+
+// userOpEvents collects the UserOperationEvents of known EntryPoints with the
+// logs each operation emitted before it.
+func userOpEvents(logs []domain.Log) []userOpEvent {
+	var out []userOpEvent
+	start := 0
+	for i, log := range logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		version, ok := entryPoints[strings.ToLower(log.Address)]
+		if !ok {
+			continue
+		}
+		if log.Topics[0] == beforeExecutionTopic {
+			start = i + 1
+			continue
+		}
+		if log.Topics[0] != userOperationEventTopic || len(log.Topics) < 4 {
+			continue
+		}
+		nonce, ok1 := abiUint(log.Data, 0)
+		success, ok2 := abiUint(log.Data, 1)
+		gasCost, ok3 := abiUint(log.Data, 2)
+		gasUsed, ok4 := abiUint(log.Data, 3)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			continue
+		}
+		succeeded := success.Sign() != 0
+		paymaster := topicToAddress(log.Topics[3])
+		if paymaster == zeroAddress {
+			paymaster = ""
+		}
+		out = append(out, userOpEvent{
+			op: domain.UserOperation{
+				EntryPoint:    strings.ToLower(log.Address),
+				Version:       version,
+				Hash:          log.Topics[1],
+				Sender:        topicToAddress(log.Topics[2]),
+				Nonce:         nonce,
+				Paymaster:     paymaster,
+				Success:       &succeeded,
+				ActualGasCost: gasCost,
+				ActualGasUsed: gasUsed,
+			},
+			logs: logs[start:i],
+			log:  log,
+		})
+		start = i + 1
+	}
+	return out
+}
+
+func matchUserOpEvent(events []userOpEvent, op decodedUserOp) (userOpEvent, bool) {
+	for _, event := range events {
+		if event.op.Sender == op.sender && event.op.Nonce.Cmp(op.nonce) == 0 {
+			return event, true
+		}
+	}
+	return userOpEvent{}, false
+}
+
+// decodeHandleOps reads handleOps(UserOperation[] ops, address beneficiary).
+// v0.6 ops are (sender, nonce, initCode, callData, callGasLimit,
+// verificationGasLimit, preVerificationGas, maxFeePerGas,
+// maxPriorityFeePerGas, paymasterAndData, signature); v0.7 packs the gas
+// fields, moving paymasterAndData to field 7.
+func decodeHandleOps(data []byte, version string) ([]decodedUserOp, bool) {
+	paymasterField := 7
+	switch selectorHex(data) {
+	case handleOpsV06Selector:
+		if version != "v0.6" {
+			return nil, false
+		}
+		paymasterField = 9
+	case handleOpsV07Selector:
+		if version == "v0.6" {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	elems, ok := abiDynamicArray(data[4:], 0)
+	if !ok {
+		return nil, false
+	}
+	ops := make([]decodedUserOp, 0, len(elems))
+	for _, elem := range elems {
+		sender, ok1 := abiAddress(elem, 0)
+		nonce, ok2 := abiUint(elem, 1)
+		callData, ok3 := abiBytes(elem, 3)
+		paymasterAndData, ok4 := abiBytes(elem, paymasterField)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return nil, false
+		}
+		op := decodedUserOp{sender: sender, nonce: nonce, callData: callData}
+		if len(paymasterAndData) >= 20 {
+			op.paymaster = strings.ToLower(common.BytesToAddress(paymasterAndData[:20]).Hex())
+		}
+		ops = append(ops, op)
+	}
+	return ops, true
+}
+
+// userOpCall is the inner call of an operation: the single call the account
+// executes when its callData is a plain execute, otherwise the EntryPoint's
+// call into the account, which SmartAccountUnwrapper splits further.
+func userOpCall(tx domain.Tx, op domain.UserOperation, callData []byte, logs []domain.Log) domain.InnerCall {
+	var receipt *domain.Receipt
+	if op.Success != nil {
+		receipt = &domain.Receipt{Success: *op.Success}
+	}
+	if calls, ok := accountCalls(callData); ok && len(calls) == 1 {
+		return domain.InnerCall{Tx: innerTx(tx, op.Sender, calls[0], logs, receipt), UserOp: &op}
+	}
+	to := op.Sender
+	inner := domain.Tx{
+		Hash:    tx.Hash,
+		From:    op.EntryPoint,
+		To:      &to,
+		Value:   big.NewInt(0),
+		Data:    callData,
+		Logs:    logs,
+		Receipt: receipt,
+	}
+	return domain.InnerCall{Tx: inner, UserOp: &op}
+}
@@ -0,0 +1,139 @@
+package classifier
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+// tail marks an encoded dynamic part of a tuple.
+type tail []byte
+
+// tuple encodes heads and tails: tail parts get an offset, the rest are words.
+func tuple(parts ...any) []byte {
+	var heads, tails []byte
+	for _, part := range parts {
+		if t, ok := part.(tail); ok {
+			heads = append(heads, words(32*len(parts)+len(tails))...)
+			tails = append(tails, t...)
+			continue
+		}
+		heads = append(heads, words(part)...)
+	}
+	return append(heads, tails...)
+}
+
+// dynArray encodes already encoded dynamic elements as the tail of an array.
+func dynArray(elems ...[]byte) []byte {
+	out := words(len(elems))
+	offset := 32 * len(elems)
+	for _, elem := range elems {
+		out = append(out, words(offset)...)
+		offset += len(elem)
+	}
+	for _, elem := range elems {
+		out = append(out, elem...)
+	}
+	return out
+}
+
+func addressBytes(addr string) []byte {
+	return words(addr)[12:]
+}
+
+func TestUserOpUnwrapper(t *testing.T) {
+	const (
+		entryPoint = "0x0000000071727de22e5e9d8baf0edac6f37da032"
+		bundler    = "0x4444444444444444444444444444444444444444"
+		senderA    = "0x1111111111111111111111111111111111111111"
+		senderB    = "0x2222222222222222222222222222222222222222"
+		token      = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+		paymaster  = "0x5555555555555555555555555555555555555555"
+	)
+	approve := calldata("095ea7b3", words(senderB, 1))
+	// A v0.7 PackedUserOperation whose account executes approve on token.
+	packedOp := func(sender string, nonce int, paymasterAndData []byte) []byte {
+		callData := calldata(accountExecuteSelector, tuple(token, 0, tail(abiBytesTail(approve))))
+		return tuple(sender, nonce, tail(abiBytesTail(nil)), tail(abiBytesTail(callData)),
+			0, 0, 0, tail(abiBytesTail(paymasterAndData)), tail(abiBytesTail(nil)))
+	}
+	handleOps := calldata(handleOpsV07Selector, tuple(
+		tail(dynArray(packedOp(senderA, 7, nil), packedOp(senderB, 3, addressBytes(paymaster)))), bundler))
+
+	event := func(sender string, nonce, success int) domain.Log {
+		return domain.Log{
+			Address: entryPoint,
+			Topics:  []string{userOperationEventTopic, intTopic(int64(nonce)), addrTopic(sender), addrTopic(zeroAddress)},
+			Data:    words(nonce, success, 100, 50),
+		}
+	}
+	approval := domain.Log{Address: token}
+	logs := []domain.Log{approval, event(senderA, 7, 1), event(senderB, 3, 0)}
+
+	tests := []struct {
+		name    string
+		to      string
+		data    []byte
+		from    []string
+		targets []string
+		logs    []int
+		success []bool
+	}{
+		{
+			name:    "handleOps calldata",
+			to:      entryPoint,
+			data:    handleOps,
+			from:    []string{senderA, senderB},
+			targets: []string{token, token},
+			logs:    []int{1, 0},
+			success: []bool{true, false},
+		},
+		{
+			name:    "events only",
+			to:      bundler,
+			data:    calldata("deadbeef", nil),
+			from:    []string{entryPoint, entryPoint},
+			targets: []string{senderA, senderB},
+			logs:    []int{1, 0},
+			success: []bool{true, false},
+		},
+		{name: "v0.6 selector on a v0.7 EntryPoint without events", to: entryPoint, data: calldata(handleOpsV06Selector, handleOps[4:])},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := tt.to
+			tx := domain.Tx{Hash: "0x01", From: bundler, To: &to, Value: big.NewInt(0), Data: tt.data}
+			if tt.from != nil {
+				tx.Logs = logs
+			}
+			current := domain.TxResult{Tx: tx, Type: domain.ClassificationContractCall}
+			result, calls, ok, err := UserOpUnwrapper{}.Unwrap(context.Background(), tx, current)
+			if err != nil || ok != (tt.from != nil) {
+				t.Fatalf("Unwrap = %v, %v", ok, err)
+			}
+			if !ok {
+				return
+			}
+			if result.Type != domain.ClassificationUserOpBundle {
+				t.Errorf("type = %s", result.Type)
+			}
+			if len(calls) != len(tt.from) {
+				t.Fatalf("got %d calls, want %d", len(calls), len(tt.from))
+			}
+			for i, call := range calls {
+				if call.Tx.From != tt.from[i] || *call.Tx.To != tt.targets[i] || len(call.Tx.Logs) != tt.logs[i] {
+					t.Errorf("call %d = %s -> %s with %d logs", i, call.Tx.From, *call.Tx.To, len(call.Tx.Logs))
+				}
+				if call.UserOp == nil || call.UserOp.Success == nil || *call.UserOp.Success != tt.success[i] {
+					t.Errorf("call %d user op = %+v", i, call.UserOp)
+				}
+				if tt.to == entryPoint && !bytes.Equal(call.Tx.Data, approve) {
+					t.Errorf("call %d data = %x", i, call.Tx.Data)
+				}
+			}
+		})
+	}
+}
@@ -2,28 +2,63 @@ package ethereum
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"ethClassify/internal/domain"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/sync/errgroup"
 )
 
+type ReaderOptions struct {
+	WithLogs bool
+	Workers  int
+	// TraceSource attaches the call tree to every tx: TraceSourceGeth,
+	// TraceSourceParity or empty to skip tracing.
+	TraceSource string
+	// WithStateDiff attaches the prestateTracer diff of every tx (one
+	// debug_traceTransaction call per tx).
+	WithStateDiff bool
+}
+
 type BlockReader struct {
-	client   *ethclient.Client
-	withLogs bool
+	raw           *rpc.Client
+	client        *ethclient.Client
+	withLogs      bool
+	workers       int
+	traceSource   string
+	withStateDiff bool
 }
 
-func NewBlockReader(rpcURL string, withLogs bool) (*BlockReader, error) {
-	client, err := ethclient.Dial(rpcURL)
+func NewBlockReader(rpcURL string, opts ReaderOptions) (*BlockReader, error) {
+	switch opts.TraceSource {
+	case "", TraceSourceGeth, TraceSourceParity:
+	default:
+		return nil, fmt.Errorf("unknown trace source %q", opts.TraceSource)
+	}
+	raw, err := rpc.Dial(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("connect rpc: %w", err)
 	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
 	return &BlockReader{
-		client:   client,
-		withLogs: withLogs,
+		raw:           raw,
+		client:        ethclient.NewClient(raw),
+		withLogs:      opts.WithLogs,
+		workers:       workers,
+		traceSource:   opts.TraceSource,
+		withStateDiff: opts.WithStateDiff,
 	}, nil
 }
 
@@ -31,77 +66,234 @@ func (r *BlockReader) LatestBlock(ctx context.Context) (domain.Block, error) {
 	if r == nil || r.client == nil {
 		return domain.Block{}, fmt.Errorf("rpc client is not initialized")
 	}
-	block, err := r.client.BlockByNumber(ctx, nil)
+	block, err := r.fetchBlock(ctx, "latest")
 	if err != nil {
 		return domain.Block{}, fmt.Errorf("fetch latest block: %w", err)
 	}
 
-	return convertBlock(ctx, r.client, r.withLogs, block)
+	return r.convertBlock(ctx, block)
 }
 
-func convertBlock(ctx context.Context, client *ethclient.Client, withLogs bool, block *types.Block) (domain.Block, error) {
-	txns := block.Transactions()
-	out := make([]domain.Tx, 0, len(txns))
-	for _, tx := range txns {
-		if withLogs {
-			receipt, err := client.TransactionReceipt(ctx, tx.Hash())
-			if err != nil {
-				return domain.Block{}, fmt.Errorf("fetch receipt for tx %s: %w", tx.Hash(), err)
-			}
-			out = append(out, convertTx(tx, receipt))
-			continue
-		}
-		out = append(out, convertTxNoLogs(tx))
+func (r *BlockReader) BlockByNumber(ctx context.Context, number *big.Int) (domain.Block, error) {
+	if r == nil || r.client == nil {
+		return domain.Block{}, fmt.Errorf("rpc client is not initialized")
+	}
+	block, err := r.fetchBlock(ctx, hexutil.EncodeBig(number))
+	if err != nil {
+		return domain.Block{}, fmt.Errorf("fetch block %s: %w", number, err)
 	}
 
-	return domain.Block{
-		Number:       new(big.Int).Set(block.Number()),
-		Hash:         block.Hash().Hex(),
-		Transactions: out,
-	}, nil
+	return r.convertBlock(ctx, block)
 }
 
-func convertTx(tx *types.Transaction, receipt *types.Receipt) domain.Tx {
-	var toStr *string
-	if tx.To() != nil {
-		addr := tx.To().Hex()
-		toStr = &addr
+func (r *BlockReader) fetchBlock(ctx context.Context, tag string) (rpcBlock, error) {
+	var block *rpcBlock
+	if err := r.raw.CallContext(ctx, &block, "eth_getBlockByNumber", tag, true); err != nil {
+		return rpcBlock{}, err
+	}
+	if block == nil {
+		return rpcBlock{}, domain.ErrNotFound
+	}
+	return *block, nil
+}
+
+func (r *BlockReader) receipt(ctx context.Context, hash common.Hash) (*rpcReceipt, error) {
+	var receipt *rpcReceipt
+	if err := r.raw.CallContext(ctx, &receipt, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, fmt.Errorf("fetch receipt for tx %s: %w", hash, err)
+	}
+	if receipt == nil {
+		return nil, fmt.Errorf("fetch receipt for tx %s: %w", hash, domain.ErrNotFound)
+	}
+	return receipt, nil
+}
+
+func (r *BlockReader) HeadNumber(ctx context.Context) (uint64, error) {
+	if r == nil || r.client == nil {
+		return 0, fmt.Errorf("rpc client is not initialized")
+	}
+	number, err := r.client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("fetch head number: %w", err)
+	}
+	return number, nil
+}
+
+func (r *BlockReader) ChainID(ctx context.Context) (uint64, error) {
+	if r == nil || r.client == nil {
+		return 0, fmt.Errorf("rpc client is not initialized")
+	}
+	id, err := r.client.ChainID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("fetch chain id: %w", err)
+	}
+	return id.Uint64(), nil
+}
+
+func (r *BlockReader) FinalizedNumber(ctx context.Context) (uint64, error) {
+	if r == nil || r.client == nil {
+		return 0, fmt.Errorf("rpc client is not initialized")
+	}
+	header, err := r.client.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	if err != nil {
+		return 0, fmt.Errorf("fetch finalized header: %w", err)
+	}
+	return header.Number.Uint64(), nil
+}
+
+func (r *BlockReader) TransactionByHash(ctx context.Context, hash string) (domain.Tx, error) {
+	if r == nil || r.client == nil {
+		return domain.Tx{}, fmt.Errorf("rpc client is not initialized")
+	}
+	if len(strings.TrimPrefix(hash, "0x")) != 64 {
+		return domain.Tx{}, fmt.Errorf("%w: tx hash %q", domain.ErrInvalidArgument, hash)
+	}
+	txHash := common.HexToHash(hash)
+
+	var tx *rpcTx
+	if err := r.raw.CallContext(ctx, &tx, "eth_getTransactionByHash", txHash); err != nil {
+		return domain.Tx{}, fmt.Errorf("fetch tx %s: %w", txHash, err)
+	}
+	if tx == nil {
+		return domain.Tx{}, fmt.Errorf("fetch tx %s: %w", txHash, domain.ErrNotFound)
+	}
+	if tx.BlockHash == nil {
+		return domain.Tx{}, fmt.Errorf("tx %s is still pending", txHash)
+	}
+	receipt, err := r.receipt(ctx, txHash)
+	if err != nil {
+		return domain.Tx{}, err
 	}
 
-	logs := make([]domain.Log, 0, len(receipt.Logs))
-	for _, l := range receipt.Logs {
-		topics := make([]string, len(l.Topics))
-		for i, t := range l.Topics {
-			topics[i] = t.Hex()
+	out := convertTx(*tx, receipt)
+	if out.Receipt != nil {
+		var header struct {
+			Miner         common.Address `json:"miner"`
+			BaseFeePerGas *hexutil.Big   `json:"baseFeePerGas"`
 		}
-		logs = append(logs, domain.Log{
-			Address: l.Address.Hex(),
-			Topics:  topics,
-			Data:    append([]byte(nil), l.Data...),
+		if err := r.raw.CallContext(ctx, &header, "eth_getBlockByHash", *tx.BlockHash, false); err != nil {
+			return domain.Tx{}, fmt.Errorf("fetch block %s: %w", tx.BlockHash, err)
+		}
+		setFeeRecipient(out.Receipt, header.Miner, header.BaseFeePerGas)
+	}
+	if r.traceSource != "" {
+		calls, err := r.txTrace(ctx, txHash)
+		if err != nil {
+			return domain.Tx{}, err
+		}
+		out.Calls = calls
+	}
+	if r.withStateDiff {
+		diff, err := r.stateDiff(ctx, txHash)
+		if err != nil {
+			return domain.Tx{}, err
+		}
+		out.StateDiff = diff
+	}
+	return out, nil
+}
+
+func (r *BlockReader) convertBlock(ctx context.Context, block rpcBlock) (domain.Block, error) {
+	txns := block.Transactions
+	out := make([]domain.Tx, len(txns))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(r.workers)
+	for i, tx := range txns {
+		g.Go(func() error {
+			if !r.withLogs {
+				out[i] = convertTx(tx, nil)
+			} else {
+				receipt, err := r.receipt(gctx, tx.Hash)
+				if err != nil {
+					return err
+				}
+				out[i] = convertTx(tx, receipt)
+				setFeeRecipient(out[i].Receipt, block.Miner, block.BaseFeePerGas)
+			}
+			if r.withStateDiff {
+				diff, err := r.stateDiff(gctx, tx.Hash)
+				if err != nil {
+					return err
+				}
+				out[i].StateDiff = diff
+			}
+			return nil
 		})
 	}
+	if err := g.Wait(); err != nil {
+		return domain.Block{}, err
+	}
+	if r.traceSource != "" && len(txns) > 0 {
+		hashes := make([]common.Hash, len(txns))
+		for i, tx := range txns {
+			hashes[i] = tx.Hash
+		}
+		traces, err := r.blockTraces(ctx, block.Number.ToInt(), hashes)
+		if err != nil {
+			return domain.Block{}, err
+		}
+		for i := range out {
+			out[i].Calls = traces[strings.ToLower(out[i].Hash)]
+		}
+	}
 
-	return domain.Tx{
-		Hash:  tx.Hash().Hex(),
-		To:    toStr,
-		Value: new(big.Int).Set(tx.Value()),
-		Data:  append([]byte(nil), tx.Data()...),
-		Logs:  logs,
+	return domain.Block{
+		Number:        hexBigOrZero(block.Number),
+		Hash:          block.Hash.Hex(),
+		ParentHash:    block.ParentHash.Hex(),
+		Sequencer:     sequencerInfo(block),
+		BlobGasUsed:   (*uint64)(block.BlobGasUsed),
+		ExcessBlobGas: (*uint64)(block.ExcessBlobGas),
+		Transactions:  out,
+	}, nil
+}
+
+func setFeeRecipient(receipt *domain.Receipt, miner common.Address, baseFee *hexutil.Big) {
+	if receipt == nil {
+		return
+	}
+	receipt.Coinbase = miner.Hex()
+	receipt.BaseFee = optionalBig(baseFee)
+}
+
+func notFound(err error) error {
+	if errors.Is(err, ethereum.NotFound) {
+		return domain.ErrNotFound
 	}
+	return err
 }
 
-func convertTxNoLogs(tx *types.Transaction) domain.Tx {
+// convertTxNoLogs maps a typed tx, as delivered by pending subscriptions.
+func convertTxNoLogs(tx *types.Transaction, from string) domain.Tx {
 	var toStr *string
 	if tx.To() != nil {
 		addr := tx.To().Hex()
 		toStr = &addr
 	}
 
-	return domain.Tx{
+	out := domain.Tx{
 		Hash:  tx.Hash().Hex(),
+		Type:  tx.Type(),
+		From:  from,
 		To:    toStr,
+		Nonce: tx.Nonce(),
 		Value: new(big.Int).Set(tx.Value()),
 		Data:  append([]byte(nil), tx.Data()...),
 		Logs:  nil,
 	}
+	for _, h := range tx.BlobHashes() {
+		out.BlobHashes = append(out.BlobHashes, h.Hex())
+	}
+	if tx.Type() == types.BlobTxType {
+		out.MaxFeePerBlobGas = new(big.Int).Set(tx.BlobGasFeeCap())
+	}
+	out.Authorizations = convertAuthorizations(tx.SetCodeAuthorizations())
+	return out
 }
+
+var (
+	_ domain.BlockReader    = (*BlockReader)(nil)
+	_ domain.TxReader       = (*BlockReader)(nil)
+	_ domain.FinalityReader = (*BlockReader)(nil)
+	_ domain.HeadReader     = (*BlockReader)(nil)
+)
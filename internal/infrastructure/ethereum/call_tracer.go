@@ -0,0 +1,77 @@
+package ethereum
+
+import (
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// callFrame mirrors the JSON produced by geth's callTracer.
+type callFrame struct {
+	Type         string          `json:"type"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to,omitempty"`
+	Value        *hexutil.Big    `json:"value,omitempty"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []callFrame     `json:"calls,omitempty"`
+	Logs         []callLog       `json:"logs,omitempty"`
+}
+
+type callLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+	// Position is the number of subcalls of the emitting frame made before
+	// the log, which lets us rebuild the global emission order.
+	Position hexutil.Uint `json:"position"`
+}
+
+var callTracerWithLogs = map[string]any{
+	"tracer":       "callTracer",
+	"tracerConfig": map[string]any{"withLog": true},
+}
+
+// frameLogs flattens the logs of a call tree in emission order and numbers
+// them like a receipt would. Reverted frames and their subcalls contribute
+// no logs; geth already clears them, but other tracers may not.
+func frameLogs(root callFrame) []domain.Log {
+	var out []domain.Log
+	var walk func(frame callFrame)
+	walk = func(frame callFrame) {
+		if frame.Error != "" {
+			return
+		}
+		next := 0
+		for i, call := range frame.Calls {
+			for next < len(frame.Logs) && int(frame.Logs[next].Position) <= i {
+				out = append(out, convertCallLog(frame.Logs[next], uint(len(out))))
+				next++
+			}
+			walk(call)
+		}
+		for ; next < len(frame.Logs); next++ {
+			out = append(out, convertCallLog(frame.Logs[next], uint(len(out))))
+		}
+	}
+	walk(root)
+	return out
+}
+
+func convertCallLog(l callLog, index uint) domain.Log {
+	topics := make([]string, len(l.Topics))
+	for i, t := range l.Topics {
+		topics[i] = t.Hex()
+	}
+	return domain.Log{
+		Index:   index,
+		Address: l.Address.Hex(),
+		Topics:  topics,
+		Data:    append([]byte(nil), l.Data...),
+	}
+}
@@ -0,0 +1,124 @@
+package ethereum
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// nestedTrace is a callTracer result with withLog for a router call: the
+// root logs around its subcalls, the first subcall logs around a nested
+// call, the second subcall reverts after logging and the third succeeds.
+// Each log's data byte is its expected receipt index; 0xff marks logs of
+// reverted frames, which must not appear.
+const nestedTrace = `{
+	"type": "CALL",
+	"from": "0x00000000000000000000000000000000000000aa",
+	"to": "0x0000000000000000000000000000000000000001",
+	"gas": "0x30000",
+	"gasUsed": "0x20000",
+	"input": "0x",
+	"logs": [
+		{"address": "0x0000000000000000000000000000000000000001", "topics": [], "data": "0x00", "position": "0x0"},
+		{"address": "0x0000000000000000000000000000000000000001", "topics": [], "data": "0x04", "position": "0x2"},
+		{"address": "0x0000000000000000000000000000000000000001", "topics": [], "data": "0x06", "position": "0x3"}
+	],
+	"calls": [
+		{
+			"type": "CALL",
+			"from": "0x0000000000000000000000000000000000000001",
+			"to": "0x0000000000000000000000000000000000000002",
+			"gas": "0x10000",
+			"gasUsed": "0x8000",
+			"input": "0x",
+			"logs": [
+				{"address": "0x0000000000000000000000000000000000000002", "topics": ["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"], "data": "0x01", "position": "0x0"},
+				{"address": "0x0000000000000000000000000000000000000002", "topics": [], "data": "0x03", "position": "0x1"}
+			],
+			"calls": [
+				{
+					"type": "DELEGATECALL",
+					"from": "0x0000000000000000000000000000000000000002",
+					"to": "0x0000000000000000000000000000000000000003",
+					"gas": "0x8000",
+					"gasUsed": "0x4000",
+					"input": "0x",
+					"logs": [
+						{"address": "0x0000000000000000000000000000000000000002", "topics": [], "data": "0x02", "position": "0x0"}
+					]
+				}
+			]
+		},
+		{
+			"type": "CALL",
+			"from": "0x0000000000000000000000000000000000000001",
+			"to": "0x0000000000000000000000000000000000000004",
+			"gas": "0x8000",
+			"gasUsed": "0x8000",
+			"input": "0x",
+			"error": "execution reverted",
+			"logs": [
+				{"address": "0x0000000000000000000000000000000000000004", "topics": [], "data": "0xff", "position": "0x0"}
+			],
+			"calls": [
+				{
+					"type": "CALL",
+					"from": "0x0000000000000000000000000000000000000004",
+					"to": "0x0000000000000000000000000000000000000005",
+					"gas": "0x4000",
+					"gasUsed": "0x1000",
+					"input": "0x",
+					"logs": [
+						{"address": "0x0000000000000000000000000000000000000005", "topics": [], "data": "0xff", "position": "0x0"}
+					]
+				}
+			]
+		},
+		{
+			"type": "STATICCALL",
+			"from": "0x0000000000000000000000000000000000000001",
+			"to": "0x0000000000000000000000000000000000000006",
+			"gas": "0x4000",
+			"gasUsed": "0x1000",
+			"input": "0x",
+			"logs": [
+				{"address": "0x0000000000000000000000000000000000000006", "topics": [], "data": "0x05", "position": "0x0"}
+			]
+		}
+	]
+}`
+
+func TestFrameLogsFollowsEmissionOrder(t *testing.T) {
+	var frame callFrame
+	if err := json.Unmarshal([]byte(nestedTrace), &frame); err != nil {
+		t.Fatalf("decode trace: %v", err)
+	}
+
+	logs := frameLogs(frame)
+	if len(logs) != 7 {
+		t.Fatalf("got %d logs, want 7", len(logs))
+	}
+	for i, l := range logs {
+		if l.Index != uint(i) || len(l.Data) != 1 || l.Data[0] != byte(i) {
+			t.Errorf("log %d = index %d data %x, want index and data %d", i, l.Index, l.Data, i)
+		}
+	}
+	// Logs keep the emitting contract, which for a delegatecall is the caller.
+	if logs[2].Address != "0x0000000000000000000000000000000000000002" {
+		t.Errorf("delegatecall log address = %s", logs[2].Address)
+	}
+	if len(logs[1].Topics) != 1 || logs[1].Topics[0] != "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" {
+		t.Errorf("log 1 topics = %v", logs[1].Topics)
+	}
+}
+
+func TestFrameLogsOfRevertedCall(t *testing.T) {
+	var frame callFrame
+	if err := json.Unmarshal([]byte(nestedTrace), &frame); err != nil {
+		t.Fatalf("decode trace: %v", err)
+	}
+	frame.Error = "execution reverted"
+
+	if logs := frameLogs(frame); len(logs) != 0 {
+		t.Errorf("reverted call kept %d logs", len(logs))
+	}
+}
@@ -0,0 +1,115 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// PendingSubscriber streams pending transactions over a websocket/IPC
+// endpoint. Full tx objects are requested first; nodes that only publish
+// hashes are handled by fetching each tx.
+type PendingSubscriber struct {
+	client  *ethclient.Client
+	gclient *gethclient.Client
+	signer  types.Signer
+}
+
+func NewPendingSubscriber(ctx context.Context, rpcURL string) (*PendingSubscriber, error) {
+	raw, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("connect rpc: %w", err)
+	}
+	client := ethclient.NewClient(raw)
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("fetch chain id: %w", err)
+	}
+	return &PendingSubscriber{
+		client:  client,
+		gclient: gethclient.New(raw),
+		signer:  types.LatestSignerForChainID(chainID),
+	}, nil
+}
+
+func (s *PendingSubscriber) SubscribePending(ctx context.Context, out chan<- domain.Tx) error {
+	if s == nil || s.client == nil {
+		return fmt.Errorf("rpc client is not initialized")
+	}
+
+	full := make(chan *types.Transaction, 256)
+	sub, err := s.gclient.SubscribeFullPendingTransactions(ctx, full)
+	if err != nil {
+		return s.subscribeHashes(ctx, out)
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return fmt.Errorf("pending subscription: %w", err)
+		case tx := <-full:
+			if err := s.emit(ctx, out, tx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *PendingSubscriber) subscribeHashes(ctx context.Context, out chan<- domain.Tx) error {
+	hashes := make(chan common.Hash, 256)
+	sub, err := s.gclient.SubscribePendingTransactions(ctx, hashes)
+	if err != nil {
+		return fmt.Errorf("subscribe pending transactions: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return fmt.Errorf("pending subscription: %w", err)
+		case hash := <-hashes:
+			tx, pending, err := s.client.TransactionByHash(ctx, hash)
+			if err != nil || !pending {
+				// Already mined or evicted before we could fetch it; the
+				// block poller reconciles it as any other tx.
+				continue
+			}
+			if err := s.emit(ctx, out, tx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *PendingSubscriber) emit(ctx context.Context, out chan<- domain.Tx, tx *types.Transaction) error {
+	from, err := types.Sender(s.signer, tx)
+	if err != nil {
+		// Txs from another chain or with a malformed signature are skipped.
+		return nil
+	}
+	select {
+	case out <- convertTxNoLogs(tx, from.Hex()):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *PendingSubscriber) Close() {
+	s.client.Close()
+}
+
+var _ domain.PendingSource = (*PendingSubscriber)(nil)
@@ -0,0 +1,346 @@
+package ethereum
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Blocks, txs and receipts are decoded from the raw JSON-RPC responses instead
+// of go-ethereum's types: those reject the tx envelopes L2s add (OP-stack
+// deposits, Arbitrum L1 messages and internal txs) and drop the L2-only
+// fields of receipts and headers.
+
+const (
+	txTypeArbDeposit         = 0x64
+	txTypeArbUnsigned        = 0x65
+	txTypeArbContract        = 0x66
+	txTypeArbRetry           = 0x68
+	txTypeArbSubmitRetryable = 0x69
+	txTypeArbInternal        = 0x6a
+	txTypeOPDeposit          = 0x7e
+)
+
+var (
+	opL1BlockPredeploy   = common.HexToAddress("0x4200000000000000000000000000000000000015")
+	opL1InfoDepositor    = common.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001")
+	opSetL1BlockValues   = [4]byte{0x01, 0x5d, 0x8e, 0xb9} // Bedrock, ABI encoded
+	opSetL1BlockEcotone  = [4]byte{0x44, 0x0a, 0x5e, 0x20} // Ecotone, packed
+	opSetL1BlockIsthmus  = [4]byte{0x09, 0x89, 0x99, 0xbe} // Isthmus, Ecotone layout plus operator fee
+	opEcotoneInfoMinSize = 164
+)
+
+type rpcBlock struct {
+	Number       *hexutil.Big `json:"number"`
+	Hash         common.Hash  `json:"hash"`
+	ParentHash   common.Hash  `json:"parentHash"`
+	Transactions []rpcTx      `json:"transactions"`
+
+	Miner         common.Address `json:"miner"`
+	BaseFeePerGas *hexutil.Big   `json:"baseFeePerGas"`
+
+	// Cancun.
+	BlobGasUsed   *hexutil.Uint64 `json:"blobGasUsed"`
+	ExcessBlobGas *hexutil.Uint64 `json:"excessBlobGas"`
+
+	// Arbitrum.
+	L1BlockNumber *hexutil.Uint64 `json:"l1BlockNumber"`
+	SendCount     *hexutil.Uint64 `json:"sendCount"`
+	SendRoot      *common.Hash    `json:"sendRoot"`
+}
+
+type rpcTx struct {
+	Type      hexutil.Uint64  `json:"type"`
+	Hash      common.Hash     `json:"hash"`
+	BlockHash *common.Hash    `json:"blockHash"`
+	Index     hexutil.Uint    `json:"transactionIndex"`
+	From      common.Address  `json:"from"`
+	To        *common.Address `json:"to"`
+	Nonce     hexutil.Uint64  `json:"nonce"`
+	Value     *hexutil.Big    `json:"value"`
+	Input     hexutil.Bytes   `json:"input"`
+
+	// EIP-4844 blob txs.
+	BlobVersionedHashes []common.Hash `json:"blobVersionedHashes"`
+	MaxFeePerBlobGas    *hexutil.Big  `json:"maxFeePerBlobGas"`
+
+	// EIP-7702 set-code txs.
+	AuthorizationList []types.SetCodeAuthorization `json:"authorizationList"`
+
+	// OP-stack deposits.
+	SourceHash *common.Hash `json:"sourceHash"`
+	Mint       *hexutil.Big `json:"mint"`
+	IsSystemTx bool         `json:"isSystemTx"`
+
+	// Arbitrum L1 messages and retryables.
+	RequestID        *common.Hash    `json:"requestId"`
+	TicketID         *common.Hash    `json:"ticketId"`
+	RefundTo         *common.Address `json:"refundTo"`
+	Beneficiary      *common.Address `json:"beneficiary"`
+	L1BaseFee        *hexutil.Big    `json:"l1BaseFee"`
+	DepositValue     *hexutil.Big    `json:"depositValue"`
+	MaxSubmissionFee *hexutil.Big    `json:"maxSubmissionFee"`
+	MaxRefund        *hexutil.Big    `json:"maxRefund"`
+	RetryTo          *common.Address `json:"retryTo"`
+	RetryValue       *hexutil.Big    `json:"retryValue"`
+	RetryData        hexutil.Bytes   `json:"retryData"`
+}
+
+type rpcLog struct {
+	Index   hexutil.Uint   `json:"logIndex"`
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+type rpcReceipt struct {
+	Status            hexutil.Uint64  `json:"status"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Logs              []rpcLog        `json:"logs"`
+	BlobGasUsed       *hexutil.Uint64 `json:"blobGasUsed"`
+	BlobGasPrice      *hexutil.Big    `json:"blobGasPrice"`
+
+	// OP-stack.
+	L1Fee               *hexutil.Big    `json:"l1Fee"`
+	L1GasPrice          *hexutil.Big    `json:"l1GasPrice"`
+	L1GasUsed           *hexutil.Big    `json:"l1GasUsed"`
+	L1FeeScalar         string          `json:"l1FeeScalar"`
+	L1BaseFeeScalar     *hexutil.Uint64 `json:"l1BaseFeeScalar"`
+	L1BlobBaseFee       *hexutil.Big    `json:"l1BlobBaseFee"`
+	L1BlobBaseFeeScalar *hexutil.Uint64 `json:"l1BlobBaseFeeScalar"`
+
+	// Arbitrum.
+	GasUsedForL1  *hexutil.Uint64 `json:"gasUsedForL1"`
+	L1BlockNumber *hexutil.Uint64 `json:"l1BlockNumber"`
+}
+
+// convertTx maps a tx and, when given, its receipt. Without a receipt the tx
+// carries no logs.
+func convertTx(tx rpcTx, receipt *rpcReceipt) domain.Tx {
+	out := domain.Tx{
+		Hash:  tx.Hash.Hex(),
+		Index: uint(tx.Index),
+		Type:  uint8(tx.Type),
+		From:  tx.From.Hex(),
+		Nonce: uint64(tx.Nonce),
+		Value: hexBigOrZero(tx.Value),
+		Data:  append([]byte(nil), tx.Input...),
+		L2:    convertL2Tx(tx),
+	}
+	for _, h := range tx.BlobVersionedHashes {
+		out.BlobHashes = append(out.BlobHashes, h.Hex())
+	}
+	out.MaxFeePerBlobGas = optionalBig(tx.MaxFeePerBlobGas)
+	out.Authorizations = convertAuthorizations(tx.AuthorizationList)
+	if tx.To != nil {
+		to := tx.To.Hex()
+		out.To = &to
+	}
+	if receipt == nil {
+		return out
+	}
+
+	logs := make([]domain.Log, 0, len(receipt.Logs))
+	for _, l := range receipt.Logs {
+		topics := make([]string, len(l.Topics))
+		for i, t := range l.Topics {
+			topics[i] = t.Hex()
+		}
+		logs = append(logs, domain.Log{
+			Index:   uint(l.Index),
+			Address: l.Address.Hex(),
+			Topics:  topics,
+			Data:    append([]byte(nil), l.Data...),
+		})
+	}
+	out.Logs = logs
+	out.Receipt = &domain.Receipt{
+		Success:      receipt.Status == 1,
+		GasUsed:      uint64(receipt.GasUsed),
+		L1Fee:        convertL1Fee(*receipt),
+		BlobGasPrice: optionalBig(receipt.BlobGasPrice),
+	}
+	if receipt.BlobGasUsed != nil {
+		out.Receipt.BlobGasUsed = uint64(*receipt.BlobGasUsed)
+	}
+	if receipt.EffectiveGasPrice != nil {
+		out.Receipt.EffectiveGasPrice = new(big.Int).Set(receipt.EffectiveGasPrice.ToInt())
+	}
+	if tx.To == nil && receipt.ContractAddress != nil {
+		out.Receipt.ContractAddress = receipt.ContractAddress.Hex()
+	}
+	return out
+}
+
+// convertAuthorizations recovers the authority of every authorization; an
+// invalid signature only leaves its Authority empty, as the EVM skips it.
+func convertAuthorizations(list []types.SetCodeAuthorization) []domain.SetCodeAuthorization {
+	if len(list) == 0 {
+		return nil
+	}
+	out := make([]domain.SetCodeAuthorization, 0, len(list))
+	for _, auth := range list {
+		converted := domain.SetCodeAuthorization{
+			ChainID:  auth.ChainID.ToBig(),
+			Delegate: auth.Address.Hex(),
+			Nonce:    auth.Nonce,
+		}
+		if authority, err := auth.Authority(); err == nil {
+			converted.Authority = authority.Hex()
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+func convertL2Tx(tx rpcTx) *domain.L2Tx {
+	var l2 domain.L2Tx
+	switch tx.Type {
+	case txTypeOPDeposit:
+		l2.Kind = domain.L2KindOPDeposit
+		l2.IsSystem = tx.IsSystemTx || (tx.From == opL1InfoDepositor && tx.To != nil && *tx.To == opL1BlockPredeploy)
+		l2.Mint = optionalBig(tx.Mint)
+		if tx.SourceHash != nil {
+			l2.SourceHash = tx.SourceHash.Hex()
+		}
+	case txTypeArbDeposit:
+		// ETH bridged from L1 is minted to the sender and then sent to To.
+		l2.Kind = domain.L2KindArbDeposit
+		l2.Mint = hexBigOrZero(tx.Value)
+	case txTypeArbUnsigned:
+		l2.Kind = domain.L2KindArbUnsigned
+	case txTypeArbContract:
+		l2.Kind = domain.L2KindArbContract
+	case txTypeArbRetry:
+		l2.Kind = domain.L2KindArbRetry
+		l2.MaxRefund = optionalBig(tx.MaxRefund)
+	case txTypeArbSubmitRetryable:
+		l2.Kind = domain.L2KindArbSubmitRetryable
+		l2.Mint = optionalBig(tx.DepositValue)
+		l2.L1BaseFee = optionalBig(tx.L1BaseFee)
+		l2.DepositValue = optionalBig(tx.DepositValue)
+		l2.MaxSubmissionFee = optionalBig(tx.MaxSubmissionFee)
+		l2.RetryValue = optionalBig(tx.RetryValue)
+		l2.RetryData = append([]byte(nil), tx.RetryData...)
+		if tx.RetryTo != nil {
+			retryTo := tx.RetryTo.Hex()
+			l2.RetryTo = &retryTo
+		}
+		if tx.Beneficiary != nil {
+			l2.Beneficiary = tx.Beneficiary.Hex()
+		}
+	case txTypeArbInternal:
+		l2.Kind = domain.L2KindArbInternal
+		l2.IsSystem = true
+	default:
+		return nil
+	}
+	if tx.RequestID != nil {
+		l2.RequestID = tx.RequestID.Hex()
+	}
+	if tx.TicketID != nil {
+		l2.TicketID = tx.TicketID.Hex()
+	}
+	if tx.RefundTo != nil {
+		l2.RefundTo = tx.RefundTo.Hex()
+	}
+	return &l2
+}
+
+func convertL1Fee(receipt rpcReceipt) *domain.L1Fee {
+	if receipt.L1Fee == nil && receipt.GasUsedForL1 == nil {
+		return nil
+	}
+	fee := &domain.L1Fee{
+		Fee:         optionalBig(receipt.L1Fee),
+		GasPrice:    optionalBig(receipt.L1GasPrice),
+		GasUsed:     optionalBig(receipt.L1GasUsed),
+		FeeScalar:   receipt.L1FeeScalar,
+		BlobBaseFee: optionalBig(receipt.L1BlobBaseFee),
+	}
+	if receipt.L1BaseFeeScalar != nil {
+		fee.BaseFeeScalar = uint64(*receipt.L1BaseFeeScalar)
+	}
+	if receipt.L1BlobBaseFeeScalar != nil {
+		fee.BlobBaseFeeScalar = uint64(*receipt.L1BlobBaseFeeScalar)
+	}
+	if receipt.GasUsedForL1 != nil {
+		fee.GasUsedForL1 = uint64(*receipt.GasUsedForL1)
+	}
+	if receipt.L1BlockNumber != nil {
+		fee.L1BlockNumber = uint64(*receipt.L1BlockNumber)
+	}
+	return fee
+}
+
+// sequencerInfo reads the L1 origin from Arbitrum header fields or from the
+// OP-stack L1 attributes deposit, which is always the first tx of the block.
+func sequencerInfo(block rpcBlock) *domain.SequencerInfo {
+	if block.L1BlockNumber != nil {
+		info := &domain.SequencerInfo{L1BlockNumber: uint64(*block.L1BlockNumber)}
+		if block.SendCount != nil {
+			info.SendCount = uint64(*block.SendCount)
+		}
+		if block.SendRoot != nil {
+			info.SendRoot = block.SendRoot.Hex()
+		}
+		return info
+	}
+	if len(block.Transactions) == 0 {
+		return nil
+	}
+	first := block.Transactions[0]
+	if first.Type != txTypeOPDeposit || first.To == nil || *first.To != opL1BlockPredeploy {
+		return nil
+	}
+	return decodeL1Attributes(first.Input)
+}
+
+func decodeL1Attributes(input []byte) *domain.SequencerInfo {
+	if len(input) < 4 {
+		return nil
+	}
+	var selector [4]byte
+	copy(selector[:], input[:4])
+	switch selector {
+	case opSetL1BlockValues:
+		args := input[4:]
+		if len(args) < 5*32 {
+			return nil
+		}
+		return &domain.SequencerInfo{
+			L1BlockNumber:  new(big.Int).SetBytes(args[0:32]).Uint64(),
+			L1BaseFee:      new(big.Int).SetBytes(args[64:96]),
+			L1BlockHash:    common.BytesToHash(args[96:128]).Hex(),
+			SequenceNumber: new(big.Int).SetBytes(args[128:160]).Uint64(),
+		}
+	case opSetL1BlockEcotone, opSetL1BlockIsthmus:
+		// selector | baseFeeScalar u32 | blobBaseFeeScalar u32 | sequenceNumber u64 |
+		// timestamp u64 | number u64 | basefee | blobBaseFee | hash | batcherHash
+		if len(input) < opEcotoneInfoMinSize {
+			return nil
+		}
+		return &domain.SequencerInfo{
+			SequenceNumber: binary.BigEndian.Uint64(input[12:20]),
+			L1BlockNumber:  binary.BigEndian.Uint64(input[28:36]),
+			L1BaseFee:      new(big.Int).SetBytes(input[36:68]),
+			L1BlobBaseFee:  new(big.Int).SetBytes(input[68:100]),
+			L1BlockHash:    common.BytesToHash(input[100:132]).Hex(),
+		}
+	}
+	return nil
+}
+
+func optionalBig(v *hexutil.Big) *big.Int {
+	if v == nil {
+		return nil
+	}
+	return new(big.Int).Set(v.ToInt())
+}
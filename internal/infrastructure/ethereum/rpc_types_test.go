@@ -0,0 +1,324 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// L1 attributes deposit calldata laid out as on OP mainnet: the Bedrock call
+// is ABI encoded (number, timestamp, basefee, hash, sequence number, batcher
+// hash, overhead, scalar); Ecotone packs the scalars and adds the blob base
+// fee.
+const (
+	bedrockL1Info = "015d8eb9" +
+		"000000000000000000000000000000000000000000000000000000000109d8fe" +
+		"00000000000000000000000000000000000000000000000000000000647f5ea7" +
+		"000000000000000000000000000000000000000000000000000000076cfaf9ac" +
+		"a6e5c1b1f4f3e4b8e5d07b2a09d3a36c1bd0c07ae7b8a9d55f0a5b1d1e7c3f42" +
+		"0000000000000000000000000000000000000000000000000000000000000005" +
+		"0000000000000000000000006887246668a3b87f54deb3b94ba47a6f63f32985" +
+		"00000000000000000000000000000000000000000000000000000000000000bc" +
+		"00000000000000000000000000000000000000000000000000000000000a6fe0"
+	ecotoneL1Info = "440a5e20" +
+		"00000558" + "000c5fc5" + "0000000000000003" + "0000000065f23e00" + "0000000001298be0" +
+		"000000000000000000000000000000000000000000000000000000063a25369f" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"5a0f2bb47e1a8ae2f9e0c93c3d3d0c4b0f2e8e1d6c7b5a49382716055f4e3d2c" +
+		"0000000000000000000000006887246668a3b87f54deb3b94ba47a6f63f32985"
+)
+
+// Isthmus keeps the Ecotone layout and appends the operator fee scalar (u32)
+// and constant (u64).
+var isthmusL1Info = "098999be" + ecotoneL1Info[8:] + "00000000" + "0000000000000000"
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode %s: %v", s, err)
+	}
+	return b
+}
+
+func TestDecodeL1Attributes(t *testing.T) {
+	bedrock := &domain.SequencerInfo{
+		L1BlockNumber:  17422590,
+		L1BlockHash:    "0xa6e5c1b1f4f3e4b8e5d07b2a09d3a36c1bd0c07ae7b8a9d55f0a5b1d1e7c3f42",
+		L1BaseFee:      big.NewInt(31893158316),
+		SequenceNumber: 5,
+	}
+	ecotone := &domain.SequencerInfo{
+		L1BlockNumber:  19500000,
+		L1BlockHash:    "0x5a0f2bb47e1a8ae2f9e0c93c3d3d0c4b0f2e8e1d6c7b5a49382716055f4e3d2c",
+		L1BaseFee:      big.NewInt(26745321119),
+		L1BlobBaseFee:  big.NewInt(1),
+		SequenceNumber: 3,
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  *domain.SequencerInfo
+	}{
+		{name: "bedrock", input: bedrockL1Info, want: bedrock},
+		{name: "ecotone", input: ecotoneL1Info, want: ecotone},
+		{name: "isthmus", input: isthmusL1Info, want: ecotone},
+		{name: "bedrock truncated", input: bedrockL1Info[:8+5*64-2]},
+		{name: "ecotone truncated", input: ecotoneL1Info[:2*opEcotoneInfoMinSize-2]},
+		{name: "unknown selector", input: "deadbeef" + ecotoneL1Info[8:]},
+		{name: "no selector", input: "015d8e"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeL1Attributes(mustHex(t, tt.input))
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("decodeL1Attributes = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("decodeL1Attributes = nil")
+			}
+			if got.L1BlockNumber != tt.want.L1BlockNumber || got.L1BlockHash != tt.want.L1BlockHash ||
+				got.SequenceNumber != tt.want.SequenceNumber || got.L1BaseFee.Cmp(tt.want.L1BaseFee) != 0 {
+				t.Errorf("decodeL1Attributes = %+v, want %+v", got, tt.want)
+			}
+			if (got.L1BlobBaseFee == nil) != (tt.want.L1BlobBaseFee == nil) ||
+				(got.L1BlobBaseFee != nil && got.L1BlobBaseFee.Cmp(tt.want.L1BlobBaseFee) != 0) {
+				t.Errorf("blob base fee = %v, want %v", got.L1BlobBaseFee, tt.want.L1BlobBaseFee)
+			}
+		})
+	}
+}
+
+func TestSequencerInfoFromBlock(t *testing.T) {
+	opBlock := `{
+		"number": "0x7270e00", "hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "parentHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"transactions": [{
+			"type": "0x7e", "hash": "0x0000000000000000000000000000000000000000000000000000000000000002",
+			"from": "0xdeaddeaddeaddeaddeaddeaddeaddeaddead0001",
+			"to": "0x4200000000000000000000000000000000000015",
+			"nonce": "0x0", "value": "0x0", "input": "0x` + ecotoneL1Info + `",
+			"sourceHash": "0x0000000000000000000000000000000000000000000000000000000000000003", "mint": "0x0", "isSystemTx": false
+		}]
+	}`
+	arbBlock := `{
+		"number": "0x1", "hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "parentHash": "0x0000000000000000000000000000000000000000000000000000000000000000", "transactions": [],
+		"l1BlockNumber": "0x1298be0", "sendCount": "0x2a",
+		"sendRoot": "0x00000000000000000000000000000000000000000000000000000000000000ab"
+	}`
+
+	var block rpcBlock
+	if err := json.Unmarshal([]byte(opBlock), &block); err != nil {
+		t.Fatalf("decode block: %v", err)
+	}
+	if info := sequencerInfo(block); info == nil || info.L1BlockNumber != 19500000 || info.SequenceNumber != 3 {
+		t.Errorf("OP sequencer info = %+v", info)
+	}
+	// A deposit to another contract in first position is not the L1 info.
+	bridge := common.HexToAddress("0x4200000000000000000000000000000000000010")
+	block.Transactions[0].To = &bridge
+	if info := sequencerInfo(block); info != nil {
+		t.Errorf("sequencer info from a bridge deposit = %+v", info)
+	}
+
+	block = rpcBlock{}
+	if err := json.Unmarshal([]byte(arbBlock), &block); err != nil {
+		t.Fatalf("decode block: %v", err)
+	}
+	info := sequencerInfo(block)
+	if info == nil || info.L1BlockNumber != 19500000 || info.SendCount != 42 || !strings.HasSuffix(info.SendRoot, "ab") {
+		t.Errorf("Arbitrum sequencer info = %+v", info)
+	}
+}
+
+func TestConvertTxL2Envelopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		tx      string
+		receipt string
+		check   func(t *testing.T, tx domain.Tx)
+	}{
+		{
+			name: "op system deposit",
+			tx: `{"type": "0x7e", "hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "from": "0xdeaddeaddeaddeaddeaddeaddeaddeaddead0001",
+				"to": "0x4200000000000000000000000000000000000015", "nonce": "0x0", "value": "0x0",
+				"input": "0x` + bedrockL1Info + `", "sourceHash": "0x00000000000000000000000000000000000000000000000000000000000000c1",
+				"mint": null, "isSystemTx": false}`,
+			check: func(t *testing.T, tx domain.Tx) {
+				if tx.L2.Kind != domain.L2KindOPDeposit || !tx.L2.IsSystem || tx.L2.Mint != nil || !strings.HasSuffix(tx.L2.SourceHash, "c1") {
+					t.Errorf("L2 = %+v", tx.L2)
+				}
+			},
+		},
+		{
+			name: "op user deposit",
+			tx: `{"type": "0x7e", "hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "from": "0x36bde71c97b33cc4729cf772ae268934f7ab70b2",
+				"to": "0x4200000000000000000000000000000000000007", "nonce": "0x0", "value": "0x0", "input": "0x",
+				"sourceHash": "0x0000000000000000000000000000000000000000000000000000000000000001", "mint": "0xde0b6b3a7640000", "isSystemTx": false}`,
+			check: func(t *testing.T, tx domain.Tx) {
+				if tx.L2.IsSystem || tx.L2.Mint == nil || tx.L2.Mint.String() != "1000000000000000000" {
+					t.Errorf("L2 = %+v", tx.L2)
+				}
+			},
+		},
+		{
+			name: "arbitrum deposit",
+			tx: `{"type": "0x64", "hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "from": "0x1111111111111111111111111111111111111111",
+				"to": "0x1111111111111111111111111111111111111111", "nonce": "0x0", "value": "0x2386f26fc10000", "input": "0x",
+				"requestId": "0x00000000000000000000000000000000000000000000000000000000000f4240"}`,
+			receipt: `{"status": "0x1", "gasUsed": "0x0", "logs": [], "gasUsedForL1": "0x0", "l1BlockNumber": "0x1298be0"}`,
+			check: func(t *testing.T, tx domain.Tx) {
+				if tx.L2.Kind != domain.L2KindArbDeposit || tx.L2.Mint.String() != "10000000000000000" || !strings.HasSuffix(tx.L2.RequestID, "0f4240") {
+					t.Errorf("L2 = %+v", tx.L2)
+				}
+			},
+		},
+		{
+			name:    "arbitrum unsigned",
+			tx:      `{"type": "0x65", "hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "from": "0x1111111111111111111111111111111111111111", "to": "0x2222222222222222222222222222222222222222", "nonce": "0x3", "value": "0x0", "input": "0x12345678"}`,
+			receipt: `{"status": "0x1", "gasUsed": "0x5208", "logs": [], "gasUsedForL1": "0x0", "l1BlockNumber": "0x1298be0"}`,
+			check: func(t *testing.T, tx domain.Tx) {
+				if tx.L2.Kind != domain.L2KindArbUnsigned || tx.L2.IsSystem {
+					t.Errorf("L2 = %+v", tx.L2)
+				}
+			},
+		},
+		{
+			name:    "arbitrum contract",
+			tx:      `{"type": "0x66", "hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "from": "0x1111111111111111111111111111111111111111", "to": "0x2222222222222222222222222222222222222222", "nonce": "0x0", "value": "0x0", "input": "0x", "requestId": "0x0000000000000000000000000000000000000000000000000000000000000002"}`,
+			receipt: `{"status": "0x1", "gasUsed": "0x5208", "logs": [], "gasUsedForL1": "0x0", "l1BlockNumber": "0x1298be0"}`,
+			check: func(t *testing.T, tx domain.Tx) {
+				if tx.L2.Kind != domain.L2KindArbContract || tx.L2.RequestID == "" {
+					t.Errorf("L2 = %+v", tx.L2)
+				}
+			},
+		},
+		{
+			name: "arbitrum retry",
+			tx: `{"type": "0x68", "hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "from": "0x1111111111111111111111111111111111111111", "to": "0x2222222222222222222222222222222222222222",
+				"nonce": "0x0", "value": "0x0", "input": "0x", "ticketId": "0x0000000000000000000000000000000000000000000000000000000000000003", "refundTo": "0x3333333333333333333333333333333333333333", "maxRefund": "0x5af3107a4000"}`,
+			receipt: `{"status": "0x0", "gasUsed": "0x186a0", "logs": [], "gasUsedForL1": "0x0", "l1BlockNumber": "0x1298be0"}`,
+			check: func(t *testing.T, tx domain.Tx) {
+				if tx.L2.Kind != domain.L2KindArbRetry || tx.L2.TicketID == "" || tx.L2.MaxRefund.String() != "100000000000000" ||
+					tx.L2.RefundTo != "0x3333333333333333333333333333333333333333" || tx.Receipt.Success {
+					t.Errorf("L2 = %+v, receipt %+v", tx.L2, tx.Receipt)
+				}
+			},
+		},
+		{
+			name: "arbitrum submit retryable",
+			tx: `{"type": "0x69", "hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "from": "0x1111111111111111111111111111111111111111", "to": "0x000000000000000000000000000000000000006e",
+				"nonce": "0x0", "value": "0x0", "input": "0x", "requestId": "0x0000000000000000000000000000000000000000000000000000000000000004", "l1BaseFee": "0x3b9aca00", "depositValue": "0xde0b6b3a7640000",
+				"maxSubmissionFee": "0x2386f26fc10000", "beneficiary": "0x4444444444444444444444444444444444444444",
+				"refundTo": "0x3333333333333333333333333333333333333333", "retryTo": "0x5555555555555555555555555555555555555555",
+				"retryValue": "0x1", "retryData": "0xa9059cbb"}`,
+			receipt: `{"status": "0x1", "gasUsed": "0x0", "logs": [], "gasUsedForL1": "0x0", "l1BlockNumber": "0x1298be0"}`,
+			check: func(t *testing.T, tx domain.Tx) {
+				l2 := tx.L2
+				if l2.Kind != domain.L2KindArbSubmitRetryable || l2.Mint.String() != "1000000000000000000" || l2.DepositValue.Cmp(l2.Mint) != 0 ||
+					l2.L1BaseFee.String() != "1000000000" || l2.MaxSubmissionFee.String() != "10000000000000000" ||
+					l2.RetryTo == nil || *l2.RetryTo != "0x5555555555555555555555555555555555555555" || l2.RetryValue.Int64() != 1 ||
+					hex.EncodeToString(l2.RetryData) != "a9059cbb" || l2.Beneficiary != "0x4444444444444444444444444444444444444444" {
+					t.Errorf("L2 = %+v", l2)
+				}
+			},
+		},
+		{
+			name:    "arbitrum internal",
+			tx:      `{"type": "0x6a", "hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "from": "0x00000000000000000000000000000000000a4b05", "to": "0x0000000000000000000000000000000000000064", "nonce": "0x0", "value": "0x0", "input": "0x6bf6a42d"}`,
+			receipt: `{"status": "0x1", "gasUsed": "0x0", "logs": [], "gasUsedForL1": "0x0", "l1BlockNumber": "0x1298be0"}`,
+			check: func(t *testing.T, tx domain.Tx) {
+				if tx.L2.Kind != domain.L2KindArbInternal || !tx.L2.IsSystem {
+					t.Errorf("L2 = %+v", tx.L2)
+				}
+			},
+		},
+		{
+			name:    "arbitrum dynamic fee tx",
+			tx:      `{"type": "0x2", "hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "from": "0x1111111111111111111111111111111111111111", "to": "0x2222222222222222222222222222222222222222", "nonce": "0x0", "value": "0x0", "input": "0x"}`,
+			receipt: `{"status": "0x1", "gasUsed": "0x5208", "logs": [], "gasUsedForL1": "0x1a2b", "l1BlockNumber": "0x1298be0"}`,
+			check: func(t *testing.T, tx domain.Tx) {
+				if tx.L2 != nil {
+					t.Errorf("L2 = %+v, want nil for a regular envelope", tx.L2)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw rpcTx
+			if err := json.Unmarshal([]byte(tt.tx), &raw); err != nil {
+				t.Fatalf("decode tx: %v", err)
+			}
+			var receipt *rpcReceipt
+			if tt.receipt != "" {
+				receipt = new(rpcReceipt)
+				if err := json.Unmarshal([]byte(tt.receipt), receipt); err != nil {
+					t.Fatalf("decode receipt: %v", err)
+				}
+			}
+			tx := convertTx(raw, receipt)
+			if tx.L2 == nil && strings.Contains(tt.name, "deposit") {
+				t.Fatal("deposit converted without L2 fields")
+			}
+			if receipt != nil {
+				// Every Arbitrum receipt reports the L1 gas and origin block,
+				// zero for txs that did not come through the sequencer feed.
+				fee := tx.Receipt.L1Fee
+				if fee == nil || fee.L1BlockNumber != 19500000 || fee.GasUsedForL1 != uint64(*receipt.GasUsedForL1) || fee.Fee != nil {
+					t.Fatalf("L1 fee = %+v", fee)
+				}
+			}
+			tt.check(t, tx)
+		})
+	}
+}
+
+func TestConvertL1FeeOPReceipts(t *testing.T) {
+	tests := []struct {
+		name    string
+		receipt string
+		want    *domain.L1Fee
+	}{
+		{
+			name:    "bedrock",
+			receipt: `{"status": "0x1", "gasUsed": "0x5208", "l1Fee": "0x2f3e8f6b4d", "l1GasPrice": "0x76cfaf9ac", "l1GasUsed": "0x6d0", "l1FeeScalar": "0.684"}`,
+			want:    &domain.L1Fee{Fee: big.NewInt(0x2f3e8f6b4d), GasPrice: big.NewInt(0x76cfaf9ac), GasUsed: big.NewInt(0x6d0), FeeScalar: "0.684"},
+		},
+		{
+			name: "ecotone",
+			receipt: `{"status": "0x1", "gasUsed": "0x5208", "l1Fee": "0x3a6c", "l1GasPrice": "0x63a25369f", "l1GasUsed": "0x640",
+				"l1BaseFeeScalar": "0x558", "l1BlobBaseFee": "0x1", "l1BlobBaseFeeScalar": "0xc5fc5"}`,
+			want: &domain.L1Fee{Fee: big.NewInt(0x3a6c), GasPrice: big.NewInt(0x63a25369f), GasUsed: big.NewInt(0x640), BaseFeeScalar: 1368, BlobBaseFee: big.NewInt(1), BlobBaseFeeScalar: 810949},
+		},
+		{name: "l1 receipt", receipt: `{"status": "0x1", "gasUsed": "0x5208"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receipt rpcReceipt
+			if err := json.Unmarshal([]byte(tt.receipt), &receipt); err != nil {
+				t.Fatalf("decode receipt: %v", err)
+			}
+			got := convertL1Fee(receipt)
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("convertL1Fee = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Fee.Cmp(tt.want.Fee) != 0 || got.GasPrice.Cmp(tt.want.GasPrice) != 0 || got.GasUsed.Cmp(tt.want.GasUsed) != 0 ||
+				got.FeeScalar != tt.want.FeeScalar || got.BaseFeeScalar != tt.want.BaseFeeScalar || got.BlobBaseFeeScalar != tt.want.BlobBaseFeeScalar ||
+				(got.BlobBaseFee == nil) != (tt.want.BlobBaseFee == nil) {
+				t.Errorf("convertL1Fee = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	TracerCallTracer = "debug_traceCall"
+	TracerEthCall    = "eth_call"
+
+	rpcMethodNotFound = -32601
+)
+
+// Simulator executes calls against a block's state. It prefers
+// debug_traceCall with the callTracer so logs are collected; nodes without
+// the debug namespace fall back to eth_call, which only reports success and
+// return data.
+type Simulator struct {
+	raw    *rpc.Client
+	client *ethclient.Client
+}
+
+func NewSimulator(rpcURL string) (*Simulator, error) {
+	raw, err := rpc.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("connect rpc: %w", err)
+	}
+	return &Simulator{raw: raw, client: ethclient.NewClient(raw)}, nil
+}
+
+func (s *Simulator) Simulate(ctx context.Context, call domain.CallRequest, block *big.Int) (domain.Simulation, error) {
+	if s == nil || s.raw == nil {
+		return domain.Simulation{}, fmt.Errorf("rpc client is not initialized")
+	}
+	tag := blockTag(block)
+
+	var frame callFrame
+	err := s.raw.CallContext(ctx, &frame, "debug_traceCall", callArg(call), tag, callTracerWithLogs)
+	if err == nil {
+		return domain.Simulation{
+			Tx:          simulatedTx(call, frameLogs(frame)),
+			Block:       blockLabel(block),
+			Tracer:      TracerCallTracer,
+			Success:     frame.Error == "",
+			Error:       frameError(frame),
+			GasUsed:     uint64(frame.GasUsed),
+			ReturnData:  append([]byte(nil), frame.Output...),
+			LogsTracked: true,
+		}, nil
+	}
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != rpcMethodNotFound {
+		return domain.Simulation{}, fmt.Errorf("debug_traceCall: %w", err)
+	}
+	return s.ethCall(ctx, call, block)
+}
+
+func (s *Simulator) ethCall(ctx context.Context, call domain.CallRequest, block *big.Int) (domain.Simulation, error) {
+	sim := domain.Simulation{
+		Tx:     simulatedTx(call, nil),
+		Block:  blockLabel(block),
+		Tracer: TracerEthCall,
+	}
+	out, err := s.client.CallContract(ctx, callMsg(call), block)
+	if err != nil {
+		var dataErr rpc.DataError
+		if !errors.As(err, &dataErr) {
+			return domain.Simulation{}, fmt.Errorf("eth_call: %w", err)
+		}
+		// Execution reverted: the node attaches the revert data to the error.
+		sim.Error = err.Error()
+		if data, ok := dataErr.ErrorData().(string); ok {
+			sim.ReturnData, _ = hexutil.Decode(data)
+		}
+		return sim, nil
+	}
+	sim.Success = true
+	sim.ReturnData = out
+	return sim, nil
+}
+
+// TransactionCall turns a pending or mined tx into a CallRequest so it can be
+// replayed against any block.
+func (s *Simulator) TransactionCall(ctx context.Context, hash string) (domain.CallRequest, error) {
+	if s == nil || s.client == nil {
+		return domain.CallRequest{}, fmt.Errorf("rpc client is not initialized")
+	}
+	if len(strings.TrimPrefix(hash, "0x")) != 64 {
+		return domain.CallRequest{}, fmt.Errorf("%w: tx hash %q", domain.ErrInvalidArgument, hash)
+	}
+	tx, _, err := s.client.TransactionByHash(ctx, common.HexToHash(hash))
+	if err != nil {
+		return domain.CallRequest{}, fmt.Errorf("fetch tx %s: %w", hash, notFound(err))
+	}
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return domain.CallRequest{}, fmt.Errorf("resolve sender for tx %s: %w", tx.Hash(), err)
+	}
+
+	var to *string
+	if tx.To() != nil {
+		addr := tx.To().Hex()
+		to = &addr
+	}
+	return domain.CallRequest{
+		Hash:  tx.Hash().Hex(),
+		From:  from.Hex(),
+		To:    to,
+		Value: new(big.Int).Set(tx.Value()),
+		Data:  append([]byte(nil), tx.Data()...),
+		Gas:   tx.Gas(),
+	}, nil
+}
+
+func (s *Simulator) ChainID(ctx context.Context) (uint64, error) {
+	id, err := s.client.ChainID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("fetch chain id: %w", err)
+	}
+	return id.Uint64(), nil
+}
+
+func (s *Simulator) Close() {
+	s.raw.Close()
+}
+
+func callArg(call domain.CallRequest) map[string]any {
+	arg := map[string]any{
+		"from":  common.HexToAddress(call.From),
+		"input": hexutil.Bytes(call.Data),
+	}
+	if call.To != nil {
+		arg["to"] = common.HexToAddress(*call.To)
+	}
+	if call.Value != nil {
+		arg["value"] = (*hexutil.Big)(call.Value)
+	}
+	if call.Gas > 0 {
+		arg["gas"] = hexutil.Uint64(call.Gas)
+	}
+	return arg
+}
+
+func callMsg(call domain.CallRequest) ethereum.CallMsg {
+	msg := ethereum.CallMsg{
+		From:  common.HexToAddress(call.From),
+		Value: call.Value,
+		Data:  call.Data,
+		Gas:   call.Gas,
+	}
+	if call.To != nil {
+		to := common.HexToAddress(*call.To)
+		msg.To = &to
+	}
+	return msg
+}
+
+func simulatedTx(call domain.CallRequest, logs []domain.Log) domain.Tx {
+	value := call.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	return domain.Tx{
+		Hash:  call.Hash,
+		From:  call.From,
+		To:    call.To,
+		Value: new(big.Int).Set(value),
+		Data:  append([]byte(nil), call.Data...),
+		Logs:  logs,
+	}
+}
+
+func frameError(frame callFrame) string {
+	if frame.RevertReason != "" {
+		return fmt.Sprintf("%s: %s", frame.Error, frame.RevertReason)
+	}
+	return frame.Error
+}
+
+func blockTag(block *big.Int) string {
+	if block == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(block)
+}
+
+func blockLabel(block *big.Int) string {
+	if block == nil {
+		return "latest"
+	}
+	return block.String()
+}
+
+var (
+	_ domain.TxSimulator  = (*Simulator)(nil)
+	_ domain.TxCallReader = (*Simulator)(nil)
+)
@@ -0,0 +1,150 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// rpcReply is the canned answer to one method: a raw JSON result or an error.
+type rpcReply struct {
+	result string
+	err    *rpcError
+}
+
+// newTestSimulator serves replies by method; unknown methods get -32601 like
+// a node without the namespace.
+func newTestSimulator(t *testing.T, replies map[string]rpcReply) *Simulator {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		reply, ok := replies[req.Method]
+		switch {
+		case !ok:
+			resp["error"] = rpcError{Code: rpcMethodNotFound, Message: "the method " + req.Method + " does not exist/is not available"}
+		case reply.err != nil:
+			resp["error"] = reply.err
+		default:
+			resp["result"] = json.RawMessage(reply.result)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	sim, err := NewSimulator(server.URL)
+	if err != nil {
+		t.Fatalf("NewSimulator: %v", err)
+	}
+	t.Cleanup(sim.Close)
+	return sim
+}
+
+func testCall() domain.CallRequest {
+	to := "0x0000000000000000000000000000000000000001"
+	return domain.CallRequest{From: "0x00000000000000000000000000000000000000aa", To: &to, Data: []byte{0x12, 0x34}}
+}
+
+func TestSimulateUsesCallTracerLogs(t *testing.T) {
+	sim := newTestSimulator(t, map[string]rpcReply{"debug_traceCall": {result: nestedTrace}})
+
+	got, err := sim.Simulate(context.Background(), testCall(), nil)
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if got.Tracer != TracerCallTracer || !got.LogsTracked || !got.Success || got.GasUsed != 0x20000 {
+		t.Errorf("simulation = %s tracked %v success %v gas %d", got.Tracer, got.LogsTracked, got.Success, got.GasUsed)
+	}
+	if len(got.Tx.Logs) != 7 || got.Tx.Value.Sign() != 0 {
+		t.Errorf("simulated tx has %d logs, value %s", len(got.Tx.Logs), got.Tx.Value)
+	}
+}
+
+func TestSimulateReportsRevertedTrace(t *testing.T) {
+	reverted := strings.Replace(nestedTrace, `"input": "0x",`, `"input": "0x", "error": "execution reverted", "revertReason": "STF",`, 1)
+	sim := newTestSimulator(t, map[string]rpcReply{"debug_traceCall": {result: reverted}})
+
+	got, err := sim.Simulate(context.Background(), testCall(), nil)
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if got.Success || got.Error != "execution reverted: STF" || len(got.Tx.Logs) != 0 {
+		t.Errorf("simulation = success %v error %q with %d logs", got.Success, got.Error, len(got.Tx.Logs))
+	}
+}
+
+func TestSimulateFallsBackToEthCall(t *testing.T) {
+	tests := []struct {
+		name        string
+		reply       rpcReply
+		wantSuccess bool
+		wantReturn  string
+	}{
+		{name: "success", reply: rpcReply{result: `"0x0000000000000000000000000000000000000000000000000000000000000001"`}, wantSuccess: true, wantReturn: strings.Repeat("00", 31) + "01"},
+		{name: "revert", reply: rpcReply{err: &rpcError{Code: 3, Message: "execution reverted", Data: "0x08c379a0"}}, wantReturn: "08c379a0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := newTestSimulator(t, map[string]rpcReply{"eth_call": tt.reply})
+
+			got, err := sim.Simulate(context.Background(), testCall(), nil)
+			if err != nil {
+				t.Fatalf("Simulate: %v", err)
+			}
+			if got.Tracer != TracerEthCall || got.LogsTracked || got.Success != tt.wantSuccess {
+				t.Errorf("simulation = %s tracked %v success %v", got.Tracer, got.LogsTracked, got.Success)
+			}
+			if ret := hex.EncodeToString(got.ReturnData); ret != tt.wantReturn {
+				t.Errorf("return data = %s, want %s", ret, tt.wantReturn)
+			}
+		})
+	}
+}
+
+func TestSimulateKeepsOtherTraceErrors(t *testing.T) {
+	// Only a missing debug namespace falls back; other failures surface.
+	sim := newTestSimulator(t, map[string]rpcReply{
+		"debug_traceCall": {err: &rpcError{Code: -32000, Message: "header not found"}},
+		"eth_call":        {result: `"0x"`},
+	})
+	if _, err := sim.Simulate(context.Background(), testCall(), nil); err == nil || !strings.Contains(err.Error(), "header not found") {
+		t.Fatalf("Simulate = %v, want the trace error", err)
+	}
+}
+
+func TestTransactionCallErrors(t *testing.T) {
+	sim := newTestSimulator(t, map[string]rpcReply{"eth_getTransactionByHash": {result: "null"}})
+	tests := []struct {
+		hash string
+		want error
+	}{
+		{hash: "0x1234", want: domain.ErrInvalidArgument},
+		{hash: "0x" + strings.Repeat("ab", 32), want: domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		if _, err := sim.TransactionCall(context.Background(), tt.hash); !errors.Is(err, tt.want) {
+			t.Errorf("TransactionCall(%s) = %v, want %v", tt.hash, err, tt.want)
+		}
+	}
+}
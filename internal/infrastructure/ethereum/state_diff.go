@@ -0,0 +1,137 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var prestateDiffTracer = map[string]any{
+	"tracer":       "prestateTracer",
+	"tracerConfig": map[string]any{"diffMode": true},
+}
+
+// prestateAccount mirrors one account of prestateTracer's output. In diff
+// mode "pre" holds the full prior account plus the modified slots, while
+// "post" only holds fields that changed (cleared slots are omitted).
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Nonce   *uint64                     `json:"nonce"`
+	Code    *hexutil.Bytes              `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+type prestateDiff struct {
+	Pre  map[common.Address]prestateAccount `json:"pre"`
+	Post map[common.Address]prestateAccount `json:"post"`
+}
+
+func (r *BlockReader) stateDiff(ctx context.Context, hash common.Hash) ([]domain.AccountDiff, error) {
+	var diff prestateDiff
+	if err := r.raw.CallContext(ctx, &diff, "debug_traceTransaction", hash, prestateDiffTracer); err != nil {
+		return nil, fmt.Errorf("prestate diff for tx %s: %w", hash, err)
+	}
+	return convertStateDiff(diff), nil
+}
+
+func convertStateDiff(diff prestateDiff) []domain.AccountDiff {
+	addrs := make(map[common.Address]struct{}, len(diff.Pre)+len(diff.Post))
+	for addr := range diff.Pre {
+		addrs[addr] = struct{}{}
+	}
+	for addr := range diff.Post {
+		addrs[addr] = struct{}{}
+	}
+	sorted := make([]common.Address, 0, len(addrs))
+	for addr := range addrs {
+		sorted = append(sorted, addr)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i][:], sorted[j][:]) < 0 })
+
+	out := make([]domain.AccountDiff, 0, len(sorted))
+	for _, addr := range sorted {
+		pre, inPre := diff.Pre[addr]
+		post, inPost := diff.Post[addr]
+		account := domain.AccountDiff{
+			Address: addr.Hex(),
+			Created: !inPre,
+			Deleted: !inPost,
+		}
+
+		if post.Balance != nil || (account.Deleted && pre.Balance != nil) {
+			account.BalanceChanged = true
+			account.BalanceBefore = hexBigOrZero(pre.Balance)
+			account.BalanceAfter = hexBigOrZero(post.Balance)
+		}
+		if post.Nonce != nil || (account.Deleted && pre.Nonce != nil) {
+			account.NonceChanged = true
+			account.NonceBefore = uint64OrZero(pre.Nonce)
+			account.NonceAfter = uint64OrZero(post.Nonce)
+		}
+		if post.Code != nil || (account.Deleted && pre.Code != nil) {
+			before, after := bytesOrNil(pre.Code), bytesOrNil(post.Code)
+			if !bytes.Equal(before, after) {
+				account.CodeChanged = true
+				account.CodeBefore = before
+				account.CodeAfter = after
+			}
+		}
+		account.Storage = storageDiff(pre.Storage, post.Storage)
+		out = append(out, account)
+	}
+	return out
+}
+
+func storageDiff(pre, post map[common.Hash]common.Hash) []domain.SlotDiff {
+	slots := make(map[common.Hash]struct{}, len(pre)+len(post))
+	for slot := range pre {
+		slots[slot] = struct{}{}
+	}
+	for slot := range post {
+		slots[slot] = struct{}{}
+	}
+	var out []domain.SlotDiff
+	for slot := range slots {
+		// A modified slot missing from post was cleared to zero.
+		before, after := pre[slot], post[slot]
+		if before == after {
+			continue
+		}
+		out = append(out, domain.SlotDiff{
+			Slot:   slot.Hex(),
+			Before: before.Hex(),
+			After:  after.Hex(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return strings.Compare(out[i].Slot, out[j].Slot) < 0 })
+	return out
+}
+
+func hexBigOrZero(v *hexutil.Big) *big.Int {
+	if v == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(v.ToInt())
+}
+
+func uint64OrZero(v *uint64) uint64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func bytesOrNil(v *hexutil.Bytes) []byte {
+	if v == nil {
+		return nil
+	}
+	return append([]byte(nil), (*v)...)
+}
@@ -0,0 +1,227 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// TraceSourceGeth uses debug_traceBlockByNumber/debug_traceTransaction
+	// with the callTracer (geth, reth, Erigon, Nethermind).
+	TraceSourceGeth = "geth"
+	// TraceSourceParity uses trace_block/trace_transaction (Erigon,
+	// Nethermind, reth).
+	TraceSourceParity = "parity"
+)
+
+var callTracerOnly = map[string]any{"tracer": "callTracer"}
+
+// blockTraces returns the call tree of every tx in the block keyed by
+// lower-case tx hash.
+func (r *BlockReader) blockTraces(ctx context.Context, number *big.Int, hashes []common.Hash) (map[string]*domain.Call, error) {
+	out := make(map[string]*domain.Call, len(hashes))
+	switch r.traceSource {
+	case TraceSourceGeth:
+		var traces []struct {
+			TxHash common.Hash `json:"txHash"`
+			Result callFrame   `json:"result"`
+			Error  string      `json:"error"`
+		}
+		if err := r.raw.CallContext(ctx, &traces, "debug_traceBlockByNumber", hexutil.EncodeBig(number), callTracerOnly); err != nil {
+			return nil, fmt.Errorf("debug_traceBlockByNumber %s: %w", number, err)
+		}
+		for i, trace := range traces {
+			if trace.Error != "" {
+				return nil, fmt.Errorf("trace tx %d of block %s: %s", i, number, trace.Error)
+			}
+			hash := trace.TxHash
+			if hash == (common.Hash{}) && i < len(hashes) {
+				// Older nodes omit txHash; results follow block order.
+				hash = hashes[i]
+			}
+			call := convertFrame(trace.Result)
+			out[strings.ToLower(hash.Hex())] = &call
+		}
+	case TraceSourceParity:
+		var traces []parityTrace
+		if err := r.raw.CallContext(ctx, &traces, "trace_block", hexutil.EncodeBig(number)); err != nil {
+			return nil, fmt.Errorf("trace_block %s: %w", number, err)
+		}
+		for hash, call := range buildParityTrees(traces) {
+			out[hash] = call
+		}
+	}
+	return out, nil
+}
+
+func (r *BlockReader) txTrace(ctx context.Context, hash common.Hash) (*domain.Call, error) {
+	switch r.traceSource {
+	case TraceSourceGeth:
+		var frame callFrame
+		if err := r.raw.CallContext(ctx, &frame, "debug_traceTransaction", hash, callTracerOnly); err != nil {
+			return nil, fmt.Errorf("debug_traceTransaction %s: %w", hash, err)
+		}
+		call := convertFrame(frame)
+		return &call, nil
+	case TraceSourceParity:
+		var traces []parityTrace
+		if err := r.raw.CallContext(ctx, &traces, "trace_transaction", hash); err != nil {
+			return nil, fmt.Errorf("trace_transaction %s: %w", hash, err)
+		}
+		return buildParityTrees(traces)[strings.ToLower(hash.Hex())], nil
+	}
+	return nil, nil
+}
+
+func convertFrame(frame callFrame) domain.Call {
+	call := domain.Call{
+		Type:    strings.ToUpper(frame.Type),
+		From:    frame.From.Hex(),
+		Value:   big.NewInt(0),
+		Input:   append([]byte(nil), frame.Input...),
+		Output:  append([]byte(nil), frame.Output...),
+		GasUsed: uint64(frame.GasUsed),
+		Error:   frame.Error,
+	}
+	if frame.To != nil {
+		call.To = frame.To.Hex()
+	}
+	if frame.Value != nil {
+		call.Value = new(big.Int).Set(frame.Value.ToInt())
+	}
+	for _, child := range frame.Calls {
+		call.Calls = append(call.Calls, convertFrame(child))
+	}
+	return call
+}
+
+// parityTrace is one flat entry of trace_block/trace_transaction; the tree is
+// encoded in TraceAddress.
+type parityTrace struct {
+	Type   string `json:"type"`
+	Action struct {
+		CallType       string          `json:"callType"`
+		From           *common.Address `json:"from"`
+		To             *common.Address `json:"to"`
+		Value          *hexutil.Big    `json:"value"`
+		Input          hexutil.Bytes   `json:"input"`
+		Init           hexutil.Bytes   `json:"init"`
+		CreationMethod string          `json:"creationMethod"`
+		Address        *common.Address `json:"address"`
+		RefundAddress  *common.Address `json:"refundAddress"`
+		Balance        *hexutil.Big    `json:"balance"`
+	} `json:"action"`
+	Result *struct {
+		GasUsed hexutil.Uint64  `json:"gasUsed"`
+		Output  hexutil.Bytes   `json:"output"`
+		Address *common.Address `json:"address"`
+	} `json:"result"`
+	Error           string       `json:"error"`
+	TraceAddress    []int        `json:"traceAddress"`
+	TransactionHash *common.Hash `json:"transactionHash"`
+}
+
+type parityNode struct {
+	call     domain.Call
+	children []*parityNode
+}
+
+// buildParityTrees rebuilds one call tree per tx. Traces arrive depth-first,
+// so every parent precedes its children.
+func buildParityTrees(traces []parityTrace) map[string]*domain.Call {
+	roots := make(map[string]*parityNode)
+	var order []string
+	nodes := make(map[string]*parityNode)
+	for _, trace := range traces {
+		if trace.TransactionHash == nil || trace.Type == "reward" {
+			continue
+		}
+		hash := strings.ToLower(trace.TransactionHash.Hex())
+		node := &parityNode{call: convertParityTrace(trace)}
+		nodes[parityKey(hash, trace.TraceAddress)] = node
+		if len(trace.TraceAddress) == 0 {
+			roots[hash] = node
+			order = append(order, hash)
+			continue
+		}
+		parent, ok := nodes[parityKey(hash, trace.TraceAddress[:len(trace.TraceAddress)-1])]
+		if !ok {
+			continue
+		}
+		parent.children = append(parent.children, node)
+	}
+
+	out := make(map[string]*domain.Call, len(roots))
+	for _, hash := range order {
+		call := roots[hash].build()
+		out[hash] = &call
+	}
+	return out
+}
+
+func (n *parityNode) build() domain.Call {
+	call := n.call
+	for _, child := range n.children {
+		call.Calls = append(call.Calls, child.build())
+	}
+	return call
+}
+
+func parityKey(hash string, path []int) string {
+	return fmt.Sprintf("%s%v", hash, path)
+}
+
+func convertParityTrace(trace parityTrace) domain.Call {
+	action := trace.Action
+	call := domain.Call{Value: big.NewInt(0), Error: trace.Error}
+	if action.Value != nil {
+		call.Value = new(big.Int).Set(action.Value.ToInt())
+	}
+	if action.From != nil {
+		call.From = action.From.Hex()
+	}
+	if trace.Result != nil {
+		call.GasUsed = uint64(trace.Result.GasUsed)
+		call.Output = append([]byte(nil), trace.Result.Output...)
+	}
+
+	switch trace.Type {
+	case "create":
+		call.Type = domain.CallTypeCreate
+		if strings.EqualFold(action.CreationMethod, "create2") {
+			call.Type = domain.CallTypeCreate2
+		}
+		call.Input = append([]byte(nil), action.Init...)
+		if trace.Result != nil && trace.Result.Address != nil {
+			call.To = trace.Result.Address.Hex()
+		}
+	case "suicide", "selfdestruct":
+		call.Type = domain.CallTypeSelfDestruct
+		if action.Address != nil {
+			call.From = action.Address.Hex()
+		}
+		if action.RefundAddress != nil {
+			call.To = action.RefundAddress.Hex()
+		}
+		if action.Balance != nil {
+			call.Value = new(big.Int).Set(action.Balance.ToInt())
+		}
+	default:
+		call.Type = strings.ToUpper(action.CallType)
+		if call.Type == "" {
+			call.Type = domain.CallTypeCall
+		}
+		call.Input = append([]byte(nil), action.Input...)
+		if action.To != nil {
+			call.To = action.To.Hex()
+		}
+	}
+	return call
+}
@@ -0,0 +1,70 @@
+CREATE TABLE addresses (
+    id      BIGSERIAL PRIMARY KEY,
+    address TEXT NOT NULL UNIQUE,
+    label   TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE blocks (
+    number      BIGINT PRIMARY KEY,
+    hash        TEXT        NOT NULL UNIQUE,
+    parent_hash TEXT        NOT NULL,
+    written_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE transactions (
+    block_number BIGINT        NOT NULL REFERENCES blocks (number) ON DELETE CASCADE,
+    tx_index     INTEGER       NOT NULL,
+    hash         TEXT          NOT NULL,
+    from_id      BIGINT        NOT NULL REFERENCES addresses (id),
+    to_id        BIGINT REFERENCES addresses (id),
+    value        NUMERIC(78,0) NOT NULL,
+    input        BYTEA         NOT NULL,
+    PRIMARY KEY (block_number, tx_index)
+);
+CREATE INDEX transactions_hash_idx ON transactions (hash);
+CREATE INDEX transactions_from_idx ON transactions (from_id);
+CREATE INDEX transactions_to_idx ON transactions (to_id);
+
+CREATE TABLE classifications (
+    block_number BIGINT  NOT NULL,
+    tx_index     INTEGER NOT NULL,
+    type         TEXT    NOT NULL,
+    selector     TEXT    NOT NULL,
+    details      TEXT    NOT NULL,
+    PRIMARY KEY (block_number, tx_index),
+    FOREIGN KEY (block_number, tx_index) REFERENCES transactions (block_number, tx_index) ON DELETE CASCADE
+);
+CREATE INDEX classifications_type_idx ON classifications (type);
+
+CREATE TABLE swaps (
+    block_number BIGINT        NOT NULL,
+    tx_index     INTEGER       NOT NULL,
+    dex          TEXT          NOT NULL,
+    pair_id      BIGINT        NOT NULL REFERENCES addresses (id),
+    sender_id    BIGINT REFERENCES addresses (id),
+    recipient_id BIGINT REFERENCES addresses (id),
+    amount0_in   NUMERIC(78,0) NOT NULL,
+    amount1_in   NUMERIC(78,0) NOT NULL,
+    amount0_out  NUMERIC(78,0) NOT NULL,
+    amount1_out  NUMERIC(78,0) NOT NULL,
+    PRIMARY KEY (block_number, tx_index),
+    FOREIGN KEY (block_number, tx_index) REFERENCES transactions (block_number, tx_index) ON DELETE CASCADE
+);
+CREATE INDEX swaps_pair_idx ON swaps (pair_id);
+
+CREATE TABLE token_transfers (
+    block_number     BIGINT        NOT NULL,
+    tx_index         INTEGER       NOT NULL,
+    log_index        INTEGER       NOT NULL,
+    standard         TEXT          NOT NULL,
+    token_address_id BIGINT        NOT NULL REFERENCES addresses (id),
+    from_id          BIGINT        NOT NULL REFERENCES addresses (id),
+    to_id            BIGINT        NOT NULL REFERENCES addresses (id),
+    amount           NUMERIC(78,0) NOT NULL,
+    token_id         NUMERIC(78,0),
+    PRIMARY KEY (block_number, log_index),
+    FOREIGN KEY (block_number, tx_index) REFERENCES transactions (block_number, tx_index) ON DELETE CASCADE
+);
+CREATE INDEX token_transfers_token_idx ON token_transfers (token_address_id);
+CREATE INDEX token_transfers_from_idx ON token_transfers (from_id);
+CREATE INDEX token_transfers_to_idx ON token_transfers (to_id);
@@ -0,0 +1,337 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/infrastructure/classifier"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const migrationLockID = 0x65746843
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+type Store struct {
+	pool *pgxpool.Pool
+}
+
+func Open(ctx context.Context, dsn string) (*Store, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("connect postgres: %w", err)
+	}
+	store := &Store{pool: pool}
+	if err := store.migrate(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *Store) Close() {
+	s.pool.Close()
+}
+
+func (s *Store) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := s.pool.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, nil
+}
+
+func (s *Store) migrate(ctx context.Context) error {
+	if _, err := s.pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_version (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("create schema_version: %w", err)
+	}
+
+	entries, err := migrations.ReadDir("migrations")
+	if err != nil {
+		return fmt.Errorf("list migrations: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin migrations: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("lock migrations: %w", err)
+	}
+	var current int
+	if err := tx.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&current); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+
+	for _, entry := range entries {
+		version, err := strconv.Atoi(strings.SplitN(entry.Name(), "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("invalid migration name %s: %w", entry.Name(), err)
+		}
+		if version <= current {
+			continue
+		}
+		script, err := migrations.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+		if _, err := tx.Exec(ctx, string(script)); err != nil {
+			return fmt.Errorf("apply migration %d: %w", version, err)
+		}
+		if _, err := tx.Exec(ctx, "INSERT INTO schema_version (version) VALUES ($1)", version); err != nil {
+			return fmt.Errorf("record migration %d: %w", version, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit migrations: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) Write(ctx context.Context, result domain.BlockResult) error {
+	number := result.Block.Number.Int64()
+	blockHash := strings.ToLower(result.Block.Hash)
+	parentHash := strings.ToLower(result.Block.ParentHash)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin write: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := retractBlocks(ctx, tx, number, blockHash, parentHash); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO blocks (number, hash, parent_hash) VALUES ($1, $2, $3)", number, blockHash, parentHash); err != nil {
+		return fmt.Errorf("insert block %d: %w", number, err)
+	}
+
+	transfers := make([][]domain.TokenTransfer, len(result.Results))
+	for i, res := range result.Results {
+		transfers[i] = classifier.TokenTransfers(res.Tx)
+	}
+	ids, err := upsertAddresses(ctx, tx, result.Results, transfers)
+	if err != nil {
+		return err
+	}
+
+	rows := buildRows(number, result.Results, transfers, ids)
+	copies := []struct {
+		table   string
+		columns []string
+		rows    [][]any
+	}{
+		{"transactions", []string{"block_number", "tx_index", "hash", "from_id", "to_id", "value", "input"}, rows.transactions},
+		{"classifications", []string{"block_number", "tx_index", "type", "selector", "details"}, rows.classifications},
+		{"swaps", []string{"block_number", "tx_index", "dex", "pair_id", "sender_id", "recipient_id", "amount0_in", "amount1_in", "amount0_out", "amount1_out"}, rows.swaps},
+		{"token_transfers", []string{"block_number", "tx_index", "log_index", "standard", "token_address_id", "from_id", "to_id", "amount", "token_id"}, rows.transfers},
+	}
+	for _, c := range copies {
+		if len(c.rows) == 0 {
+			continue
+		}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{c.table}, c.columns, pgx.CopyFromRows(c.rows)); err != nil {
+			return fmt.Errorf("copy %s for block %d: %w", c.table, number, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit block %d: %w", number, err)
+	}
+	return nil
+}
+
+// blockRows are the COPY rows of one block.
+type blockRows struct {
+	transactions, classifications, swaps, transfers [][]any
+}
+
+// buildRows lays out the rows of results, keyed by the on-chain tx index
+// since a watchlist may have filtered results.
+func buildRows(number int64, results []domain.TxResult, transfers [][]domain.TokenTransfer, ids map[string]int64) blockRows {
+	var rows blockRows
+	for i, res := range results {
+		index := int64(res.Tx.Index)
+		var toID any
+		if res.Tx.To != nil {
+			toID = ids[strings.ToLower(*res.Tx.To)]
+		}
+		input := res.Tx.Data
+		if input == nil {
+			input = []byte{}
+		}
+		rows.transactions = append(rows.transactions, []any{number, index, strings.ToLower(res.Tx.Hash), ids[strings.ToLower(res.Tx.From)], toID, numeric(res.Tx.Value), input})
+		rows.classifications = append(rows.classifications, []any{number, index, string(res.Type), res.Selector, res.Details})
+		if res.Swap != nil {
+			rows.swaps = append(rows.swaps, []any{
+				number, index, res.Swap.Dex,
+				ids[strings.ToLower(res.Swap.Pair)], optionalID(ids, res.Swap.Sender), optionalID(ids, res.Swap.Recipient),
+				numeric(res.Swap.Amount0In), numeric(res.Swap.Amount1In), numeric(res.Swap.Amount0Out), numeric(res.Swap.Amount1Out),
+			})
+		}
+		for _, transfer := range transfers[i] {
+			tokenID := pgtype.Numeric{}
+			if transfer.TokenID != nil {
+				tokenID = numeric(transfer.TokenID)
+			}
+			rows.transfers = append(rows.transfers, []any{
+				number, index, int32(transfer.LogIndex), transfer.Standard,
+				ids[transfer.Token], ids[transfer.From], ids[transfer.To],
+				numeric(transfer.Amount), tokenID,
+			})
+		}
+	}
+	return rows
+}
+
+// retraction is what must be deleted before a block is written.
+type retraction struct {
+	// fromNumber retracts every stored block at or above the block: a
+	// different block was stored at its height.
+	fromNumber bool
+	// replace deletes the stored copy of the same block, rewritten on resume.
+	replace bool
+	// parent retracts the stored block below unless it is the block's parent.
+	parent bool
+}
+
+// planRetraction decides the retraction for a block given the hash stored
+// at its height, empty when there is none.
+func planRetraction(number int64, stored, blockHash, parentHash string) retraction {
+	return retraction{
+		fromNumber: stored != "" && stored != blockHash,
+		replace:    stored != "" && stored == blockHash,
+		parent:     parentHash != "" && number > 0,
+	}
+}
+
+func retractBlocks(ctx context.Context, tx pgx.Tx, number int64, blockHash, parentHash string) error {
+	var stored string
+	err := tx.QueryRow(ctx, "SELECT hash FROM blocks WHERE number = $1", number).Scan(&stored)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("read block %d: %w", number, err)
+	}
+
+	plan := planRetraction(number, stored, blockHash, parentHash)
+	switch {
+	case plan.fromNumber:
+		if _, err := tx.Exec(ctx, "DELETE FROM blocks WHERE number >= $1", number); err != nil {
+			return fmt.Errorf("retract blocks from %d: %w", number, err)
+		}
+	case plan.replace:
+		if _, err := tx.Exec(ctx, "DELETE FROM blocks WHERE number = $1", number); err != nil {
+			return fmt.Errorf("replace block %d: %w", number, err)
+		}
+	}
+	if plan.parent {
+		if _, err := tx.Exec(ctx, "DELETE FROM blocks WHERE number = $1 AND hash <> $2", number-1, parentHash); err != nil {
+			return fmt.Errorf("retract parent of block %d: %w", number, err)
+		}
+	}
+	return nil
+}
+
+func upsertAddresses(ctx context.Context, tx pgx.Tx, results []domain.TxResult, transfers [][]domain.TokenTransfer) (map[string]int64, error) {
+	addrs, values := blockAddresses(results, transfers)
+	rows, err := tx.Query(ctx, `INSERT INTO addresses (address, label)
+		SELECT a, l FROM unnest($1::text[], $2::text[]) AS t(a, l)
+		ON CONFLICT (address) DO UPDATE
+		SET label = CASE WHEN EXCLUDED.label <> '' THEN EXCLUDED.label ELSE addresses.label END
+		RETURNING id, address`, addrs, values)
+	if err != nil {
+		return nil, fmt.Errorf("upsert addresses: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int64, len(addrs))
+	for rows.Next() {
+		var id int64
+		var addr string
+		if err := rows.Scan(&id, &addr); err != nil {
+			return nil, fmt.Errorf("scan address id: %w", err)
+		}
+		ids[addr] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("upsert addresses: %w", err)
+	}
+	return ids, nil
+}
+
+// blockAddresses lists the sorted addresses a block references with their
+// labels; a label seen for an address is kept over an empty one.
+func blockAddresses(results []domain.TxResult, transfers [][]domain.TokenTransfer) ([]string, []string) {
+	labels := make(map[string]string)
+	add := func(addr, label string) {
+		addr = strings.ToLower(addr)
+		if addr == "" {
+			return
+		}
+		if label != "" || labels[addr] == "" {
+			labels[addr] = label
+		}
+	}
+	for i, res := range results {
+		add(res.Tx.From, "")
+		if res.Tx.To != nil {
+			add(*res.Tx.To, res.ToLabel)
+		}
+		if res.Swap != nil {
+			add(res.Swap.Pair, "")
+			add(res.Swap.Sender, "")
+			add(res.Swap.Recipient, "")
+		}
+		for _, transfer := range transfers[i] {
+			add(transfer.Token, "")
+			add(transfer.From, "")
+			add(transfer.To, "")
+		}
+	}
+
+	addrs := make([]string, 0, len(labels))
+	for addr := range labels {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	values := make([]string, len(addrs))
+	for i, addr := range addrs {
+		values[i] = labels[addr]
+	}
+	return addrs, values
+}
+
+func optionalID(ids map[string]int64, addr string) any {
+	id, ok := ids[strings.ToLower(addr)]
+	if !ok {
+		return nil
+	}
+	return id
+}
+
+func numeric(v *big.Int) pgtype.Numeric {
+	if v == nil {
+		v = big.NewInt(0)
+	}
+	return pgtype.Numeric{Int: new(big.Int).Set(v), Valid: true}
+}
+
+var _ domain.ResultSink = (*Store)(nil)
@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"ethClassify/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// The Write tests need a real server; set ETHCLASSIFY_TEST_POSTGRES to a DSN
+// (e.g. the docker command in the README) to run them. Each test works in its
+// own schema, dropped afterwards. Row building and the reorg decision are
+// covered without one.
+const testDSNEnv = "ETHCLASSIFY_TEST_POSTGRES"
+
+const (
+	testToken = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testAlice = "0x1111111111111111111111111111111111111111"
+	testBob   = "0x2222222222222222222222222222222222222222"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s not set", testDSNEnv)
+	}
+	ctx := context.Background()
+
+	admin, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	schema := fmt.Sprintf("ethclassify_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		admin.Close(ctx)
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec(ctx, "DROP SCHEMA "+schema+" CASCADE")
+		admin.Close(ctx)
+	})
+
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("parse dsn: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	store := &Store{pool: pool}
+	if err := store.migrate(ctx); err != nil {
+		pool.Close()
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(store.Close)
+	return store
+}
+
+func testBlock(number int64, hash, parent string) domain.BlockResult {
+	token := testToken
+	to := testBob
+	transfer := domain.Log{
+		Address: token,
+		Topics: []string{
+			"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			"0x000000000000000000000000" + testAlice[2:],
+			"0x000000000000000000000000" + testBob[2:],
+		},
+		Data: big.NewInt(1_000_000).FillBytes(make([]byte, 32)),
+	}
+	return domain.BlockResult{
+		Block: domain.Block{Number: big.NewInt(number), Hash: hash, ParentHash: parent},
+		Results: []domain.TxResult{
+			{
+				Tx:   domain.Tx{Hash: fmt.Sprintf("0x%064x", number*10), Index: 4, From: testAlice, To: &to, Value: big.NewInt(1)},
+				Type: domain.ClassificationTransfer,
+			},
+			{
+				Tx:       domain.Tx{Hash: fmt.Sprintf("0x%064x", number*10+1), Index: 9, From: testAlice, To: &token, Value: big.NewInt(0), Data: []byte{0xa9, 0x05, 0x9c, 0xbb}, Logs: []domain.Log{transfer}},
+				Type:     domain.ClassificationERC20Transfer,
+				Selector: "0xa9059cbb",
+			},
+		},
+	}
+}
+
+func countRows(t *testing.T, store *Store, table string) int {
+	t.Helper()
+	var n int
+	if err := store.pool.QueryRow(context.Background(), "SELECT COUNT(*) FROM "+table).Scan(&n); err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return n
+}
+
+func storedHashes(t *testing.T, store *Store) map[int64]string {
+	t.Helper()
+	rows, err := store.pool.Query(context.Background(), "SELECT number, hash FROM blocks")
+	if err != nil {
+		t.Fatalf("read blocks: %v", err)
+	}
+	defer rows.Close()
+	hashes := map[int64]string{}
+	for rows.Next() {
+		var number int64
+		var hash string
+		if err := rows.Scan(&number, &hash); err != nil {
+			t.Fatalf("scan block: %v", err)
+		}
+		hashes[number] = hash
+	}
+	return hashes
+}
+
+func writeAll(t *testing.T, store *Store, blocks ...domain.BlockResult) {
+	t.Helper()
+	for _, block := range blocks {
+		if err := store.Write(context.Background(), block); err != nil {
+			t.Fatalf("write block %s: %v", block.Block.Number, err)
+		}
+	}
+}
+
+func TestWriteInsertsBlockRows(t *testing.T) {
+	store := openTestStore(t)
+	writeAll(t, store, testBlock(1, "0xa1", "0xa0"))
+
+	if version, err := store.SchemaVersion(context.Background()); err != nil || version < 1 {
+		t.Fatalf("schema version = %d, %v", version, err)
+	}
+	for table, want := range map[string]int{
+		"blocks": 1, "transactions": 2, "classifications": 2, "token_transfers": 1, "addresses": 3,
+	} {
+		if got := countRows(t, store, table); got != want {
+			t.Errorf("%s rows = %d, want %d", table, got, want)
+		}
+	}
+
+	var class, amount string
+	err := store.pool.QueryRow(context.Background(), `
+		SELECT c.type, tt.amount::TEXT
+		FROM token_transfers tt
+		JOIN classifications c USING (block_number, tx_index)`).Scan(&class, &amount)
+	if err != nil {
+		t.Fatalf("read transfer: %v", err)
+	}
+	if class != string(domain.ClassificationERC20Transfer) || amount != "1000000" {
+		t.Errorf("transfer row = %s %s", class, amount)
+	}
+}
+
+func TestWriteRetractsReorgedBlocks(t *testing.T) {
+	store := openTestStore(t)
+	writeAll(t, store,
+		testBlock(1, "0xa1", "0xa0"),
+		testBlock(2, "0xa2", "0xa1"),
+		testBlock(3, "0xa3", "0xa2"),
+	)
+
+	// A competing block 2 drops the old 2 and everything built on it.
+	writeAll(t, store, testBlock(2, "0xb2", "0xa1"))
+	hashes := storedHashes(t, store)
+	if len(hashes) != 2 || hashes[1] != "0xa1" || hashes[2] != "0xb2" {
+		t.Fatalf("blocks after reorg = %v", hashes)
+	}
+	if got := countRows(t, store, "transactions"); got != 4 {
+		t.Errorf("transactions after reorg = %d, want 4", got)
+	}
+
+	// A block whose parent hash disagrees with the stored parent retracts it.
+	writeAll(t, store, testBlock(3, "0xc3", "0xc2"))
+	hashes = storedHashes(t, store)
+	if len(hashes) != 2 || hashes[1] != "0xa1" || hashes[3] != "0xc3" {
+		t.Fatalf("blocks after parent mismatch = %v", hashes)
+	}
+}
+
+func TestWriteResumeIsIdempotent(t *testing.T) {
+	store := openTestStore(t)
+	blocks := []domain.BlockResult{
+		testBlock(1, "0xa1", "0xa0"),
+		testBlock(2, "0xa2", "0xa1"),
+		testBlock(3, "0xa3", "0xa2"),
+	}
+	writeAll(t, store, blocks...)
+
+	// A backfill resumed from an older checkpoint rewrites blocks it already
+	// stored; the rows are replaced, not duplicated.
+	writeAll(t, store, blocks[1:]...)
+	for table, want := range map[string]int{
+		"blocks": 3, "transactions": 6, "classifications": 6, "token_transfers": 3, "addresses": 3,
+	} {
+		if got := countRows(t, store, table); got != want {
+			t.Errorf("%s rows = %d, want %d", table, got, want)
+		}
+	}
+}
+
+func TestBuildRowsUsesOnChainTxIndex(t *testing.T) {
+	block := testBlock(5, "0xa5", "0xa4")
+	transfers := [][]domain.TokenTransfer{nil, {{
+		LogIndex: 2, Standard: "erc20", Token: testToken, From: testAlice, To: testBob, Amount: big.NewInt(1_000_000),
+	}}}
+	ids := map[string]int64{testToken: 1, testAlice: 2, testBob: 3}
+
+	rows := buildRows(5, block.Results, transfers, ids)
+	if len(rows.transactions) != 2 || len(rows.classifications) != 2 || len(rows.swaps) != 0 || len(rows.transfers) != 1 {
+		t.Fatalf("rows = %d tx, %d class, %d swap, %d transfer",
+			len(rows.transactions), len(rows.classifications), len(rows.swaps), len(rows.transfers))
+	}
+	for i, want := range []int64{4, 9} {
+		if got := rows.transactions[i][1]; got != want {
+			t.Errorf("transaction %d tx_index = %v, want %d", i, got, want)
+		}
+		if got := rows.classifications[i][1]; got != want {
+			t.Errorf("classification %d tx_index = %v, want %d", i, got, want)
+		}
+	}
+	transfer := rows.transfers[0]
+	if transfer[1] != int64(9) || transfer[2] != int32(2) || transfer[4] != int64(1) || transfer[5] != int64(2) || transfer[6] != int64(3) {
+		t.Errorf("transfer row = %v", transfer)
+	}
+	// A transaction without a recipient stores a NULL to_id, and empty input
+	// is stored as an empty BYTEA rather than NULL.
+	block.Results[0].Tx.To = nil
+	rows = buildRows(5, block.Results, transfers, ids)
+	if rows.transactions[0][4] != nil {
+		t.Errorf("contract creation to_id = %v, want nil", rows.transactions[0][4])
+	}
+	if input, ok := rows.transactions[0][6].([]byte); !ok || input == nil {
+		t.Errorf("empty input = %#v", rows.transactions[0][6])
+	}
+}
+
+func TestBlockAddressesKeepsLabels(t *testing.T) {
+	block := testBlock(1, "0xa1", "0xa0")
+	block.Results[0].ToLabel = "Bob"
+	checksummed := "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	block.Results[1].Tx.To = &checksummed
+	// The transfer sees Bob again without a label.
+	transfers := [][]domain.TokenTransfer{nil, {{Token: testToken, From: testAlice, To: testBob}}}
+
+	addrs, labels := blockAddresses(block.Results, transfers)
+	want := []string{testAlice, testBob, testToken}
+	if len(addrs) != len(want) || addrs[0] != want[0] || addrs[1] != want[1] || addrs[2] != want[2] {
+		t.Fatalf("addresses = %v, want %v", addrs, want)
+	}
+	if labels[0] != "" || labels[1] != "Bob" || labels[2] != "" {
+		t.Errorf("labels = %q", labels)
+	}
+}
+
+func TestPlanRetraction(t *testing.T) {
+	tests := []struct {
+		name                 string
+		number               int64
+		stored, hash, parent string
+		want                 retraction
+	}{
+		{name: "new height", number: 3, hash: "0xa3", parent: "0xa2", want: retraction{parent: true}},
+		{name: "same block rewritten", number: 3, stored: "0xa3", hash: "0xa3", parent: "0xa2", want: retraction{replace: true, parent: true}},
+		{name: "competing block", number: 3, stored: "0xa3", hash: "0xb3", parent: "0xa2", want: retraction{fromNumber: true, parent: true}},
+		{name: "genesis has no parent", number: 0, hash: "0xa0", parent: "0x00", want: retraction{}},
+		{name: "unknown parent", number: 3, stored: "0xa3", hash: "0xb3", want: retraction{fromNumber: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := planRetraction(tt.number, tt.stored, tt.hash, tt.parent); got != tt.want {
+				t.Errorf("planRetraction = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
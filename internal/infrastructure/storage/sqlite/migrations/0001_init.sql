@@ -0,0 +1,64 @@
+CREATE TABLE blocks (
+    hash   TEXT PRIMARY KEY,
+    number INTEGER NOT NULL
+);
+CREATE INDEX blocks_number_idx ON blocks (number);
+
+CREATE TABLE txs (
+    block_hash TEXT    NOT NULL REFERENCES blocks (hash) ON DELETE CASCADE,
+    tx_index   INTEGER NOT NULL,
+    hash       TEXT    NOT NULL,
+    from_addr  TEXT    NOT NULL,
+    to_addr    TEXT,
+    value      TEXT    NOT NULL,
+    data       BLOB,
+    PRIMARY KEY (block_hash, tx_index)
+);
+CREATE INDEX txs_hash_idx ON txs (hash);
+CREATE INDEX txs_from_idx ON txs (from_addr);
+CREATE INDEX txs_to_idx ON txs (to_addr);
+
+CREATE TABLE classifications (
+    block_hash TEXT    NOT NULL,
+    tx_index   INTEGER NOT NULL,
+    type       TEXT    NOT NULL,
+    selector   TEXT    NOT NULL,
+    to_label   TEXT    NOT NULL,
+    details    TEXT    NOT NULL,
+    PRIMARY KEY (block_hash, tx_index),
+    FOREIGN KEY (block_hash, tx_index) REFERENCES txs (block_hash, tx_index) ON DELETE CASCADE
+);
+CREATE INDEX classifications_type_idx ON classifications (type);
+
+CREATE TABLE swaps (
+    block_hash  TEXT    NOT NULL,
+    tx_index    INTEGER NOT NULL,
+    dex         TEXT    NOT NULL,
+    pair        TEXT    NOT NULL,
+    sender      TEXT    NOT NULL,
+    recipient   TEXT    NOT NULL,
+    amount0_in  TEXT    NOT NULL,
+    amount1_in  TEXT    NOT NULL,
+    amount0_out TEXT    NOT NULL,
+    amount1_out TEXT    NOT NULL,
+    PRIMARY KEY (block_hash, tx_index),
+    FOREIGN KEY (block_hash, tx_index) REFERENCES txs (block_hash, tx_index) ON DELETE CASCADE
+);
+CREATE INDEX swaps_pair_idx ON swaps (pair);
+
+CREATE TABLE token_transfers (
+    block_hash TEXT    NOT NULL,
+    tx_index   INTEGER NOT NULL,
+    log_index  INTEGER NOT NULL,
+    standard   TEXT    NOT NULL,
+    token      TEXT    NOT NULL,
+    from_addr  TEXT    NOT NULL,
+    to_addr    TEXT    NOT NULL,
+    amount     TEXT    NOT NULL,
+    token_id   TEXT,
+    PRIMARY KEY (block_hash, log_index),
+    FOREIGN KEY (block_hash, tx_index) REFERENCES txs (block_hash, tx_index) ON DELETE CASCADE
+);
+CREATE INDEX token_transfers_token_idx ON token_transfers (token);
+CREATE INDEX token_transfers_from_idx ON token_transfers (from_addr);
+CREATE INDEX token_transfers_to_idx ON token_transfers (to_addr);
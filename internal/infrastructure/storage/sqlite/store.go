@@ -0,0 +1,300 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"math/big"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/infrastructure/classifier"
+
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+type Store struct {
+	db *sql.DB
+}
+
+func Open(ctx context.Context, dsn string) (*Store, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite %s: %w", dsn, err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("enable foreign keys: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, "PRAGMA journal_mode = WAL"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("enable wal: %w", err)
+	}
+	store := &Store{db: db}
+	if err := store.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+
+	entries, err := migrations.ReadDir("migrations")
+	if err != nil {
+		return fmt.Errorf("list migrations: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, entry := range entries {
+		version, err := strconv.Atoi(strings.SplitN(entry.Name(), "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("invalid migration name %s: %w", entry.Name(), err)
+		}
+		if version <= current {
+			continue
+		}
+		script, err := migrations.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("begin migration %d: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("apply migration %d: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES (?)", version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("record migration %d: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) Write(ctx context.Context, result domain.BlockResult) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin write: %w", err)
+	}
+	defer tx.Rollback()
+
+	blockHash := strings.ToLower(result.Block.Hash)
+	number := result.Block.Number.Uint64()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM blocks WHERE hash = ? OR number = ?", blockHash, number); err != nil {
+		return fmt.Errorf("replace block %d: %w", number, err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO blocks (hash, number) VALUES (?, ?)", blockHash, number); err != nil {
+		return fmt.Errorf("insert block %d: %w", number, err)
+	}
+
+	for _, res := range result.Results {
+		// Results may be filtered by a watchlist, so the index comes from
+		// the tx, not from its position here.
+		i := res.Tx.Index
+		var to any
+		if res.Tx.To != nil {
+			to = strings.ToLower(*res.Tx.To)
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO txs (block_hash, tx_index, hash, from_addr, to_addr, value, data) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			blockHash, i, strings.ToLower(res.Tx.Hash), strings.ToLower(res.Tx.From), to, bigString(res.Tx.Value), res.Tx.Data,
+		); err != nil {
+			return fmt.Errorf("insert tx %s: %w", res.Tx.Hash, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO classifications (block_hash, tx_index, type, selector, to_label, details) VALUES (?, ?, ?, ?, ?, ?)",
+			blockHash, i, string(res.Type), res.Selector, res.ToLabel, res.Details,
+		); err != nil {
+			return fmt.Errorf("insert classification for tx %s: %w", res.Tx.Hash, err)
+		}
+		if res.Swap != nil {
+			if _, err := tx.ExecContext(ctx,
+				"INSERT INTO swaps (block_hash, tx_index, dex, pair, sender, recipient, amount0_in, amount1_in, amount0_out, amount1_out) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				blockHash, i, res.Swap.Dex, strings.ToLower(res.Swap.Pair), strings.ToLower(res.Swap.Sender), strings.ToLower(res.Swap.Recipient),
+				bigString(res.Swap.Amount0In), bigString(res.Swap.Amount1In), bigString(res.Swap.Amount0Out), bigString(res.Swap.Amount1Out),
+			); err != nil {
+				return fmt.Errorf("insert swap for tx %s: %w", res.Tx.Hash, err)
+			}
+		}
+		for _, transfer := range classifier.TokenTransfers(res.Tx) {
+			var tokenID any
+			if transfer.TokenID != nil {
+				tokenID = transfer.TokenID.String()
+			}
+			if _, err := tx.ExecContext(ctx,
+				"INSERT INTO token_transfers (block_hash, tx_index, log_index, standard, token, from_addr, to_addr, amount, token_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				blockHash, i, transfer.LogIndex, transfer.Standard, transfer.Token, transfer.From, transfer.To, bigString(transfer.Amount), tokenID,
+			); err != nil {
+				return fmt.Errorf("insert token transfer for tx %s: %w", res.Tx.Hash, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit block %d: %w", number, err)
+	}
+	return nil
+}
+
+func (s *Store) Query(ctx context.Context, filter domain.TxFilter) ([]domain.BlockResult, error) {
+	query := `SELECT b.number, b.hash, t.tx_index, t.hash, t.from_addr, t.to_addr, t.value, t.data,
+		c.type, c.selector, c.to_label, c.details,
+		s.dex, s.pair, s.sender, s.recipient, s.amount0_in, s.amount1_in, s.amount0_out, s.amount1_out
+	FROM txs t
+	JOIN blocks b ON b.hash = t.block_hash
+	JOIN classifications c ON c.block_hash = t.block_hash AND c.tx_index = t.tx_index
+	LEFT JOIN swaps s ON s.block_hash = t.block_hash AND s.tx_index = t.tx_index`
+
+	var where []string
+	var args []any
+	if filter.Type != "" {
+		where = append(where, "c.type = ?")
+		args = append(args, string(filter.Type))
+	}
+	if filter.Address != "" {
+		addr := strings.ToLower(filter.Address)
+		where = append(where, `(t.from_addr = ? OR t.to_addr = ? OR s.pair = ? OR EXISTS (
+			SELECT 1 FROM token_transfers tt
+			WHERE tt.block_hash = t.block_hash AND tt.tx_index = t.tx_index
+			AND (tt.token = ? OR tt.from_addr = ? OR tt.to_addr = ?)))`)
+		args = append(args, addr, addr, addr, addr, addr, addr)
+	}
+	if filter.FromBlock != nil {
+		where = append(where, "b.number >= ?")
+		args = append(args, *filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		where = append(where, "b.number <= ?")
+		args = append(args, *filter.ToBlock)
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY b.number, t.tx_index"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query txs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.BlockResult
+	for rows.Next() {
+		var (
+			number                                       uint64
+			blockHash, txHash, from, value               string
+			txIndex                                      uint
+			to                                           sql.NullString
+			data                                         []byte
+			classType, selector, toLabel, details        string
+			dex, pair, sender, recipient                 sql.NullString
+			amount0In, amount1In, amount0Out, amount1Out sql.NullString
+		)
+		if err := rows.Scan(&number, &blockHash, &txIndex, &txHash, &from, &to, &value, &data,
+			&classType, &selector, &toLabel, &details,
+			&dex, &pair, &sender, &recipient, &amount0In, &amount1In, &amount0Out, &amount1Out,
+		); err != nil {
+			return nil, fmt.Errorf("scan tx: %w", err)
+		}
+
+		result := domain.TxResult{
+			Tx: domain.Tx{
+				Hash:  txHash,
+				Index: txIndex,
+				From:  from,
+				Value: parseBig(value),
+				Data:  data,
+			},
+			Type:     domain.ClassificationType(classType),
+			Selector: selector,
+			ToLabel:  toLabel,
+			Details:  details,
+		}
+		if to.Valid {
+			addr := to.String
+			result.Tx.To = &addr
+		}
+		if dex.Valid {
+			result.Swap = &domain.SwapInfo{
+				Dex:        dex.String,
+				Pair:       pair.String,
+				Sender:     sender.String,
+				Recipient:  recipient.String,
+				Amount0In:  parseBig(amount0In.String),
+				Amount1In:  parseBig(amount1In.String),
+				Amount0Out: parseBig(amount0Out.String),
+				Amount1Out: parseBig(amount1Out.String),
+			}
+		}
+
+		if len(out) == 0 || out[len(out)-1].Block.Hash != blockHash {
+			out = append(out, domain.BlockResult{
+				Block: domain.Block{
+					Number: new(big.Int).SetUint64(number),
+					Hash:   blockHash,
+				},
+			})
+		}
+		last := &out[len(out)-1]
+		last.Block.Transactions = append(last.Block.Transactions, result.Tx)
+		last.Results = append(last.Results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate txs: %w", err)
+	}
+	return out, nil
+}
+
+func bigString(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
+
+func parseBig(v string) *big.Int {
+	n, ok := new(big.Int).SetString(v, 10)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return n
+}
+
+var (
+	_ domain.ResultSink  = (*Store)(nil)
+	_ domain.ResultStore = (*Store)(nil)
+)
@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+func TestWriteKeepsOnChainTxIndex(t *testing.T) {
+	ctx := context.Background()
+	store, err := Open(ctx, filepath.Join(t.TempDir(), "results.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer store.Close()
+
+	// A watchlist kept only the txs at positions 7 and 3 of the block.
+	result := domain.BlockResult{
+		Block: domain.Block{Number: big.NewInt(100), Hash: "0xb100"},
+		Results: []domain.TxResult{
+			{Tx: domain.Tx{Hash: "0x07", Index: 7, From: "0x01", Value: big.NewInt(0)}, Type: domain.ClassificationTransfer},
+			{Tx: domain.Tx{Hash: "0x03", Index: 3, From: "0x01", Value: big.NewInt(0)}, Type: domain.ClassificationTransfer},
+		},
+	}
+	if err := store.Write(ctx, result); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	blocks, err := store.Query(ctx, domain.TxFilter{})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(blocks) != 1 || len(blocks[0].Results) != 2 {
+		t.Fatalf("Query = %+v", blocks)
+	}
+	for i, want := range []struct {
+		hash  string
+		index uint
+	}{{"0x03", 3}, {"0x07", 7}} {
+		got := blocks[0].Results[i].Tx
+		if got.Hash != want.hash || got.Index != want.index {
+			t.Errorf("result %d = %s at %d, want %s at %d", i, got.Hash, got.Index, want.hash, want.index)
+		}
+	}
+}
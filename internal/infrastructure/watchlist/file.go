@@ -0,0 +1,41 @@
+package watchlist
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func LoadFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open watchlist: %w", err)
+	}
+	defer f.Close()
+
+	entries := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if !common.IsHexAddress(fields[0]) {
+			return nil, fmt.Errorf("watchlist %s:%d: invalid address %q", path, line, fields[0])
+		}
+		entries[strings.ToLower(fields[0])] = strings.Join(fields[1:], " ")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read watchlist: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/interface/jsonview"
+)
+
+func PrintBlockResultJSON(result domain.BlockResult) error {
+	return writeJSON(jsonview.NewBlock(result))
+}
+
+func PrintTxResultJSON(result domain.TxResult) error {
+	return writeJSON(jsonview.NewTx(result))
+}
+
+func PrintSimulationJSON(result domain.SimulationResult) error {
+	return writeJSON(jsonview.NewSimulation(result))
+}
+
+func writeJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -3,50 +3,439 @@ package cli
 import (
 	"fmt"
 	"math/big"
+	"strings"
+	"time"
 
 	"ethClassify/internal/domain"
 	"ethClassify/utils"
 )
 
-func PrintBlockResult(result domain.BlockResult) {
+// TextOptions selects the optional sections of the text output.
+type TextOptions struct {
+	Balances bool
+	// NativeSymbol is the chain's native currency (ETH when empty).
+	NativeSymbol string
+}
+
+func PrintBlockResult(result domain.BlockResult, opts TextOptions) {
 	fmt.Printf("Block Number: %s\n", result.Block.Number)
 	fmt.Printf("Block Hash:   %s\n", result.Block.Hash)
+	if seq := result.Block.Sequencer; seq != nil {
+		printSequencer(*seq)
+	}
+	if result.Blobs != nil {
+		printBlobSummary(*result.Blobs, opts)
+	}
 
 	for _, tx := range result.Results {
 		fmt.Println()
-		fmt.Printf("Tx Hash: %s\n", tx.Tx.Hash)
+		PrintTxResult(tx, opts)
+		fmt.Println("----------------")
+	}
+}
+
+func PrintTxResult(result domain.TxResult, opts TextOptions) {
+	printTx(result, opts)
+	if opts.Balances {
+		printBalances(result.Balances, opts)
+	}
+	printStateDiff(result.Tx.StateDiff)
+	printTrace(result.Trace)
+}
+
+func printSequencer(seq domain.SequencerInfo) {
+	line := fmt.Sprintf("L1 Origin:    block %d", seq.L1BlockNumber)
+	if seq.L1BlockHash != "" {
+		line = fmt.Sprintf("%s (%s) seq %d", line, seq.L1BlockHash, seq.SequenceNumber)
+	}
+	if seq.L1BaseFee != nil {
+		line = fmt.Sprintf("%s basefee %s", line, seq.L1BaseFee)
+	}
+	if seq.SendRoot != "" {
+		line = fmt.Sprintf("%s sendCount %d sendRoot %s", line, seq.SendCount, seq.SendRoot)
+	}
+	fmt.Println(line)
+}
+
+func printBlobSummary(blobs domain.BlobSummary, opts TextOptions) {
+	line := fmt.Sprintf("Blobs:        %d in %d txs, blob gas %d, excess %d", blobs.Blobs, blobs.Txs, blobs.BlobGasUsed, blobs.ExcessBlobGas)
+	if blobs.BlobGasPrice != nil {
+		line = fmt.Sprintf("%s, price %s wei, fees %s", line, blobs.BlobGasPrice, utils.WeiToNativeString(blobs.BlobFees, opts.NativeSymbol))
+	}
+	parts := make([]string, 0, len(blobs.Rollups))
+	for _, usage := range blobs.Rollups {
+		name := usage.Rollup
+		if name == "" {
+			name = "unknown"
+		}
+		parts = append(parts, fmt.Sprintf("%s=%d", name, usage.Blobs))
+	}
+	fmt.Printf("%s (%s)\n", line, strings.Join(parts, " "))
+}
+
+func printBalances(deltas []domain.BalanceDelta, opts TextOptions) {
+	if len(deltas) == 0 {
+		return
+	}
+	fmt.Println("Balance Changes:")
+	for _, delta := range deltas {
+		switch delta.Standard {
+		case domain.AssetNative:
+			fmt.Printf("  %s %s\n", delta.Address, signedNative(delta.Delta, opts.NativeSymbol))
+		case domain.AssetERC721, domain.AssetERC1155:
+			fmt.Printf("  %s %+d %s %s #%s\n", delta.Address, delta.Delta, delta.Standard, delta.Token, delta.TokenID)
+		default:
+			fmt.Printf("  %s %+d %s %s\n", delta.Address, delta.Delta, delta.Standard, delta.Token)
+		}
+	}
+}
+
+func printStateDiff(accounts []domain.AccountDiff) {
+	if len(accounts) == 0 {
+		return
+	}
+	fmt.Println("State Diff:")
+	for _, account := range accounts {
+		parts := make([]string, 0, 5)
+		switch {
+		case account.Created:
+			parts = append(parts, "created")
+		case account.Deleted:
+			parts = append(parts, "deleted")
+		}
+		if account.BalanceChanged {
+			parts = append(parts, fmt.Sprintf("balance %s -> %s", account.BalanceBefore, account.BalanceAfter))
+		}
+		if account.NonceChanged {
+			parts = append(parts, fmt.Sprintf("nonce %d -> %d", account.NonceBefore, account.NonceAfter))
+		}
+		if account.CodeChanged {
+			parts = append(parts, fmt.Sprintf("code %d -> %d bytes", len(account.CodeBefore), len(account.CodeAfter)))
+		}
+		if len(account.Storage) > 0 {
+			parts = append(parts, fmt.Sprintf("%d slots", len(account.Storage)))
+		}
+		fmt.Printf("  %s %s\n", account.Address, strings.Join(parts, ", "))
+	}
+}
+
+func signedNative(wei *big.Int, symbol string) string {
+	if wei.Sign() < 0 {
+		return "-" + utils.WeiToNativeString(new(big.Int).Neg(wei), symbol)
+	}
+	return "+" + utils.WeiToNativeString(wei, symbol)
+}
+
+func printTx(tx domain.TxResult, opts TextOptions) {
+	fmt.Printf("Tx Hash: %s\n", tx.Tx.Hash)
+	if tx.Tx.From != "" {
+		from := tx.Tx.From
+		if tx.FromLabel != "" {
+			from = fmt.Sprintf("%s (%s)", from, tx.FromLabel)
+		}
+		fmt.Printf("Tx From: %s\n", from)
+	}
+
+	to := "CONTRACT_CREATION"
+	if tx.Tx.To != nil {
+		to = *tx.Tx.To
+		if tx.ToLabel != "" {
+			to = fmt.Sprintf("%s (%s)", to, tx.ToLabel)
+		}
+	}
+	fmt.Printf("Tx To: %s\n", to)
+
+	value := ""
+	if tx.Tx.Value != nil {
+		value = fmt.Sprintf("%s wei (%s)", tx.Tx.Value, utils.WeiToNativeString(tx.Tx.Value, opts.NativeSymbol))
+	}
+	fmt.Printf("Tx Value: %s\n", value)
+	fmt.Printf("Tx Data: %x\n", tx.Tx.Data)
+	if l2 := tx.Tx.L2; l2 != nil {
+		fmt.Printf("L2 Tx: type=0x%02x kind=%s system=%t\n", tx.Tx.Type, l2.Kind, l2.IsSystem)
+	}
+	if tx.Tx.Receipt != nil && tx.Tx.Receipt.L1Fee != nil {
+		fmt.Printf("L1 Fee: %s\n", formatL1Fee(*tx.Tx.Receipt.L1Fee, opts.NativeSymbol))
+	}
+	fmt.Printf("Classification: %s\n", tx.Type)
+	if tx.Swap != nil {
+		fmt.Printf("Swap: dex=%s pair=%s sender=%s recipient=%s a0(in/out)=%s/%s a1(in/out)=%s/%s\n",
+			tx.Swap.Dex, tx.Swap.Pair, tx.Swap.Sender, tx.Swap.Recipient,
+			formatBigInt(tx.Swap.Amount0In), formatBigInt(tx.Swap.Amount0Out),
+			formatBigInt(tx.Swap.Amount1In), formatBigInt(tx.Swap.Amount1Out),
+		)
+	}
+	if tx.Intent != nil {
+		fmt.Printf("Swap Intent: router=%s method=%s path=%s recipient=%s\n",
+			tx.Intent.Router, tx.Intent.Method, strings.Join(tx.Intent.Path, ">"), tx.Intent.Recipient)
+	}
+	if tx.Batch != nil {
+		rollup := tx.Batch.Rollup
+		if rollup == "" {
+			rollup = "unknown"
+		}
+		line := fmt.Sprintf("Rollup Batch: rollup=%s blobs=%d blobGas=%d", rollup, tx.Batch.Blobs, tx.Batch.BlobGasUsed)
+		if tx.Batch.BlobFee != nil {
+			line = fmt.Sprintf("%s blobFee=%s", line, utils.WeiToNativeString(tx.Batch.BlobFee, opts.NativeSymbol))
+		}
+		fmt.Println(line)
+	}
+	if exec := tx.Safe; exec != nil {
+		success := "?"
+		if exec.Success != nil {
+			success = fmt.Sprintf("%t", *exec.Success)
+		}
+		fmt.Printf("Safe: safe=%s operation=%s to=%s multisend=%t success=%s safeTxHash=%s\n",
+			exec.Safe, exec.Operation, exec.To, exec.MultiSend, success, exec.SafeTxHash)
+		for _, sig := range exec.Signatures {
+			signer := sig.Signer
+			if signer == "" {
+				signer = "?"
+			}
+			fmt.Printf("Safe Signer: %s (%s)\n", signer, sig.Method)
+		}
+	}
+	for _, d := range tx.SetCode {
+		authority := d.Authority
+		if authority == "" {
+			authority = "?"
+		}
+		delegate := d.Delegate
+		switch {
+		case d.Revoked:
+			delegate = "none (revoked)"
+		case d.Unknown:
+			delegate += " (UNKNOWN)"
+		default:
+			delegate = fmt.Sprintf("%s (%s)", delegate, d.DelegateName)
+		}
+		line := fmt.Sprintf("Set Code: authority=%s delegate=%s chainId=%s nonce=%d", authority, delegate, formatBigInt(d.ChainID), d.Nonce)
+		if d.WrongChain {
+			line += " WRONG_CHAIN"
+		}
+		fmt.Println(line)
+	}
+	for _, account := range tx.Delegated {
+		fmt.Printf("Delegated: %s runs %s since block %d (%s)\n", account.Account, account.Delegate, account.Block, account.TxHash)
+	}
+	if tx.Bridge != nil {
+		fmt.Printf("Bridge: bridge=%s chain=%s amount=%s from=%s to=%s\n",
+			tx.Bridge.Bridge, tx.Bridge.Chain, formatBridgeAmount(*tx.Bridge, opts), tx.Bridge.From, tx.Bridge.To)
+	}
+	for _, a := range tx.Lending {
+		raw := a.Protocol == domain.LendingProtocolMorphoBlue
+		if l := a.Liquidation; l != nil {
+			fmt.Printf("Liquidation: protocol=%s market=%s borrower=%s liquidator=%s repaid=%s seized=%s\n",
+				a.Protocol, a.Market, l.Borrower, l.Liquidator,
+				formatLendingAmount(l.DebtRepaid, l.DebtAsset, raw, opts), formatLendingAmount(l.CollateralSeized, l.CollateralAsset, raw, opts))
+			continue
+		}
+		action := a.Action
+		if a.Collateral {
+			action += " collateral"
+		}
+		line := fmt.Sprintf("Lending: protocol=%s market=%s action=%s amount=%s user=%s", a.Protocol, a.Market, action, formatLendingAmount(a.Amount, a.Asset, raw, opts), a.User)
+		if a.Caller != "" && a.Caller != a.User {
+			line += " caller=" + a.Caller
+		}
+		fmt.Println(line)
+	}
+	for _, f := range tx.Flash {
+		fmt.Printf("Flash Loan: provider=%s lender=%s borrower=%s amount=%s fee=%s\n",
+			f.Provider, f.Lender, f.Borrower, formatLendingAmount(f.Amount, f.Asset, true, opts), formatLendingAmount(f.Fee, f.Asset, true, opts))
+	}
+	if tx.Selector != "" {
+		fmt.Printf("Function Selector: %s\n", tx.Selector)
+	}
+	if tx.Details != "" {
+		fmt.Printf("Details: %s\n", tx.Details)
+	}
+	if tx.Internal != nil {
+		for _, call := range tx.Internal.Transfers {
+			fmt.Printf("Internal Transfer: %s -> %s %s (%s depth %d)\n",
+				call.From, call.To, utils.WeiToNativeString(call.Value, opts.NativeSymbol), call.Type, call.Depth)
+		}
+		for _, call := range tx.Internal.SelfDestructs {
+			fmt.Printf("Self-destruct: %s -> %s %s\n", call.From, call.To, utils.WeiToNativeString(call.Value, opts.NativeSymbol))
+		}
+		for _, call := range tx.Internal.Creates {
+			fmt.Printf("Nested Create: %s created %s (%s depth %d)\n", call.From, call.To, call.Type, call.Depth)
+		}
+	}
+	for _, match := range tx.Watch {
+		addr := match.Address
+		if match.Label != "" {
+			addr = fmt.Sprintf("%s (%s)", addr, match.Label)
+		}
+		fmt.Printf("Watched: %s as %s\n", addr, match.Role)
+	}
+	printChildren(tx.Children, opts, 1)
+}
 
+// printChildren lists the inner calls of a wrapper tx, one line each plus
+// user operation and details lines, indented by nesting depth.
+func printChildren(children []domain.TxResult, opts TextOptions, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for i, child := range children {
 		to := "CONTRACT_CREATION"
-		if tx.Tx.To != nil {
-			to = *tx.Tx.To
-			if tx.ToLabel != "" {
-				to = fmt.Sprintf("%s (%s)", to, tx.ToLabel)
+		if child.Tx.To != nil {
+			to = *child.Tx.To
+			if child.ToLabel != "" {
+				to = fmt.Sprintf("%s (%s)", to, child.ToLabel)
+			}
+		}
+		line := fmt.Sprintf("%sInner Call %d: %s %s -> %s", indent, i+1, child.Type, child.Tx.From, to)
+		if child.Tx.Value != nil && child.Tx.Value.Sign() > 0 {
+			line = fmt.Sprintf("%s value %s", line, utils.WeiToNativeString(child.Tx.Value, opts.NativeSymbol))
+		}
+		if child.Selector != "" {
+			line = fmt.Sprintf("%s selector %s", line, child.Selector)
+		}
+		fmt.Println(line)
+		if op := child.UserOp; op != nil {
+			success := "?"
+			if op.Success != nil {
+				success = fmt.Sprintf("%t", *op.Success)
 			}
+			paymaster := op.Paymaster
+			if paymaster == "" {
+				paymaster = "none"
+			}
+			gasCost := "?"
+			if op.ActualGasCost != nil {
+				gasCost = utils.WeiToNativeString(op.ActualGasCost, opts.NativeSymbol)
+			}
+			fmt.Printf("%s  UserOp: sender=%s nonce=%s paymaster=%s success=%s gasCost=%s entryPoint=%s\n",
+				indent, op.Sender, formatBigInt(op.Nonce), paymaster, success, gasCost, op.Version)
 		}
-		fmt.Printf("Tx To: %s\n", to)
+		if child.Details != "" {
+			fmt.Printf("%s  Details: %s\n", indent, child.Details)
+		}
+		printChildren(child.Children, opts, depth+1)
+	}
+}
 
-		value := ""
-		if tx.Tx.Value != nil {
-			value = fmt.Sprintf("%s wei (%s ETH)", tx.Tx.Value, utils.WeiToEtherString(tx.Tx.Value))
+func PrintSimulation(result domain.SimulationResult, opts TextOptions) {
+	sim := result.Simulation
+	status := "success"
+	if !sim.Success {
+		status = "reverted"
+		if sim.Error != "" {
+			status = fmt.Sprintf("reverted (%s)", sim.Error)
 		}
-		fmt.Printf("Tx Value: %s\n", value)
-		fmt.Printf("Tx Data: %x\n", tx.Tx.Data)
-		fmt.Printf("Classification: %s\n", tx.Type)
-		if tx.Swap != nil {
-			fmt.Printf("Swap: dex=%s pair=%s sender=%s recipient=%s a0(in/out)=%s/%s a1(in/out)=%s/%s\n",
-				tx.Swap.Dex, tx.Swap.Pair, tx.Swap.Sender, tx.Swap.Recipient,
-				formatBigInt(tx.Swap.Amount0In), formatBigInt(tx.Swap.Amount0Out),
-				formatBigInt(tx.Swap.Amount1In), formatBigInt(tx.Swap.Amount1Out),
-			)
+	}
+	fmt.Printf("Simulated at block: %s via %s\n", sim.Block, sim.Tracer)
+	fmt.Printf("Status: %s\n", status)
+	if sim.GasUsed > 0 {
+		fmt.Printf("Gas Used: %d\n", sim.GasUsed)
+	}
+	if sim.LogsTracked {
+		fmt.Printf("Logs: %d\n", len(sim.Tx.Logs))
+	} else {
+		fmt.Println("Logs: not available (node without debug_traceCall)")
+	}
+	if len(sim.ReturnData) > 0 {
+		fmt.Printf("Return Data: %x\n", sim.ReturnData)
+	}
+	fmt.Println()
+	opts.Balances = true
+	PrintTxResult(result.Result, opts)
+}
+
+func PrintMempoolEvent(event domain.MempoolEvent, opts TextOptions) {
+	line := fmt.Sprintf("[%s] %s", event.Kind, event.Result.Tx.Hash)
+	switch event.Kind {
+	case domain.MempoolIncluded:
+		line = fmt.Sprintf("%s block=%s after %s", line, event.BlockNumber, event.TimeInMempool.Round(time.Millisecond))
+	case domain.MempoolPrivateInclusion:
+		line = fmt.Sprintf("%s block=%s (never seen pending)", line, event.BlockNumber)
+	case domain.MempoolReplaced:
+		line = fmt.Sprintf("%s by %s after %s", line, event.ReplacedBy, event.TimeInMempool.Round(time.Millisecond))
+	case domain.MempoolDropped:
+		line = fmt.Sprintf("%s after %s", line, event.TimeInMempool.Round(time.Millisecond))
+	}
+	fmt.Println(line)
+	if event.Kind == domain.MempoolPending || event.Kind == domain.MempoolPrivateInclusion {
+		printTx(event.Result, opts)
+		printTrace(event.Result.Trace)
+	}
+	fmt.Println("----------------")
+}
+
+func printTrace(trace []domain.TraceStep) {
+	if len(trace) == 0 {
+		return
+	}
+	fmt.Println("Explain:")
+	for _, step := range trace {
+		line := fmt.Sprintf("  [%s] %s: %s", step.Stage, step.Name, step.Outcome)
+		if step.Reason != "" {
+			line = fmt.Sprintf("%s (%s)", line, step.Reason)
 		}
-		if tx.Selector != "" {
-			fmt.Printf("Function Selector: %s\n", tx.Selector)
+		if step.Evidence != nil {
+			line = fmt.Sprintf("%s evidence: %s", line, formatEvidence(*step.Evidence))
 		}
-		if tx.Details != "" {
-			fmt.Printf("Details: %s\n", tx.Details)
+		fmt.Println(line)
+	}
+}
+
+func formatEvidence(ev domain.Evidence) string {
+	parts := make([]string, 0, 5)
+	if ev.Rule != "" {
+		parts = append(parts, fmt.Sprintf("rule=%q", ev.Rule))
+	}
+	if ev.Selector != "" {
+		parts = append(parts, "selector="+ev.Selector)
+	}
+	if ev.Topic != "" {
+		parts = append(parts, "topic="+ev.Topic)
+	}
+	if ev.Address != "" {
+		parts = append(parts, "address="+ev.Address)
+	}
+	if ev.Slot != "" {
+		parts = append(parts, "slot="+ev.Slot)
+	}
+	if ev.LogIndex != nil {
+		parts = append(parts, fmt.Sprintf("logIndex=%d", *ev.LogIndex))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatBridgeAmount shows native amounts in the chain's currency and token
+// amounts in raw units next to the token address.
+func formatBridgeAmount(transfer domain.BridgeTransfer, opts TextOptions) string {
+	if transfer.Amount == nil {
+		return "?"
+	}
+	if transfer.Token == "" {
+		return utils.WeiToNativeString(transfer.Amount, opts.NativeSymbol)
+	}
+	return fmt.Sprintf("%s %s", transfer.Amount, transfer.Token)
+}
+
+// formatLendingAmount shows lending amounts like formatBridgeAmount. An
+// empty asset is the native currency unless raw is set: Morpho Blue events
+// and some flash loans name no asset, and their amounts are shown raw.
+func formatLendingAmount(amount *big.Int, asset string, raw bool, opts TextOptions) string {
+	if asset == "" && raw {
+		if amount == nil {
+			return "?"
 		}
-		fmt.Println("----------------")
+		return amount.String()
+	}
+	return formatBridgeAmount(domain.BridgeTransfer{Amount: amount, Token: asset}, opts)
+}
+
+func formatL1Fee(fee domain.L1Fee, symbol string) string {
+	if fee.GasUsedForL1 > 0 || fee.Fee == nil {
+		return fmt.Sprintf("%d L2 gas for L1 data (l1 block %d)", fee.GasUsedForL1, fee.L1BlockNumber)
+	}
+	line := utils.WeiToNativeString(fee.Fee, symbol)
+	if fee.GasUsed != nil && fee.GasPrice != nil {
+		line = fmt.Sprintf("%s (l1 gas %s at %s wei)", line, fee.GasUsed, fee.GasPrice)
 	}
+	return line
 }
 
 func formatBigInt(v *big.Int) string {
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/interface/jsonview"
+)
+
+type Printer struct {
+	Format string
+	Text   TextOptions
+}
+
+func (p Printer) Write(ctx context.Context, result domain.BlockResult) error {
+	switch p.Format {
+	case "", "text":
+		PrintBlockResult(result, p.Text)
+		return nil
+	case "json":
+		if err := json.NewEncoder(os.Stdout).Encode(jsonview.NewBlock(result)); err != nil {
+			return fmt.Errorf("write block %s: %w", result.Block.Number, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", p.Format)
+	}
+}
+
+func (p Printer) Publish(ctx context.Context, event domain.MempoolEvent) error {
+	switch p.Format {
+	case "", "text":
+		PrintMempoolEvent(event, p.Text)
+		return nil
+	case "json":
+		if err := json.NewEncoder(os.Stdout).Encode(jsonview.NewMempoolEvent(event)); err != nil {
+			return fmt.Errorf("write mempool event %s: %w", event.Result.Tx.Hash, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", p.Format)
+	}
+}
+
+var (
+	_ domain.ResultSink  = Printer{}
+	_ domain.MempoolSink = Printer{}
+)
@@ -0,0 +1,130 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/interface/jsonview"
+	"ethClassify/internal/interface/stream"
+	"ethClassify/internal/usecase"
+)
+
+type Handler struct {
+	Blocks         usecase.CachedClassifyBlock
+	Txs            usecase.ClassifyTx
+	Stream         *stream.Handler
+	Delegations    *usecase.DelegationTracker
+	RequestTimeout time.Duration
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func (h Handler) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", h.health)
+	mux.HandleFunc("GET /blocks/latest", h.latestBlock)
+	mux.HandleFunc("GET /blocks/{number}", h.blockByNumber)
+	mux.HandleFunc("GET /tx/{hash}", h.txByHash)
+	if h.Stream != nil {
+		mux.HandleFunc("GET /stream/sse", h.Stream.SSE)
+		mux.HandleFunc("GET /stream/ws", h.Stream.WebSocket)
+	}
+	if h.Delegations != nil {
+		mux.HandleFunc("GET /delegations", h.delegations)
+	}
+	return mux
+}
+
+func (h Handler) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (h Handler) latestBlock(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	result, err := h.Blocks.Latest(ctx)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, jsonview.NewBlock(result))
+}
+
+func (h Handler) blockByNumber(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.ParseUint(r.PathValue("number"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorBody{Error: fmt.Sprintf("invalid block number %q", r.PathValue("number"))})
+		return
+	}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	result, err := h.Blocks.ExecuteNumber(ctx, number)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, jsonview.NewBlock(result))
+}
+
+func (h Handler) txByHash(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	result, err := h.Txs.Execute(ctx, r.PathValue("hash"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, jsonview.NewTx(result))
+}
+
+func (h Handler) delegations(w http.ResponseWriter, r *http.Request) {
+	accounts := h.Delegations.Accounts()
+	views := make([]jsonview.Delegated, 0, len(accounts))
+	for _, account := range accounts {
+		views = append(views, jsonview.NewDelegated(account))
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (h Handler) requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	if h.RequestTimeout <= 0 {
+		return context.WithCancel(r.Context())
+	}
+	return context.WithTimeout(r.Context(), h.RequestTimeout)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidArgument):
+		status = http.StatusBadRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return
+	}
+	writeJSON(w, status, errorBody{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/infrastructure/cache"
+	"ethClassify/internal/usecase"
+)
+
+const knownTx = "0x00000000000000000000000000000000000000000000000000000000000000aa"
+
+// testChain serves blocks up to head and the single knownTx, and counts
+// block reads. Hash validation mirrors the RPC reader's.
+type testChain struct {
+	head  uint64
+	reads atomic.Int32
+}
+
+func (c *testChain) LatestBlock(ctx context.Context) (domain.Block, error) {
+	return c.BlockByNumber(ctx, new(big.Int).SetUint64(c.head))
+}
+
+func (c *testChain) BlockByNumber(_ context.Context, number *big.Int) (domain.Block, error) {
+	c.reads.Add(1)
+	if number.Uint64() > c.head {
+		return domain.Block{}, domain.ErrNotFound
+	}
+	return domain.Block{Number: new(big.Int).Set(number), Hash: fmt.Sprintf("0x%x", number)}, nil
+}
+
+func (c *testChain) TransactionByHash(_ context.Context, hash string) (domain.Tx, error) {
+	if len(hash) != 66 || !strings.HasPrefix(hash, "0x") {
+		return domain.Tx{}, fmt.Errorf("%w: tx hash %q", domain.ErrInvalidArgument, hash)
+	}
+	if hash != knownTx {
+		return domain.Tx{}, fmt.Errorf("fetch tx %s: %w", hash, domain.ErrNotFound)
+	}
+	return domain.Tx{Hash: hash, Value: big.NewInt(1)}, nil
+}
+
+func (c *testChain) FinalizedNumber(context.Context) (uint64, error) { return c.head - 2, nil }
+
+type transferClassifier struct{}
+
+func (transferClassifier) Classify(_ context.Context, tx domain.Tx) (domain.TxResult, bool, error) {
+	return domain.TxResult{Tx: tx, Type: domain.ClassificationTransfer}, true, nil
+}
+
+func newTestServer(t *testing.T, chain *testChain) *httptest.Server {
+	t.Helper()
+	pipeline := usecase.Pipeline{Classifiers: []domain.TxClassifier{transferClassifier{}}}
+	handler := Handler{
+		Blocks: usecase.NewCachedClassifyBlock(usecase.ClassifyBlock{Reader: chain, Pipeline: pipeline}, chain, cache.NewBlockLRU(8)),
+		Txs:    usecase.ClassifyTx{Reader: chain, Pipeline: pipeline},
+	}
+	server := httptest.NewServer(handler.Routes())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, server *httptest.Server, path string) (int, map[string]any) {
+	t.Helper()
+	resp, err := http.Get(server.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+	return resp.StatusCode, body
+}
+
+func TestHandlerStatusCodes(t *testing.T) {
+	server := newTestServer(t, &testChain{head: 100})
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/healthz", http.StatusOK},
+		{"/blocks/latest", http.StatusOK},
+		{"/blocks/42", http.StatusOK},
+		{"/blocks/abc", http.StatusBadRequest},
+		{"/blocks/101", http.StatusNotFound},
+		{"/tx/" + knownTx, http.StatusOK},
+		{"/tx/0x1234", http.StatusBadRequest},
+		{"/tx/" + strings.Repeat("0", 64) + "bb", http.StatusBadRequest},
+		{"/tx/0x" + strings.Repeat("0", 62) + "bb", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			status, body := get(t, server, tt.path)
+			if status != tt.want {
+				t.Fatalf("status = %d, want %d (body %v)", status, tt.want, body)
+			}
+			if msg, _ := body["error"].(string); status >= 400 && msg == "" {
+				t.Errorf("error body = %v, want an error message", body)
+			}
+		})
+	}
+}
+
+func TestHandlerServesFinalizedBlocksFromCache(t *testing.T) {
+	chain := &testChain{head: 100}
+	server := newTestServer(t, chain)
+
+	for range 3 {
+		if status, body := get(t, server, "/blocks/98"); status != http.StatusOK || body["hash"] != "0x62" {
+			t.Fatalf("GET /blocks/98 = %d %v", status, body)
+		}
+	}
+	if reads := chain.reads.Load(); reads != 1 {
+		t.Errorf("finalized block read %d times, want 1", reads)
+	}
+
+	// Block 99 is past the finalized block and is read on every request.
+	for range 2 {
+		if status, _ := get(t, server, "/blocks/99"); status != http.StatusOK {
+			t.Fatalf("GET /blocks/99 = %d", status)
+		}
+	}
+	if reads := chain.reads.Load(); reads != 3 {
+		t.Errorf("reads = %d, want 3 with the unfinalized block uncached", reads)
+	}
+}
@@ -0,0 +1,717 @@
+package jsonview
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"ethClassify/internal/domain"
+)
+
+type Block struct {
+	Number     string     `json:"number"`
+	Hash       string     `json:"hash"`
+	ParentHash string     `json:"parentHash,omitempty"`
+	Sequencer  *Sequencer `json:"sequencer,omitempty"`
+	Blobs      *Blobs     `json:"blobs,omitempty"`
+	Results    []Tx       `json:"results"`
+}
+
+type Blobs struct {
+	Txs           int          `json:"txs"`
+	Blobs         int          `json:"blobs"`
+	BlobGasUsed   uint64       `json:"blobGasUsed"`
+	ExcessBlobGas uint64       `json:"excessBlobGas"`
+	BlobGasPrice  string       `json:"blobGasPrice,omitempty"`
+	BlobFees      string       `json:"blobFees,omitempty"`
+	Rollups       []RollupBlob `json:"rollups"`
+}
+
+type RollupBlob struct {
+	Rollup  string `json:"rollup"`
+	Txs     int    `json:"txs"`
+	Blobs   int    `json:"blobs"`
+	BlobFee string `json:"blobFee,omitempty"`
+}
+
+type Sequencer struct {
+	L1BlockNumber  uint64 `json:"l1BlockNumber"`
+	L1BlockHash    string `json:"l1BlockHash,omitempty"`
+	L1BaseFee      string `json:"l1BaseFee,omitempty"`
+	L1BlobBaseFee  string `json:"l1BlobBaseFee,omitempty"`
+	SequenceNumber uint64 `json:"sequenceNumber,omitempty"`
+	SendCount      uint64 `json:"sendCount,omitempty"`
+	SendRoot       string `json:"sendRoot,omitempty"`
+}
+
+type Tx struct {
+	Hash      string      `json:"hash"`
+	From      string      `json:"from,omitempty"`
+	FromLabel string      `json:"fromLabel,omitempty"`
+	To        *string     `json:"to"`
+	ToLabel   string      `json:"toLabel,omitempty"`
+	Value     string      `json:"value"`
+	Data      string      `json:"data"`
+	TxType    uint8       `json:"txType,omitempty"`
+	L2        *L2Tx       `json:"l2,omitempty"`
+	L1Fee     *L1Fee      `json:"l1Fee,omitempty"`
+	Type      string      `json:"type"`
+	Selector  string      `json:"selector,omitempty"`
+	Swap      *Swap       `json:"swap,omitempty"`
+	Intent    *Intent     `json:"intent,omitempty"`
+	Bridge    *Bridge     `json:"bridge,omitempty"`
+	Lending   []Lending   `json:"lending,omitempty"`
+	Flash     []FlashLoan `json:"flashLoans,omitempty"`
+	Batch     *Batch      `json:"batch,omitempty"`
+	UserOp    *UserOp     `json:"userOp,omitempty"`
+	Safe      *Safe       `json:"safe,omitempty"`
+	SetCode   []SetCode   `json:"setCode,omitempty"`
+	Delegated []Delegated `json:"delegated,omitempty"`
+	Internal  *Internal   `json:"internal,omitempty"`
+	Balances  []Balance   `json:"balances,omitempty"`
+	Calls     *Call       `json:"calls,omitempty"`
+	StateDiff []Account   `json:"stateDiff,omitempty"`
+	Details   string      `json:"details,omitempty"`
+	Watch     []Watch     `json:"watch,omitempty"`
+	Trace     []TraceStep `json:"trace,omitempty"`
+	Children  []Tx        `json:"children,omitempty"`
+}
+
+type Safe struct {
+	Safe       string          `json:"safe"`
+	To         string          `json:"to"`
+	Value      string          `json:"value"`
+	Operation  string          `json:"operation"`
+	MultiSend  bool            `json:"multiSend,omitempty"`
+	SafeTxHash string          `json:"safeTxHash,omitempty"`
+	Success    *bool           `json:"success,omitempty"`
+	Payment    string          `json:"payment,omitempty"`
+	Signatures []SafeSignature `json:"signatures"`
+}
+
+type SafeSignature struct {
+	Signer string `json:"signer,omitempty"`
+	Method string `json:"method"`
+}
+
+type SetCode struct {
+	Authority    string `json:"authority,omitempty"`
+	Delegate     string `json:"delegate"`
+	DelegateName string `json:"delegateName,omitempty"`
+	ChainID      string `json:"chainId"`
+	Nonce        uint64 `json:"nonce"`
+	Revoked      bool   `json:"revoked,omitempty"`
+	Unknown      bool   `json:"unknownDelegate,omitempty"`
+	WrongChain   bool   `json:"wrongChain,omitempty"`
+}
+
+type Delegated struct {
+	Account  string `json:"account"`
+	Delegate string `json:"delegate"`
+	Block    uint64 `json:"block"`
+	TxHash   string `json:"txHash"`
+}
+
+type UserOp struct {
+	EntryPoint    string `json:"entryPoint"`
+	Version       string `json:"version"`
+	Hash          string `json:"hash,omitempty"`
+	Sender        string `json:"sender"`
+	Nonce         string `json:"nonce"`
+	Paymaster     string `json:"paymaster,omitempty"`
+	Success       *bool  `json:"success,omitempty"`
+	ActualGasCost string `json:"actualGasCost,omitempty"`
+	ActualGasUsed string `json:"actualGasUsed,omitempty"`
+}
+
+type L2Tx struct {
+	Kind             string  `json:"kind"`
+	IsSystem         bool    `json:"isSystem,omitempty"`
+	SourceHash       string  `json:"sourceHash,omitempty"`
+	Mint             string  `json:"mint,omitempty"`
+	RequestID        string  `json:"requestId,omitempty"`
+	TicketID         string  `json:"ticketId,omitempty"`
+	RefundTo         string  `json:"refundTo,omitempty"`
+	Beneficiary      string  `json:"beneficiary,omitempty"`
+	L1BaseFee        string  `json:"l1BaseFee,omitempty"`
+	DepositValue     string  `json:"depositValue,omitempty"`
+	MaxSubmissionFee string  `json:"maxSubmissionFee,omitempty"`
+	MaxRefund        string  `json:"maxRefund,omitempty"`
+	RetryTo          *string `json:"retryTo,omitempty"`
+	RetryValue       string  `json:"retryValue,omitempty"`
+	RetryData        string  `json:"retryData,omitempty"`
+}
+
+type L1Fee struct {
+	Fee               string `json:"fee,omitempty"`
+	GasPrice          string `json:"gasPrice,omitempty"`
+	GasUsed           string `json:"gasUsed,omitempty"`
+	FeeScalar         string `json:"feeScalar,omitempty"`
+	BaseFeeScalar     uint64 `json:"baseFeeScalar,omitempty"`
+	BlobBaseFee       string `json:"blobBaseFee,omitempty"`
+	BlobBaseFeeScalar uint64 `json:"blobBaseFeeScalar,omitempty"`
+	GasUsedForL1      uint64 `json:"gasUsedForL1,omitempty"`
+	L1BlockNumber     uint64 `json:"l1BlockNumber,omitempty"`
+}
+
+type Watch struct {
+	Address string `json:"address"`
+	Label   string `json:"label,omitempty"`
+	Role    string `json:"role"`
+}
+
+type Swap struct {
+	Dex        string `json:"dex"`
+	Pair       string `json:"pair"`
+	Sender     string `json:"sender"`
+	Recipient  string `json:"recipient"`
+	Amount0In  string `json:"amount0In"`
+	Amount1In  string `json:"amount1In"`
+	Amount0Out string `json:"amount0Out"`
+	Amount1Out string `json:"amount1Out"`
+}
+
+type Intent struct {
+	Router       string   `json:"router"`
+	Method       string   `json:"method"`
+	Path         []string `json:"path"`
+	AmountIn     string   `json:"amountIn,omitempty"`
+	AmountInMax  string   `json:"amountInMax,omitempty"`
+	AmountOut    string   `json:"amountOut,omitempty"`
+	AmountOutMin string   `json:"amountOutMin,omitempty"`
+	Recipient    string   `json:"recipient"`
+	Deadline     string   `json:"deadline,omitempty"`
+}
+
+type Bridge struct {
+	Bridge string `json:"bridge"`
+	Chain  string `json:"chain"`
+	Token  string `json:"token,omitempty"`
+	Amount string `json:"amount,omitempty"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+type Lending struct {
+	Protocol    string       `json:"protocol"`
+	Market      string       `json:"market"`
+	Action      string       `json:"action"`
+	Asset       string       `json:"asset,omitempty"`
+	Amount      string       `json:"amount,omitempty"`
+	User        string       `json:"user,omitempty"`
+	Caller      string       `json:"caller,omitempty"`
+	Collateral  bool         `json:"collateral,omitempty"`
+	Liquidation *Liquidation `json:"liquidation,omitempty"`
+}
+
+type Liquidation struct {
+	Borrower         string `json:"borrower"`
+	Liquidator       string `json:"liquidator"`
+	DebtAsset        string `json:"debtAsset,omitempty"`
+	DebtRepaid       string `json:"debtRepaid,omitempty"`
+	CollateralAsset  string `json:"collateralAsset,omitempty"`
+	CollateralSeized string `json:"collateralSeized,omitempty"`
+}
+
+type FlashLoan struct {
+	Provider string `json:"provider"`
+	Lender   string `json:"lender"`
+	Borrower string `json:"borrower,omitempty"`
+	Asset    string `json:"asset,omitempty"`
+	Amount   string `json:"amount,omitempty"`
+	Fee      string `json:"fee,omitempty"`
+}
+
+type Batch struct {
+	Rollup       string `json:"rollup,omitempty"`
+	Blobs        int    `json:"blobs"`
+	BlobGasUsed  uint64 `json:"blobGasUsed"`
+	BlobGasPrice string `json:"blobGasPrice,omitempty"`
+	BlobFee      string `json:"blobFee,omitempty"`
+}
+
+type Internal struct {
+	Transfers     []InternalCall `json:"transfers,omitempty"`
+	SelfDestructs []InternalCall `json:"selfDestructs,omitempty"`
+	Creates       []InternalCall `json:"creates,omitempty"`
+}
+
+type InternalCall struct {
+	Type  string `json:"type"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+	Depth int    `json:"depth"`
+}
+
+type Balance struct {
+	Address  string `json:"address"`
+	Standard string `json:"standard"`
+	Token    string `json:"token,omitempty"`
+	TokenID  string `json:"tokenId,omitempty"`
+	Delta    string `json:"delta"`
+}
+
+type Account struct {
+	Address        string     `json:"address"`
+	Created        bool       `json:"created,omitempty"`
+	Deleted        bool       `json:"deleted,omitempty"`
+	BalanceBefore  string     `json:"balanceBefore,omitempty"`
+	BalanceAfter   string     `json:"balanceAfter,omitempty"`
+	NonceBefore    *uint64    `json:"nonceBefore,omitempty"`
+	NonceAfter     *uint64    `json:"nonceAfter,omitempty"`
+	CodeSizeBefore *int       `json:"codeSizeBefore,omitempty"`
+	CodeSizeAfter  *int       `json:"codeSizeAfter,omitempty"`
+	Storage        []SlotDiff `json:"storage,omitempty"`
+}
+
+type SlotDiff struct {
+	Slot   string `json:"slot"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+type Call struct {
+	Type    string `json:"type"`
+	From    string `json:"from"`
+	To      string `json:"to,omitempty"`
+	Value   string `json:"value"`
+	Input   string `json:"input,omitempty"`
+	Output  string `json:"output,omitempty"`
+	GasUsed uint64 `json:"gasUsed"`
+	Error   string `json:"error,omitempty"`
+	Calls   []Call `json:"calls,omitempty"`
+}
+
+type MempoolEvent struct {
+	Kind            string  `json:"kind"`
+	SeenAt          *string `json:"seenAt,omitempty"`
+	TimeInMempoolMs *int64  `json:"timeInMempoolMs,omitempty"`
+	BlockNumber     string  `json:"blockNumber,omitempty"`
+	BlockHash       string  `json:"blockHash,omitempty"`
+	ReplacedBy      string  `json:"replacedBy,omitempty"`
+	Tx              Tx      `json:"tx"`
+}
+
+type Simulation struct {
+	Block       string `json:"block"`
+	Tracer      string `json:"tracer"`
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
+	GasUsed     uint64 `json:"gasUsed,omitempty"`
+	ReturnData  string `json:"returnData,omitempty"`
+	LogsTracked bool   `json:"logsTracked"`
+	Logs        int    `json:"logs"`
+	Tx          Tx     `json:"tx"`
+}
+
+type TraceStep struct {
+	Stage    string    `json:"stage"`
+	Name     string    `json:"name"`
+	Outcome  string    `json:"outcome"`
+	Reason   string    `json:"reason,omitempty"`
+	Evidence *Evidence `json:"evidence,omitempty"`
+}
+
+type Evidence struct {
+	Rule     string `json:"rule,omitempty"`
+	Selector string `json:"selector,omitempty"`
+	Topic    string `json:"topic,omitempty"`
+	Address  string `json:"address,omitempty"`
+	Slot     string `json:"slot,omitempty"`
+	LogIndex *uint  `json:"logIndex,omitempty"`
+}
+
+func NewBlock(result domain.BlockResult) Block {
+	txs := make([]Tx, 0, len(result.Results))
+	for _, res := range result.Results {
+		txs = append(txs, NewTx(res))
+	}
+	view := Block{
+		Number:     bigString(result.Block.Number),
+		Hash:       result.Block.Hash,
+		ParentHash: result.Block.ParentHash,
+		Results:    txs,
+	}
+	if seq := result.Block.Sequencer; seq != nil {
+		view.Sequencer = &Sequencer{
+			L1BlockNumber:  seq.L1BlockNumber,
+			L1BlockHash:    seq.L1BlockHash,
+			L1BaseFee:      optionalBig(seq.L1BaseFee),
+			L1BlobBaseFee:  optionalBig(seq.L1BlobBaseFee),
+			SequenceNumber: seq.SequenceNumber,
+			SendCount:      seq.SendCount,
+			SendRoot:       seq.SendRoot,
+		}
+	}
+	if blobs := result.Blobs; blobs != nil {
+		view.Blobs = &Blobs{
+			Txs:           blobs.Txs,
+			Blobs:         blobs.Blobs,
+			BlobGasUsed:   blobs.BlobGasUsed,
+			ExcessBlobGas: blobs.ExcessBlobGas,
+			BlobGasPrice:  optionalBig(blobs.BlobGasPrice),
+			BlobFees:      optionalBig(blobs.BlobFees),
+			Rollups:       make([]RollupBlob, 0, len(blobs.Rollups)),
+		}
+		for _, usage := range blobs.Rollups {
+			view.Blobs.Rollups = append(view.Blobs.Rollups, RollupBlob{
+				Rollup:  usage.Rollup,
+				Txs:     usage.Txs,
+				Blobs:   usage.Blobs,
+				BlobFee: optionalBig(usage.BlobFee),
+			})
+		}
+	}
+	return view
+}
+
+func NewTx(result domain.TxResult) Tx {
+	view := Tx{
+		Hash:      result.Tx.Hash,
+		From:      result.Tx.From,
+		FromLabel: result.FromLabel,
+		To:        result.Tx.To,
+		ToLabel:   result.ToLabel,
+		Value:     bigString(result.Tx.Value),
+		Data:      fmt.Sprintf("0x%x", result.Tx.Data),
+		TxType:    result.Tx.Type,
+		Type:      string(result.Type),
+		Selector:  result.Selector,
+		Details:   result.Details,
+	}
+	if l2 := result.Tx.L2; l2 != nil {
+		view.L2 = &L2Tx{
+			Kind:             l2.Kind,
+			IsSystem:         l2.IsSystem,
+			SourceHash:       l2.SourceHash,
+			Mint:             optionalBig(l2.Mint),
+			RequestID:        l2.RequestID,
+			TicketID:         l2.TicketID,
+			RefundTo:         l2.RefundTo,
+			Beneficiary:      l2.Beneficiary,
+			L1BaseFee:        optionalBig(l2.L1BaseFee),
+			DepositValue:     optionalBig(l2.DepositValue),
+			MaxSubmissionFee: optionalBig(l2.MaxSubmissionFee),
+			MaxRefund:        optionalBig(l2.MaxRefund),
+			RetryTo:          l2.RetryTo,
+			RetryValue:       optionalBig(l2.RetryValue),
+		}
+		if len(l2.RetryData) > 0 {
+			view.L2.RetryData = fmt.Sprintf("0x%x", l2.RetryData)
+		}
+	}
+	if result.Tx.Receipt != nil && result.Tx.Receipt.L1Fee != nil {
+		fee := result.Tx.Receipt.L1Fee
+		view.L1Fee = &L1Fee{
+			Fee:               optionalBig(fee.Fee),
+			GasPrice:          optionalBig(fee.GasPrice),
+			GasUsed:           optionalBig(fee.GasUsed),
+			FeeScalar:         fee.FeeScalar,
+			BaseFeeScalar:     fee.BaseFeeScalar,
+			BlobBaseFee:       optionalBig(fee.BlobBaseFee),
+			BlobBaseFeeScalar: fee.BlobBaseFeeScalar,
+			GasUsedForL1:      fee.GasUsedForL1,
+			L1BlockNumber:     fee.L1BlockNumber,
+		}
+	}
+	if result.Swap != nil {
+		view.Swap = &Swap{
+			Dex:        result.Swap.Dex,
+			Pair:       result.Swap.Pair,
+			Sender:     result.Swap.Sender,
+			Recipient:  result.Swap.Recipient,
+			Amount0In:  bigString(result.Swap.Amount0In),
+			Amount1In:  bigString(result.Swap.Amount1In),
+			Amount0Out: bigString(result.Swap.Amount0Out),
+			Amount1Out: bigString(result.Swap.Amount1Out),
+		}
+	}
+	if result.Intent != nil {
+		view.Intent = &Intent{
+			Router:       result.Intent.Router,
+			Method:       result.Intent.Method,
+			Path:         result.Intent.Path,
+			AmountIn:     optionalBig(result.Intent.AmountIn),
+			AmountInMax:  optionalBig(result.Intent.AmountInMax),
+			AmountOut:    optionalBig(result.Intent.AmountOut),
+			AmountOutMin: optionalBig(result.Intent.AmountOutMin),
+			Recipient:    result.Intent.Recipient,
+			Deadline:     optionalBig(result.Intent.Deadline),
+		}
+	}
+	if result.Bridge != nil {
+		view.Bridge = &Bridge{
+			Bridge: result.Bridge.Bridge,
+			Chain:  result.Bridge.Chain,
+			Token:  result.Bridge.Token,
+			Amount: optionalBig(result.Bridge.Amount),
+			From:   result.Bridge.From,
+			To:     result.Bridge.To,
+		}
+	}
+	for _, a := range result.Lending {
+		lending := Lending{
+			Protocol:   a.Protocol,
+			Market:     a.Market,
+			Action:     a.Action,
+			Asset:      a.Asset,
+			Amount:     optionalBig(a.Amount),
+			User:       a.User,
+			Caller:     a.Caller,
+			Collateral: a.Collateral,
+		}
+		if l := a.Liquidation; l != nil {
+			lending.Liquidation = &Liquidation{
+				Borrower:         l.Borrower,
+				Liquidator:       l.Liquidator,
+				DebtAsset:        l.DebtAsset,
+				DebtRepaid:       optionalBig(l.DebtRepaid),
+				CollateralAsset:  l.CollateralAsset,
+				CollateralSeized: optionalBig(l.CollateralSeized),
+			}
+		}
+		view.Lending = append(view.Lending, lending)
+	}
+	for _, f := range result.Flash {
+		view.Flash = append(view.Flash, FlashLoan{
+			Provider: f.Provider,
+			Lender:   f.Lender,
+			Borrower: f.Borrower,
+			Asset:    f.Asset,
+			Amount:   optionalBig(f.Amount),
+			Fee:      optionalBig(f.Fee),
+		})
+	}
+	if result.Batch != nil {
+		view.Batch = &Batch{
+			Rollup:       result.Batch.Rollup,
+			Blobs:        result.Batch.Blobs,
+			BlobGasUsed:  result.Batch.BlobGasUsed,
+			BlobGasPrice: optionalBig(result.Batch.BlobGasPrice),
+			BlobFee:      optionalBig(result.Batch.BlobFee),
+		}
+	}
+	if op := result.UserOp; op != nil {
+		view.UserOp = &UserOp{
+			EntryPoint:    op.EntryPoint,
+			Version:       op.Version,
+			Hash:          op.Hash,
+			Sender:        op.Sender,
+			Nonce:         bigString(op.Nonce),
+			Paymaster:     op.Paymaster,
+			Success:       op.Success,
+			ActualGasCost: optionalBig(op.ActualGasCost),
+			ActualGasUsed: optionalBig(op.ActualGasUsed),
+		}
+	}
+	if exec := result.Safe; exec != nil {
+		view.Safe = &Safe{
+			Safe:       exec.Safe,
+			To:         exec.To,
+			Value:      bigString(exec.Value),
+			Operation:  exec.Operation,
+			MultiSend:  exec.MultiSend,
+			SafeTxHash: exec.SafeTxHash,
+			Success:    exec.Success,
+			Payment:    optionalBig(exec.Payment),
+			Signatures: make([]SafeSignature, 0, len(exec.Signatures)),
+		}
+		for _, sig := range exec.Signatures {
+			view.Safe.Signatures = append(view.Safe.Signatures, SafeSignature{Signer: sig.Signer, Method: sig.Method})
+		}
+	}
+	for _, d := range result.SetCode {
+		view.SetCode = append(view.SetCode, SetCode{
+			Authority:    d.Authority,
+			Delegate:     d.Delegate,
+			DelegateName: d.DelegateName,
+			ChainID:      bigString(d.ChainID),
+			Nonce:        d.Nonce,
+			Revoked:      d.Revoked,
+			Unknown:      d.Unknown,
+			WrongChain:   d.WrongChain,
+		})
+	}
+	for _, account := range result.Delegated {
+		view.Delegated = append(view.Delegated, NewDelegated(account))
+	}
+	for _, child := range result.Children {
+		view.Children = append(view.Children, NewTx(child))
+	}
+	if result.Internal != nil {
+		view.Internal = &Internal{
+			Transfers:     newInternalCalls(result.Internal.Transfers),
+			SelfDestructs: newInternalCalls(result.Internal.SelfDestructs),
+			Creates:       newInternalCalls(result.Internal.Creates),
+		}
+	}
+	for _, delta := range result.Balances {
+		view.Balances = append(view.Balances, Balance{
+			Address:  delta.Address,
+			Standard: delta.Standard,
+			Token:    delta.Token,
+			TokenID:  optionalBig(delta.TokenID),
+			Delta:    bigString(delta.Delta),
+		})
+	}
+	for _, account := range result.Tx.StateDiff {
+		view.StateDiff = append(view.StateDiff, newAccount(account))
+	}
+	if result.Tx.Calls != nil {
+		root := newCall(*result.Tx.Calls)
+		view.Calls = &root
+	}
+	for _, match := range result.Watch {
+		view.Watch = append(view.Watch, Watch{
+			Address: match.Address,
+			Label:   match.Label,
+			Role:    string(match.Role),
+		})
+	}
+	for _, step := range result.Trace {
+		view.Trace = append(view.Trace, newTraceStep(step))
+	}
+	return view
+}
+
+func newInternalCalls(calls []domain.InternalCall) []InternalCall {
+	var out []InternalCall
+	for _, call := range calls {
+		out = append(out, InternalCall{
+			Type:  call.Type,
+			From:  call.From,
+			To:    call.To,
+			Value: bigString(call.Value),
+			Depth: call.Depth,
+		})
+	}
+	return out
+}
+
+func newAccount(account domain.AccountDiff) Account {
+	view := Account{
+		Address: account.Address,
+		Created: account.Created,
+		Deleted: account.Deleted,
+	}
+	if account.BalanceChanged {
+		view.BalanceBefore = bigString(account.BalanceBefore)
+		view.BalanceAfter = bigString(account.BalanceAfter)
+	}
+	if account.NonceChanged {
+		before, after := account.NonceBefore, account.NonceAfter
+		view.NonceBefore, view.NonceAfter = &before, &after
+	}
+	if account.CodeChanged {
+		before, after := len(account.CodeBefore), len(account.CodeAfter)
+		view.CodeSizeBefore, view.CodeSizeAfter = &before, &after
+	}
+	for _, slot := range account.Storage {
+		view.Storage = append(view.Storage, SlotDiff{Slot: slot.Slot, Before: slot.Before, After: slot.After})
+	}
+	return view
+}
+
+func newCall(call domain.Call) Call {
+	view := Call{
+		Type:    call.Type,
+		From:    call.From,
+		To:      call.To,
+		Value:   bigString(call.Value),
+		GasUsed: call.GasUsed,
+		Error:   call.Error,
+	}
+	if len(call.Input) > 0 {
+		view.Input = fmt.Sprintf("0x%x", call.Input)
+	}
+	if len(call.Output) > 0 {
+		view.Output = fmt.Sprintf("0x%x", call.Output)
+	}
+	for _, child := range call.Calls {
+		view.Calls = append(view.Calls, newCall(child))
+	}
+	return view
+}
+
+func NewDelegated(account domain.DelegatedAccount) Delegated {
+	return Delegated{
+		Account:  account.Account,
+		Delegate: account.Delegate,
+		Block:    account.Block,
+		TxHash:   account.TxHash,
+	}
+}
+
+func NewMempoolEvent(event domain.MempoolEvent) MempoolEvent {
+	view := MempoolEvent{
+		Kind:       string(event.Kind),
+		BlockHash:  event.BlockHash,
+		ReplacedBy: event.ReplacedBy,
+		Tx:         NewTx(event.Result),
+	}
+	if !event.SeenAt.IsZero() {
+		seen := event.SeenAt.UTC().Format(time.RFC3339Nano)
+		view.SeenAt = &seen
+	}
+	if event.Kind != domain.MempoolPending && event.Kind != domain.MempoolPrivateInclusion {
+		ms := event.TimeInMempool.Milliseconds()
+		view.TimeInMempoolMs = &ms
+	}
+	if event.BlockNumber != nil {
+		view.BlockNumber = event.BlockNumber.String()
+	}
+	return view
+}
+
+func NewSimulation(result domain.SimulationResult) Simulation {
+	sim := result.Simulation
+	view := Simulation{
+		Block:       sim.Block,
+		Tracer:      sim.Tracer,
+		Success:     sim.Success,
+		Error:       sim.Error,
+		GasUsed:     sim.GasUsed,
+		LogsTracked: sim.LogsTracked,
+		Logs:        len(sim.Tx.Logs),
+		Tx:          NewTx(result.Result),
+	}
+	if len(sim.ReturnData) > 0 {
+		view.ReturnData = fmt.Sprintf("0x%x", sim.ReturnData)
+	}
+	return view
+}
+
+func newTraceStep(step domain.TraceStep) TraceStep {
+	view := TraceStep{
+		Stage:   step.Stage,
+		Name:    step.Name,
+		Outcome: string(step.Outcome),
+		Reason:  step.Reason,
+	}
+	if step.Evidence != nil {
+		view.Evidence = &Evidence{
+			Rule:     step.Evidence.Rule,
+			Selector: step.Evidence.Selector,
+			Topic:    step.Evidence.Topic,
+			Address:  step.Evidence.Address,
+			Slot:     step.Evidence.Slot,
+			LogIndex: step.Evidence.LogIndex,
+		}
+	}
+	return view
+}
+
+func optionalBig(v *big.Int) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func bigString(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
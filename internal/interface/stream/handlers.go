@@ -0,0 +1,181 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/usecase"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	keepAliveInterval = 15 * time.Second
+	writeTimeout      = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type Handler struct {
+	Hub           *Hub
+	DefaultPolicy SlowPolicy
+}
+
+func (h Handler) SSE(w http.ResponseWriter, r *http.Request) {
+	match, policy, err := h.parseQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sub := h.Hub.Subscribe(match, policy)
+	defer h.Hub.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	controller := http.NewResponseController(w)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.Done():
+			return
+		case <-keepAlive.C:
+			controller.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event := <-sub.Events():
+			payload, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("encode stream event: %v", err)
+				continue
+			}
+			controller.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if _, err := fmt.Fprintf(w, "event: tx\ndata: %s\n\n", payload); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h Handler) WebSocket(w http.ResponseWriter, r *http.Request) {
+	match, policy, err := h.parseQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sub := h.Hub.Subscribe(match, policy)
+	defer h.Hub.Unsubscribe(sub)
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-sub.Done():
+			msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+			if sub.TooSlow() {
+				msg = websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "client too slow")
+			}
+			conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeTimeout))
+			return
+		case <-keepAlive.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		case event := <-sub.Events():
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h Handler) parseQuery(r *http.Request) (usecase.TxMatch, SlowPolicy, error) {
+	q := r.URL.Query()
+	var match usecase.TxMatch
+	for _, t := range splitList(q.Get("type")) {
+		match.Types = append(match.Types, domain.ClassificationType(strings.ToUpper(t)))
+	}
+	match.Addresses = splitList(q.Get("address"))
+	match.Labels = splitList(q.Get("label"))
+	if raw := q.Get("flash-loan"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return usecase.TxMatch{}, "", fmt.Errorf("invalid flash-loan %q, expected true or false", raw)
+		}
+		match.FlashLoan = v
+	}
+	if raw := q.Get("min-value"); raw != "" {
+		v, ok := new(big.Int).SetString(raw, 10)
+		if !ok || v.Sign() < 0 {
+			return usecase.TxMatch{}, "", fmt.Errorf("invalid min-value %q, expected wei", raw)
+		}
+		match.MinValue = v
+	}
+
+	policy := h.DefaultPolicy
+	if raw := q.Get("slow"); raw != "" {
+		policy = SlowPolicy(raw)
+	}
+	if policy == "" {
+		policy = SlowDisconnect
+	}
+	if policy != SlowDrop && policy != SlowDisconnect {
+		return usecase.TxMatch{}, "", fmt.Errorf("invalid slow policy %q", policy)
+	}
+	return match, policy, nil
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ethClassify/internal/domain"
+
+	"github.com/gorilla/websocket"
+)
+
+func newStreamServer(t *testing.T, hub *Hub) *httptest.Server {
+	t.Helper()
+	handler := Handler{Hub: hub}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stream/sse", handler.SSE)
+	mux.HandleFunc("GET /stream/ws", handler.WebSocket)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// waitSubscribers waits until the handler under test has subscribed.
+func waitSubscribers(t *testing.T, hub *Hub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		hub.mu.Lock()
+		count := len(hub.subscribers)
+		hub.mu.Unlock()
+		if count == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("hub never reached %d subscribers", n)
+}
+
+func TestSSEFramesMatchingEvents(t *testing.T) {
+	hub := NewHub(4)
+	server := newStreamServer(t, hub)
+
+	resp, err := http.Get(server.URL + "/stream/sse?type=transfer")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || ct != "text/event-stream" {
+		t.Fatalf("response = %d %s", resp.StatusCode, ct)
+	}
+	waitSubscribers(t, hub, 1)
+
+	if err := hub.Write(context.Background(), testResult(7, domain.ClassificationDexSwap, domain.ClassificationTransfer)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// Only the transfer matches; it arrives as one "tx" event whose data
+	// line is the JSON event, terminated by a blank line.
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v (got %q)", err, lines)
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	if lines[0] != "event: tx" || !strings.HasPrefix(lines[1], "data: ") || lines[2] != "" {
+		t.Fatalf("event frame = %q", lines)
+	}
+	var event Event
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if event.BlockNumber != "7" || event.BlockHash != "0x7" || event.Tx.Type != string(domain.ClassificationTransfer) {
+		t.Errorf("event = %+v", event)
+	}
+}
+
+func TestStreamRejectsInvalidQueries(t *testing.T) {
+	server := newStreamServer(t, NewHub(1))
+	for _, query := range []string{"slow=block", "flash-loan=maybe", "min-value=-1"} {
+		for _, path := range []string{"/stream/sse?", "/stream/ws?"} {
+			resp, err := http.Get(server.URL + path + query)
+			if err != nil {
+				t.Fatalf("GET %s%s: %v", path, query, err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("GET %s%s = %d, want 400", path, query, resp.StatusCode)
+			}
+		}
+	}
+}
+
+func dialStream(t *testing.T, server *httptest.Server, hub *Hub, query string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/stream/ws?" + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	waitSubscribers(t, hub, 1)
+	return conn
+}
+
+func TestWebSocketSendsEventsAsJSONMessages(t *testing.T) {
+	hub := NewHub(4)
+	server := newStreamServer(t, hub)
+	conn := dialStream(t, server, hub, "type=dex_swap")
+
+	if err := hub.Write(context.Background(), testResult(9, domain.ClassificationTransfer, domain.ClassificationDexSwap)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	kind, payload, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatalf("decode %s: %v", payload, err)
+	}
+	if kind != websocket.TextMessage || event.BlockNumber != "9" || event.Tx.Type != string(domain.ClassificationDexSwap) {
+		t.Errorf("message = %d %+v", kind, event)
+	}
+}
+
+func TestWebSocketCloseCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		end  func(hub *Hub)
+		want int
+	}{
+		{
+			name: "slow client",
+			end: func(hub *Hub) {
+				hub.mu.Lock()
+				defer hub.mu.Unlock()
+				for sub := range hub.subscribers {
+					hub.disconnectSlow(sub)
+				}
+			},
+			want: websocket.ClosePolicyViolation,
+		},
+		{name: "shutdown", end: (*Hub).Close, want: websocket.CloseGoingAway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub(1)
+			server := newStreamServer(t, hub)
+			conn := dialStream(t, server, hub, "")
+
+			tt.end(hub)
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			_, _, err := conn.ReadMessage()
+			if !websocket.IsCloseError(err, tt.want) {
+				t.Fatalf("read = %v, want close %d", err, tt.want)
+			}
+		})
+	}
+}
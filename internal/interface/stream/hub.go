@@ -0,0 +1,139 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/interface/jsonview"
+	"ethClassify/internal/usecase"
+)
+
+type SlowPolicy string
+
+const (
+	SlowDrop       SlowPolicy = "drop"
+	SlowDisconnect SlowPolicy = "disconnect"
+)
+
+type Event struct {
+	BlockNumber string      `json:"blockNumber"`
+	BlockHash   string      `json:"blockHash"`
+	Tx          jsonview.Tx `json:"tx"`
+}
+
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[*Subscriber]struct{}
+	buffer      int
+}
+
+type Subscriber struct {
+	events  chan Event
+	done    chan struct{}
+	match   usecase.TxMatch
+	policy  SlowPolicy
+	dropped atomic.Uint64
+	slow    atomic.Bool
+	once    sync.Once
+}
+
+func NewHub(buffer int) *Hub {
+	if buffer < 1 {
+		buffer = 1
+	}
+	return &Hub{
+		subscribers: make(map[*Subscriber]struct{}),
+		buffer:      buffer,
+	}
+}
+
+func (h *Hub) Subscribe(match usecase.TxMatch, policy SlowPolicy) *Subscriber {
+	sub := &Subscriber{
+		events: make(chan Event, h.buffer),
+		done:   make(chan struct{}),
+		match:  match,
+		policy: policy,
+	}
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+func (h *Hub) Unsubscribe(sub *Subscriber) {
+	h.mu.Lock()
+	delete(h.subscribers, sub)
+	h.mu.Unlock()
+	sub.close()
+}
+
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		delete(h.subscribers, sub)
+		sub.close()
+	}
+}
+
+func (h *Hub) Write(ctx context.Context, result domain.BlockResult) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, res := range result.Results {
+		var event *Event
+		for sub := range h.subscribers {
+			if !sub.match.Matches(res) {
+				continue
+			}
+			if event == nil {
+				event = &Event{
+					BlockNumber: result.Block.Number.String(),
+					BlockHash:   result.Block.Hash,
+					Tx:          jsonview.NewTx(res),
+				}
+			}
+			select {
+			case sub.events <- *event:
+			default:
+				if sub.policy == SlowDisconnect {
+					h.disconnectSlow(sub)
+					continue
+				}
+				sub.dropped.Add(1)
+			}
+		}
+	}
+	return nil
+}
+
+// disconnectSlow drops a subscriber whose buffer is full; h.mu must be held.
+func (h *Hub) disconnectSlow(sub *Subscriber) {
+	delete(h.subscribers, sub)
+	sub.slow.Store(true)
+	sub.close()
+}
+
+func (s *Subscriber) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscriber) TooSlow() bool {
+	return s.slow.Load()
+}
+
+func (s *Subscriber) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+func (s *Subscriber) close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+var _ domain.ResultSink = (*Hub)(nil)
@@ -0,0 +1,107 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/usecase"
+)
+
+func testResult(number int64, types ...domain.ClassificationType) domain.BlockResult {
+	result := domain.BlockResult{Block: domain.Block{Number: big.NewInt(number), Hash: fmt.Sprintf("0x%x", number)}}
+	for i, t := range types {
+		result.Results = append(result.Results, domain.TxResult{
+			Tx:   domain.Tx{Hash: fmt.Sprintf("0x%064x", number*100+int64(i)), Index: uint(i), Value: big.NewInt(0)},
+			Type: t,
+		})
+	}
+	return result
+}
+
+func TestHubDropsEventsForSlowDropSubscribers(t *testing.T) {
+	hub := NewHub(2)
+	sub := hub.Subscribe(usecase.TxMatch{}, SlowDrop)
+
+	// Nobody reads: two events fill the buffer and the other three are dropped.
+	if err := hub.Write(context.Background(), testResult(1,
+		domain.ClassificationTransfer, domain.ClassificationTransfer, domain.ClassificationTransfer,
+		domain.ClassificationTransfer, domain.ClassificationTransfer,
+	)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if sub.Dropped() != 3 {
+		t.Errorf("dropped = %d, want 3", sub.Dropped())
+	}
+	select {
+	case <-sub.Done():
+		t.Fatal("drop subscriber was disconnected")
+	default:
+	}
+
+	// The buffered events are the oldest ones, and the subscriber keeps
+	// receiving once it catches up.
+	first := <-sub.Events()
+	<-sub.Events()
+	if first.Tx.Hash != fmt.Sprintf("0x%064x", 100) {
+		t.Errorf("first event = %s, want the block's first tx", first.Tx.Hash)
+	}
+	if err := hub.Write(context.Background(), testResult(2, domain.ClassificationTransfer)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if event := <-sub.Events(); event.BlockNumber != "2" {
+		t.Errorf("event after catching up from block %s, want 2", event.BlockNumber)
+	}
+}
+
+func TestHubDisconnectsSlowDisconnectSubscribers(t *testing.T) {
+	hub := NewHub(1)
+	slow := hub.Subscribe(usecase.TxMatch{}, SlowDisconnect)
+	other := hub.Subscribe(usecase.TxMatch{Types: []domain.ClassificationType{domain.ClassificationDexSwap}}, SlowDisconnect)
+
+	if err := hub.Write(context.Background(), testResult(1, domain.ClassificationTransfer, domain.ClassificationTransfer)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	select {
+	case <-slow.Done():
+	default:
+		t.Fatal("slow subscriber still connected after its buffer overflowed")
+	}
+	if !slow.TooSlow() || slow.Dropped() != 0 {
+		t.Errorf("TooSlow = %v, dropped = %d; want disconnected without drops", slow.TooSlow(), slow.Dropped())
+	}
+
+	// The filtered subscriber saw nothing it matched and stays connected;
+	// the disconnected one gets nothing more.
+	if err := hub.Write(context.Background(), testResult(2, domain.ClassificationDexSwap)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if event := <-other.Events(); event.BlockNumber != "2" {
+		t.Errorf("filtered subscriber got block %s, want 2", event.BlockNumber)
+	}
+	if len(slow.Events()) != 1 {
+		t.Errorf("slow subscriber buffer = %d events, want the 1 before the overflow", len(slow.Events()))
+	}
+	if other.TooSlow() {
+		t.Error("filtered subscriber marked too slow")
+	}
+}
+
+func TestHubCloseEndsSubscriptions(t *testing.T) {
+	hub := NewHub(1)
+	sub := hub.Subscribe(usecase.TxMatch{}, SlowDisconnect)
+	hub.Close()
+
+	select {
+	case <-sub.Done():
+	default:
+		t.Fatal("subscriber still open after hub close")
+	}
+	if sub.TooSlow() {
+		t.Error("subscriber closed by shutdown marked too slow")
+	}
+	// Unsubscribing after the hub closed the subscriber is harmless.
+	hub.Unsubscribe(sub)
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ethClassify/internal/domain"
+)
+
+type AlertRule struct {
+	Name     string
+	Match    TxMatch
+	Webhooks []string
+}
+
+type Alerting struct {
+	Rules  []AlertRule
+	Outbox domain.AlertOutbox
+}
+
+func (uc Alerting) Write(ctx context.Context, result domain.BlockResult) error {
+	if uc.Outbox == nil {
+		return fmt.Errorf("alert outbox is required")
+	}
+	for _, res := range result.Results {
+		for _, rule := range uc.Rules {
+			if !rule.Match.Matches(res) {
+				continue
+			}
+			for _, webhook := range rule.Webhooks {
+				_, err := uc.Outbox.Enqueue(ctx, domain.AlertEvent{
+					Rule:        rule.Name,
+					Webhook:     webhook,
+					BlockNumber: result.Block.Number.Uint64(),
+					BlockHash:   result.Block.Hash,
+					Result:      res,
+				})
+				if err != nil {
+					return fmt.Errorf("enqueue alert %s for tx %s: %w", rule.Name, res.Tx.Hash, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type AlertDispatcher struct {
+	Outbox       domain.AlertOutbox
+	Notifier     domain.AlertNotifier
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	OnError      func(error)
+}
+
+func (uc AlertDispatcher) Execute(ctx context.Context) error {
+	if uc.Outbox == nil || uc.Notifier == nil {
+		return fmt.Errorf("alert outbox and notifier are required")
+	}
+	interval := uc.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.dispatchDue(ctx); err != nil {
+			uc.report(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc AlertDispatcher) dispatchDue(ctx context.Context) error {
+	batch := uc.BatchSize
+	if batch <= 0 {
+		batch = 50
+	}
+	due, err := uc.Outbox.Due(ctx, time.Now(), batch)
+	if err != nil {
+		return err
+	}
+	for _, pending := range due {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		notifyErr := uc.Notifier.Notify(ctx, pending.Event)
+		if notifyErr == nil {
+			if err := uc.Outbox.MarkDelivered(ctx, pending.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		attempts := pending.Attempts + 1
+		dead := uc.MaxAttempts > 0 && attempts >= uc.MaxAttempts
+		next := time.Now().Add(uc.backoff(attempts))
+		if err := uc.Outbox.MarkFailed(ctx, pending.ID, attempts, next, notifyErr.Error(), dead); err != nil {
+			return err
+		}
+		uc.report(fmt.Errorf("deliver alert %s for tx %s to %s (attempt %d): %w",
+			pending.Event.Rule, pending.Event.Result.Tx.Hash, pending.Event.Webhook, attempts, notifyErr))
+	}
+	return nil
+}
+
+func (uc AlertDispatcher) backoff(attempts int) time.Duration {
+	base := uc.BaseBackoff
+	if base <= 0 {
+		base = 2 * time.Second
+	}
+	limit := uc.MaxBackoff
+	if limit <= 0 {
+		limit = 10 * time.Minute
+	}
+	delay := base
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	if delay > limit {
+		delay = limit
+	}
+	return delay
+}
+
+func (uc AlertDispatcher) report(err error) {
+	if uc.OnError != nil && err != nil {
+		uc.OnError(err)
+	}
+}
+
+var _ domain.ResultSink = Alerting{}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"ethClassify/internal/domain"
+)
+
+type Backfill struct {
+	Classify         ClassifyBlock
+	Sink             domain.ResultSink
+	Checkpoint       domain.CheckpointStore
+	BlockWorkers     int
+	ProgressInterval time.Duration
+	OnProgress       func(BackfillProgress)
+}
+
+type BackfillProgress struct {
+	LastBlock uint64
+	Done      uint64
+	Remaining uint64
+	Elapsed   time.Duration
+	Rate      float64
+	ETA       time.Duration
+}
+
+type blockOutcome struct {
+	result domain.BlockResult
+	err    error
+}
+
+func (uc Backfill) Execute(ctx context.Context, from, to uint64) error {
+	if err := uc.Classify.validate(); err != nil {
+		return err
+	}
+	if uc.Sink == nil {
+		return fmt.Errorf("result sink is required")
+	}
+	if from > to {
+		return fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+
+	start := from
+	if uc.Checkpoint != nil {
+		last, ok, err := uc.Checkpoint.Load()
+		if err != nil {
+			return err
+		}
+		if ok && last >= from {
+			start = last + 1
+		}
+	}
+	if start > to {
+		return nil
+	}
+
+	workers := uc.BlockWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pending := make(chan chan blockOutcome, workers)
+	go func() {
+		defer close(pending)
+		for n := start; n <= to; n++ {
+			out := make(chan blockOutcome, 1)
+			select {
+			case pending <- out:
+			case <-ctx.Done():
+				return
+			}
+			go func(number uint64) {
+				result, err := uc.Classify.ExecuteNumber(ctx, new(big.Int).SetUint64(number))
+				out <- blockOutcome{result: result, err: err}
+			}(n)
+		}
+	}()
+
+	total := to - start + 1
+	began := time.Now()
+	lastReport := began
+	var done uint64
+	for out := range pending {
+		outcome := <-out
+		if outcome.err != nil {
+			return outcome.err
+		}
+		if err := uc.Sink.Write(ctx, outcome.result); err != nil {
+			return err
+		}
+		number := outcome.result.Block.Number.Uint64()
+		if uc.Checkpoint != nil {
+			if err := uc.Checkpoint.Save(number); err != nil {
+				return err
+			}
+		}
+		done++
+
+		now := time.Now()
+		if uc.OnProgress != nil && (done == total || now.Sub(lastReport) >= uc.ProgressInterval) {
+			lastReport = now
+			uc.OnProgress(newBackfillProgress(number, done, total, now.Sub(began)))
+		}
+	}
+
+	return ctx.Err()
+}
+
+func newBackfillProgress(last, done, total uint64, elapsed time.Duration) BackfillProgress {
+	progress := BackfillProgress{
+		LastBlock: last,
+		Done:      done,
+		Remaining: total - done,
+		Elapsed:   elapsed,
+	}
+	if elapsed > 0 {
+		progress.Rate = float64(done) / elapsed.Seconds()
+	}
+	if progress.Rate > 0 {
+		progress.ETA = time.Duration(float64(progress.Remaining) / progress.Rate * float64(time.Second))
+	}
+	return progress
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"ethClassify/internal/domain"
+)
+
+type fakeChain struct {
+	head uint64
+}
+
+func (c *fakeChain) HeadNumber(context.Context) (uint64, error) { return c.head, nil }
+
+func (c *fakeChain) LatestBlock(ctx context.Context) (domain.Block, error) {
+	return c.BlockByNumber(ctx, new(big.Int).SetUint64(c.head))
+}
+
+func (c *fakeChain) BlockByNumber(_ context.Context, number *big.Int) (domain.Block, error) {
+	return domain.Block{Number: new(big.Int).Set(number), Hash: fmt.Sprintf("0x%x", number)}, nil
+}
+
+type staticClassifier struct{}
+
+func (staticClassifier) Classify(_ context.Context, tx domain.Tx) (domain.TxResult, bool, error) {
+	return domain.TxResult{Tx: tx, Type: domain.ClassificationTransfer}, true, nil
+}
+
+// slowChain serves later blocks faster, so concurrent workers finish out of order.
+type slowChain struct {
+	fakeChain
+	to uint64
+}
+
+func (c *slowChain) BlockByNumber(ctx context.Context, number *big.Int) (domain.Block, error) {
+	time.Sleep(time.Duration(c.to-number.Uint64()) * 2 * time.Millisecond)
+	return c.fakeChain.BlockByNumber(ctx, number)
+}
+
+type memCheckpoint struct {
+	last  uint64
+	saved bool
+}
+
+func (c *memCheckpoint) Load() (uint64, bool, error) { return c.last, c.saved, nil }
+
+func (c *memCheckpoint) Save(block uint64) error {
+	c.last, c.saved = block, true
+	return nil
+}
+
+// failingSink fails on block failAt and records the blocks before it.
+type failingSink struct {
+	failAt  uint64
+	written []uint64
+}
+
+func (s *failingSink) Write(_ context.Context, result domain.BlockResult) error {
+	number := result.Block.Number.Uint64()
+	if number == s.failAt {
+		return errors.New("disk full")
+	}
+	s.written = append(s.written, number)
+	return nil
+}
+
+func newTestBackfill(sink domain.ResultSink, checkpoint domain.CheckpointStore) Backfill {
+	return Backfill{
+		Classify:     ClassifyBlock{Reader: &slowChain{to: 20}, Pipeline: Pipeline{Classifiers: []domain.TxClassifier{staticClassifier{}}}},
+		Sink:         sink,
+		Checkpoint:   checkpoint,
+		BlockWorkers: 4,
+	}
+}
+
+func blockRange(from, to uint64) []uint64 {
+	var out []uint64
+	for n := from; n <= to; n++ {
+		out = append(out, n)
+	}
+	return out
+}
+
+func TestBackfillWritesInOrder(t *testing.T) {
+	sink := &failingSink{}
+	var progress []uint64
+	uc := newTestBackfill(sink, nil)
+	uc.OnProgress = func(p BackfillProgress) { progress = append(progress, p.LastBlock) }
+	if err := uc.Execute(context.Background(), 1, 20); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if want := blockRange(1, 20); !slices.Equal(sink.written, want) {
+		t.Fatalf("wrote %v, want %v", sink.written, want)
+	}
+	if len(progress) == 0 || progress[len(progress)-1] != 20 {
+		t.Fatalf("progress %v does not end at the last block", progress)
+	}
+}
+
+func TestBackfillStopsOnErrorAndResumes(t *testing.T) {
+	checkpoint := &memCheckpoint{}
+	first := &failingSink{failAt: 8}
+	if err := newTestBackfill(first, checkpoint).Execute(context.Background(), 1, 20); err == nil {
+		t.Fatal("Execute succeeded despite a sink error")
+	}
+	if want := blockRange(1, 7); !slices.Equal(first.written, want) {
+		t.Fatalf("first run wrote %v, want %v", first.written, want)
+	}
+	if !checkpoint.saved || checkpoint.last != 7 {
+		t.Fatalf("checkpoint = %d, %v; want 7", checkpoint.last, checkpoint.saved)
+	}
+
+	second := &failingSink{}
+	if err := newTestBackfill(second, checkpoint).Execute(context.Background(), 1, 20); err != nil {
+		t.Fatalf("resumed Execute: %v", err)
+	}
+	if want := blockRange(8, 20); !slices.Equal(second.written, want) {
+		t.Fatalf("resumed run wrote %v, want %v", second.written, want)
+	}
+	if checkpoint.last != 20 {
+		t.Fatalf("checkpoint after resume = %d, want 20", checkpoint.last)
+	}
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"ethClassify/internal/domain"
+)
+
+type CachedClassifyBlock struct {
+	Classify  ClassifyBlock
+	Finality  domain.FinalityReader
+	Cache     domain.BlockResultCache
+	finalized *atomic.Uint64
+}
+
+func NewCachedClassifyBlock(classify ClassifyBlock, finality domain.FinalityReader, cache domain.BlockResultCache) CachedClassifyBlock {
+	return CachedClassifyBlock{
+		Classify:  classify,
+		Finality:  finality,
+		Cache:     cache,
+		finalized: new(atomic.Uint64),
+	}
+}
+
+func (uc CachedClassifyBlock) Latest(ctx context.Context) (domain.BlockResult, error) {
+	return uc.Classify.Execute(ctx)
+}
+
+func (uc CachedClassifyBlock) ExecuteNumber(ctx context.Context, number uint64) (domain.BlockResult, error) {
+	if uc.Cache == nil || uc.Finality == nil || uc.finalized == nil {
+		return uc.Classify.ExecuteNumber(ctx, new(big.Int).SetUint64(number))
+	}
+	if result, ok := uc.Cache.Get(number); ok {
+		return result, nil
+	}
+
+	// Finality is checked before the fetch: a block final by then cannot be
+	// reorged away while it is read, so the cached result is the canonical
+	// one. A failed check only costs the cache, not the request.
+	final, finalityErr := uc.isFinalized(ctx, number)
+	result, err := uc.Classify.ExecuteNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return domain.BlockResult{}, err
+	}
+	if finalityErr == nil && final {
+		uc.Cache.Add(number, result)
+	}
+	return result, nil
+}
+
+func (uc CachedClassifyBlock) isFinalized(ctx context.Context, number uint64) (bool, error) {
+	if number <= uc.finalized.Load() {
+		return true, nil
+	}
+	latest, err := uc.Finality.FinalizedNumber(ctx)
+	if err != nil {
+		return false, fmt.Errorf("check finality of block %d: %w", number, err)
+	}
+	for {
+		current := uc.finalized.Load()
+		if latest <= current || uc.finalized.CompareAndSwap(current, latest) {
+			break
+		}
+	}
+	return number <= latest, nil
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+// countingChain counts block reads, each of which sees the current fork.
+type countingChain struct {
+	fakeChain
+	fork  string
+	reads int
+}
+
+func (c *countingChain) BlockByNumber(_ context.Context, number *big.Int) (domain.Block, error) {
+	c.reads++
+	return domain.Block{Number: new(big.Int).Set(number), Hash: c.fork + number.String()}, nil
+}
+
+// stepFinality reports the next finalized number on each call, repeating the last.
+type stepFinality struct {
+	numbers []uint64
+	err     error
+}
+
+func (f *stepFinality) FinalizedNumber(context.Context) (uint64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	number := f.numbers[0]
+	if len(f.numbers) > 1 {
+		f.numbers = f.numbers[1:]
+	}
+	return number, nil
+}
+
+type mapCache map[uint64]domain.BlockResult
+
+func (c mapCache) Get(number uint64) (domain.BlockResult, bool) {
+	result, ok := c[number]
+	return result, ok
+}
+
+func (c mapCache) Add(number uint64, result domain.BlockResult) { c[number] = result }
+
+func newTestCachedClassify(chain *countingChain, finality domain.FinalityReader, cache mapCache) CachedClassifyBlock {
+	classify := ClassifyBlock{Reader: chain, Pipeline: Pipeline{Classifiers: []domain.TxClassifier{staticClassifier{}}}}
+	return NewCachedClassifyBlock(classify, finality, cache)
+}
+
+func TestCachedClassifyBlockCachesOnlyBlocksFinalBeforeTheFetch(t *testing.T) {
+	chain := &countingChain{fork: "0xa"}
+	cache := mapCache{}
+	// Block 8 finalizes between the first request's finality check and
+	// the second one.
+	uc := newTestCachedClassify(chain, &stepFinality{numbers: []uint64{5, 10}}, cache)
+
+	first, err := uc.ExecuteNumber(context.Background(), 8)
+	if err != nil {
+		t.Fatalf("ExecuteNumber: %v", err)
+	}
+	if _, ok := cache[8]; ok {
+		t.Fatalf("block %s cached before it was final", first.Block.Hash)
+	}
+
+	// The fork read while the block was unfinalized lost; the block read
+	// once it is final is the one cached.
+	chain.fork = "0xb"
+	if _, err := uc.ExecuteNumber(context.Background(), 8); err != nil {
+		t.Fatalf("ExecuteNumber: %v", err)
+	}
+	cached, err := uc.ExecuteNumber(context.Background(), 8)
+	if err != nil {
+		t.Fatalf("ExecuteNumber: %v", err)
+	}
+	if cached.Block.Hash != "0xb8" || chain.reads != 2 {
+		t.Fatalf("cached block = %s after %d reads, want 0xb8 after 2", cached.Block.Hash, chain.reads)
+	}
+}
+
+func TestCachedClassifyBlockServesUncachedOnFinalityError(t *testing.T) {
+	chain := &countingChain{fork: "0xa"}
+	cache := mapCache{}
+	uc := newTestCachedClassify(chain, &stepFinality{err: errors.New("finalized tag unsupported")}, cache)
+
+	for range 2 {
+		result, err := uc.ExecuteNumber(context.Background(), 3)
+		if err != nil {
+			t.Fatalf("ExecuteNumber = %v, want the block despite the finality error", err)
+		}
+		if result.Block.Hash != "0xa3" {
+			t.Fatalf("block hash = %s, want 0xa3", result.Block.Hash)
+		}
+	}
+	if len(cache) != 0 || chain.reads != 2 {
+		t.Fatalf("cache = %v after %d reads, want nothing cached", cache, chain.reads)
+	}
+}
@@ -9,18 +9,18 @@ import (
 )
 
 type ClassifyBlock struct {
-	Reader       domain.BlockReader
-	Classifiers  []domain.TxClassifier
-	LogResolvers []domain.TxLogResolver
-	Labeler      domain.AddressLabeler
+	Reader   domain.BlockReader
+	Pipeline Pipeline
+	Workers  int
+	Watch    Watchlist
+	// Delegations, when set, tracks EIP-7702 delegations across the
+	// classified blocks, which must then come in chain order.
+	Delegations *DelegationTracker
 }
 
 func (uc ClassifyBlock) Execute(ctx context.Context) (domain.BlockResult, error) {
-	if uc.Reader == nil {
-		return domain.BlockResult{}, fmt.Errorf("block reader is required")
-	}
-	if len(uc.Classifiers) == 0 {
-		return domain.BlockResult{}, fmt.Errorf("at least one classifier is required")
+	if err := uc.validate(); err != nil {
+		return domain.BlockResult{}, err
 	}
 
 	block, err := uc.Reader.LatestBlock(ctx)
@@ -28,103 +28,100 @@ func (uc ClassifyBlock) Execute(ctx context.Context) (domain.BlockResult, error)
 		return domain.BlockResult{}, err
 	}
 
-	results := make([]domain.TxResult, 0, len(block.Transactions))
-	for _, tx := range block.Transactions {
-		labeled := uc.label(tx.To)
-		classified := false
-
-		for _, classifier := range uc.Classifiers {
-			result, ok, err := classifier.Classify(ctx, tx)
-			if err != nil {
-				return domain.BlockResult{}, err
-			}
-			if !ok {
-				continue
-			}
-			result.Tx = tx
-			result.ToLabel = labeled
-			classified = true
-			result, err = uc.resolveLogs(ctx, tx, result)
-			if err != nil {
-				return domain.BlockResult{}, err
-			}
-			results = append(results, result)
-			break
-		}
+	return uc.classify(ctx, block)
+}
 
-		if !classified {
-			result := domain.TxResult{
-				Tx:       tx,
-				Type:     domain.ClassificationUnknown,
-				Selector: selectorHex(tx.Data),
-				ToLabel:  labeled,
-			}
-			result, err = uc.resolveLogs(ctx, tx, result)
-			if err != nil {
-				return domain.BlockResult{}, err
-			}
-			results = append(results, result)
-		}
+func (uc ClassifyBlock) ExecuteNumber(ctx context.Context, number *big.Int) (domain.BlockResult, error) {
+	if err := uc.validate(); err != nil {
+		return domain.BlockResult{}, err
 	}
 
-	results = markSandwiches(results)
+	block, err := uc.Reader.BlockByNumber(ctx, number)
+	if err != nil {
+		return domain.BlockResult{}, err
+	}
 
-	return domain.BlockResult{
-		Block:   block,
-		Results: results,
-	}, nil
+	return uc.classify(ctx, block)
 }
 
-func (uc ClassifyBlock) label(addr *string) string {
-	if addr == nil || uc.Labeler == nil {
-		return ""
+func (uc ClassifyBlock) validate() error {
+	if uc.Reader == nil {
+		return fmt.Errorf("block reader is required")
 	}
-	return uc.Labeler.Label(*addr)
+	return uc.Pipeline.validate()
 }
 
-func (uc ClassifyBlock) resolveLogs(ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, error) {
-	if len(uc.LogResolvers) == 0 {
-		return current, nil
+func (uc ClassifyBlock) classify(ctx context.Context, block domain.Block) (domain.BlockResult, error) {
+	results, err := uc.Pipeline.ClassifyAll(ctx, block.Transactions, uc.Workers)
+	if err != nil {
+		return domain.BlockResult{}, err
 	}
 
-	if current.Type != domain.ClassificationContractCall && current.Type != domain.ClassificationUnknown {
-		return current, nil
-	}
+	results = markSandwiches(results, uc.Pipeline.Explain)
+	uc.Delegations.Track(block, results)
+	blobs := summarizeBlobs(block, results)
+	results = uc.Watch.Filter(results)
 
-	if len(tx.Logs) == 0 {
-		return current, nil
-	}
+	return domain.BlockResult{
+		Block:   block,
+		Results: results,
+		Blobs:   blobs,
+	}, nil
+}
 
-	resolved := current
-	for _, resolver := range uc.LogResolvers {
-		next, ok, err := resolver.Resolve(ctx, tx, resolved)
-		if err != nil {
-			return domain.TxResult{}, err
-		}
-		if !ok {
+// summarizeBlobs totals the blobs of the block's batch and blob txs per
+// rollup. Gas figures come from the header when the node reports them; the
+// blob gas price is the same for every tx of a block, so any receipt gives it.
+func summarizeBlobs(block domain.Block, results []domain.TxResult) *domain.BlobSummary {
+	summary := domain.BlobSummary{}
+	byRollup := map[string]int{}
+	for _, res := range results {
+		batch := res.Batch
+		if batch == nil || batch.Blobs == 0 {
 			continue
 		}
-		if next.ToLabel == "" {
-			next.ToLabel = resolved.ToLabel
+		summary.Txs++
+		summary.Blobs += batch.Blobs
+		summary.BlobGasUsed += batch.BlobGasUsed
+		if summary.BlobGasPrice == nil && batch.BlobGasPrice != nil {
+			summary.BlobGasPrice = batch.BlobGasPrice
+		}
+		if batch.BlobFee != nil {
+			if summary.BlobFees == nil {
+				summary.BlobFees = new(big.Int)
+			}
+			summary.BlobFees.Add(summary.BlobFees, batch.BlobFee)
+		}
+
+		i, ok := byRollup[batch.Rollup]
+		if !ok {
+			i = len(summary.Rollups)
+			byRollup[batch.Rollup] = i
+			summary.Rollups = append(summary.Rollups, domain.RollupBlobUsage{Rollup: batch.Rollup})
 		}
-		if next.Tx.Hash == "" {
-			next.Tx = tx
+		usage := &summary.Rollups[i]
+		usage.Txs++
+		usage.Blobs += batch.Blobs
+		if batch.BlobFee != nil {
+			if usage.BlobFee == nil {
+				usage.BlobFee = new(big.Int)
+			}
+			usage.BlobFee.Add(usage.BlobFee, batch.BlobFee)
 		}
-		resolved = next
-		break
 	}
-
-	return resolved, nil
-}
-
-func selectorHex(data []byte) string {
-	if len(data) < 4 {
-		return ""
+	if summary.Txs == 0 {
+		return nil
+	}
+	if block.BlobGasUsed != nil {
+		summary.BlobGasUsed = *block.BlobGasUsed
 	}
-	return fmt.Sprintf("%x", data[:4])
+	if block.ExcessBlobGas != nil {
+		summary.ExcessBlobGas = *block.ExcessBlobGas
+	}
+	return &summary
 }
 
-func markSandwiches(results []domain.TxResult) []domain.TxResult {
+func markSandwiches(results []domain.TxResult, explain bool) []domain.TxResult {
 	if len(results) < 3 {
 		return results
 	}
@@ -165,6 +162,18 @@ func markSandwiches(results []domain.TxResult) []domain.TxResult {
 
 		results[i].Type = domain.ClassificationSandwichSuspect
 		results[i].Details = fmt.Sprintf("Possible sandwich: frontrun %s / backrun %s attacker %s", pre.Tx.Hash, post.Tx.Hash, preFlow.sender)
+		if explain {
+			results[i].Trace = append(results[i].Trace, domain.TraceStep{
+				Stage:   stageBlock,
+				Name:    "markSandwiches",
+				Outcome: domain.TraceMatched,
+				Reason:  fmt.Sprintf("frontrun %s, backrun %s", pre.Tx.Hash, post.Tx.Hash),
+				Evidence: &domain.Evidence{
+					Rule:    "same pair and attacker around victim swap",
+					Address: victimFlow.pair,
+				},
+			})
+		}
 	}
 
 	return results
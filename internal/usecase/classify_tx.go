@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"ethClassify/internal/domain"
+)
+
+type ClassifyTx struct {
+	Reader   domain.TxReader
+	Pipeline Pipeline
+	Watch    Watchlist
+}
+
+func (uc ClassifyTx) Execute(ctx context.Context, hash string) (domain.TxResult, error) {
+	if uc.Reader == nil {
+		return domain.TxResult{}, fmt.Errorf("tx reader is required")
+	}
+	if err := uc.Pipeline.validate(); err != nil {
+		return domain.TxResult{}, err
+	}
+
+	tx, err := uc.Reader.TransactionByHash(ctx, hash)
+	if err != nil {
+		return domain.TxResult{}, err
+	}
+
+	result, err := uc.Pipeline.Classify(ctx, tx)
+	if err != nil {
+		return domain.TxResult{}, err
+	}
+	result.Watch = uc.Watch.Match(tx)
+	return result, nil
+}
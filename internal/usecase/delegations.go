@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"ethClassify/internal/domain"
+)
+
+// delegationReorgDepth is how many blocks back a reorg can restore an
+// earlier delegation; older history is pruned.
+const delegationReorgDepth = 64
+
+// DelegationTracker remembers which EOAs delegated their code in SET_CODE
+// txs seen so far, so later txs sent by or to them can be marked. Blocks
+// must reach it in chain order; a delegation to the zero address clears it.
+// Authorizations apply even when the tx reverts and are taken as signed:
+// one the EVM skipped for a stale nonce goes unnoticed. A block at or below
+// the last tracked one, or whose parent is not the last tracked block, is a
+// reorg: the delegations recorded by the replaced blocks are dropped.
+type DelegationTracker struct {
+	mu sync.Mutex
+	// history holds each account's delegations and revocations in chain
+	// order; the last one is current.
+	history  map[string][]delegationEntry
+	tracked  bool
+	head     uint64
+	headHash string
+}
+
+type delegationEntry struct {
+	account domain.DelegatedAccount
+	revoked bool
+}
+
+func NewDelegationTracker() *DelegationTracker {
+	return &DelegationTracker{history: map[string][]delegationEntry{}}
+}
+
+// Track walks the block's results in order, marking those whose sender or
+// recipient is delegated at that point and recording the delegations of
+// SET_CODE txs, reverted or not. A nil tracker does nothing.
+func (t *DelegationTracker) Track(block domain.Block, results []domain.TxResult) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	number := uint64(0)
+	if block.Number != nil {
+		number = block.Number.Uint64()
+	}
+	if t.tracked {
+		switch {
+		case number <= t.head:
+			t.rewind(number)
+		case number == t.head+1 && block.ParentHash != "" && !strings.EqualFold(block.ParentHash, t.headHash):
+			t.rewind(t.head)
+		}
+	}
+	t.tracked, t.head, t.headHash = true, number, block.Hash
+
+	for i := range results {
+		results[i].Delegated = t.delegated(results[i].Tx)
+		res := results[i]
+		if res.Type != domain.ClassificationSetCode {
+			continue
+		}
+		for _, d := range res.SetCode {
+			if d.Authority == "" || d.WrongChain {
+				continue
+			}
+			t.record(delegationEntry{
+				account: domain.DelegatedAccount{
+					Account:  d.Authority,
+					Delegate: d.Delegate,
+					Block:    number,
+					TxHash:   res.Tx.Hash,
+				},
+				revoked: d.Revoked,
+			})
+		}
+	}
+}
+
+// record appends entry to its account's history, pruning entries no reorg
+// can return to.
+func (t *DelegationTracker) record(entry delegationEntry) {
+	history := append(t.history[entry.account.Account], entry)
+	for len(history) > 1 && history[1].account.Block+delegationReorgDepth <= entry.account.Block {
+		history = history[1:]
+	}
+	t.history[entry.account.Account] = history
+}
+
+// rewind drops the entries recorded at or above block number.
+func (t *DelegationTracker) rewind(number uint64) {
+	for account, history := range t.history {
+		kept := len(history)
+		for kept > 0 && history[kept-1].account.Block >= number {
+			kept--
+		}
+		if kept == 0 {
+			delete(t.history, account)
+		} else {
+			t.history[account] = history[:kept]
+		}
+	}
+}
+
+// current returns the account's delegation, if it is delegated.
+func (t *DelegationTracker) current(account string) (domain.DelegatedAccount, bool) {
+	history := t.history[account]
+	if len(history) == 0 || history[len(history)-1].revoked {
+		return domain.DelegatedAccount{}, false
+	}
+	return history[len(history)-1].account, true
+}
+
+func (t *DelegationTracker) delegated(tx domain.Tx) []domain.DelegatedAccount {
+	var out []domain.DelegatedAccount
+	if account, ok := t.current(strings.ToLower(tx.From)); ok {
+		out = append(out, account)
+	}
+	if tx.To != nil && !strings.EqualFold(*tx.To, tx.From) {
+		if account, ok := t.current(strings.ToLower(*tx.To)); ok {
+			out = append(out, account)
+		}
+	}
+	return out
+}
+
+// Accounts returns the currently delegated EOAs ordered by address.
+func (t *DelegationTracker) Accounts() []domain.DelegatedAccount {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]domain.DelegatedAccount, 0, len(t.history))
+	for account := range t.history {
+		if delegated, ok := t.current(account); ok {
+			out = append(out, delegated)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Account < out[j].Account })
+	return out
+}
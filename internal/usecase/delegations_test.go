@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+const (
+	testEOA      = "0x1111111111111111111111111111111111111111"
+	testDelegate = "0x2222222222222222222222222222222222222222"
+	testOther    = "0x3333333333333333333333333333333333333333"
+)
+
+func trackedBlock(number int64, hash, parent string) domain.Block {
+	return domain.Block{Number: big.NewInt(number), Hash: hash, ParentHash: parent}
+}
+
+func setCodeResult(delegate string, revoked bool) domain.TxResult {
+	return domain.TxResult{
+		Tx:      domain.Tx{Hash: "0x" + delegate[2:6], From: testOther},
+		Type:    domain.ClassificationSetCode,
+		SetCode: []domain.CodeDelegation{{Authority: testEOA, Delegate: delegate, Revoked: revoked}},
+	}
+}
+
+func delegateOf(t *testing.T, tracker *DelegationTracker) string {
+	t.Helper()
+	accounts := tracker.Accounts()
+	switch len(accounts) {
+	case 0:
+		return ""
+	case 1:
+		return accounts[0].Delegate
+	}
+	t.Fatalf("tracked %v", accounts)
+	return ""
+}
+
+func TestDelegationTrackerMarksLaterTxs(t *testing.T) {
+	tracker := NewDelegationTracker()
+	to := testEOA
+	results := []domain.TxResult{
+		{Tx: domain.Tx{From: testEOA}},
+		setCodeResult(testDelegate, false),
+		{Tx: domain.Tx{From: testOther, To: &to}},
+	}
+	// Authorizations apply even when the SET_CODE tx reverts.
+	results[1].Tx.Receipt = &domain.Receipt{Success: false}
+	tracker.Track(trackedBlock(10, "0xa10", "0xa9"), results)
+
+	if len(results[0].Delegated) != 0 {
+		t.Errorf("tx before the delegation marked: %v", results[0].Delegated)
+	}
+	if len(results[2].Delegated) != 1 || results[2].Delegated[0].Block != 10 {
+		t.Errorf("tx after the delegation marked %v", results[2].Delegated)
+	}
+
+	tracker.Track(trackedBlock(11, "0xa11", "0xa10"), []domain.TxResult{setCodeResult(testDelegate, true)})
+	if got := delegateOf(t, tracker); got != "" {
+		t.Fatalf("revoked delegation still tracked: %s", got)
+	}
+}
+
+func TestDelegationTrackerDropsReorgedBlocks(t *testing.T) {
+	tests := []struct {
+		name  string
+		block domain.Block
+	}{
+		{"same height", trackedBlock(12, "0xb12", "0xa11")},
+		{"lower height", trackedBlock(11, "0xb11", "0xa10")},
+		{"parent mismatch", trackedBlock(13, "0xb13", "0xb12")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewDelegationTracker()
+			tracker.Track(trackedBlock(10, "0xa10", "0xa9"), []domain.TxResult{setCodeResult(testDelegate, false)})
+			tracker.Track(trackedBlock(11, "0xa11", "0xa10"), nil)
+			tracker.Track(trackedBlock(12, "0xa12", "0xa11"), []domain.TxResult{setCodeResult(testOther, false)})
+			if got := delegateOf(t, tracker); got != testOther {
+				t.Fatalf("delegate before reorg = %s", got)
+			}
+
+			// The replacement block carries no SET_CODE tx, so the earlier
+			// delegation is current again.
+			tracker.Track(tt.block, nil)
+			if got := delegateOf(t, tracker); got != testDelegate {
+				t.Fatalf("delegate after reorg = %s, want %s", got, testDelegate)
+			}
+		})
+	}
+}
+
+func TestDelegationTrackerPrunesOldHistory(t *testing.T) {
+	tracker := NewDelegationTracker()
+	parent := "0x0"
+	for n := int64(1); n <= 3*delegationReorgDepth; n++ {
+		hash := fmt.Sprintf("0x%x", n)
+		tracker.Track(trackedBlock(n, hash, parent), []domain.TxResult{setCodeResult(testDelegate, n%2 == 0)})
+		parent = hash
+	}
+	if got := len(tracker.history[testEOA]); got > delegationReorgDepth+1 {
+		t.Fatalf("kept %d entries for one account", got)
+	}
+}
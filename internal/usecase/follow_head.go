@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"ethClassify/internal/domain"
+)
+
+type FollowHead struct {
+	Classify     ClassifyBlock
+	Head         domain.HeadReader
+	Sinks        []domain.ResultSink
+	PollInterval time.Duration
+	OnError      func(error)
+}
+
+func (uc FollowHead) Execute(ctx context.Context) error {
+	if err := uc.Classify.validate(); err != nil {
+		return err
+	}
+	if uc.Head == nil {
+		return fmt.Errorf("head reader is required")
+	}
+	interval := uc.PollInterval
+	if interval <= 0 {
+		interval = 4 * time.Second
+	}
+
+	var next uint64
+	started := false
+	// pending is the last classified block whose sinks did not all accept
+	// it; it is retried, only on the failed sinks, before moving on.
+	var pending *domain.BlockResult
+	var unsent []domain.ResultSink
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		head, err := uc.Head.HeadNumber(ctx)
+		if err != nil {
+			uc.report(err)
+		} else {
+			if !started {
+				next = head
+				started = true
+			}
+			for next <= head && ctx.Err() == nil {
+				if pending == nil {
+					result, err := uc.Classify.ExecuteNumber(ctx, new(big.Int).SetUint64(next))
+					if err != nil {
+						uc.report(fmt.Errorf("classify block %d: %w", next, err))
+						break
+					}
+					pending, unsent = &result, uc.Sinks
+				}
+				unsent = uc.publish(ctx, *pending, unsent)
+				if len(unsent) > 0 {
+					break
+				}
+				pending = nil
+				next++
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish writes result to sinks and returns the ones that failed.
+func (uc FollowHead) publish(ctx context.Context, result domain.BlockResult, sinks []domain.ResultSink) []domain.ResultSink {
+	var failed []domain.ResultSink
+	for _, sink := range sinks {
+		if err := sink.Write(ctx, result); err != nil {
+			uc.report(fmt.Errorf("publish block %s: %w", result.Block.Number, err))
+			failed = append(failed, sink)
+		}
+	}
+	return failed
+}
+
+func (uc FollowHead) report(err error) {
+	if uc.OnError != nil && err != nil {
+		uc.OnError(err)
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ethClassify/internal/domain"
+)
+
+// flakySink fails the next failures writes and records the blocks it accepts.
+type flakySink struct {
+	failures int
+	written  []uint64
+	cancel   context.CancelFunc
+	stopAt   int
+}
+
+func (s *flakySink) Write(_ context.Context, result domain.BlockResult) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.written = append(s.written, result.Block.Number.Uint64())
+	if len(s.written) == s.stopAt {
+		s.cancel()
+	}
+	return nil
+}
+
+func TestFollowHeadRetriesBlockAfterSinkError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	chain := &fakeChain{head: 10}
+	failing := &flakySink{failures: 2, cancel: cancel, stopAt: 1}
+	healthy := &flakySink{cancel: func() {}}
+	var reported int
+	uc := FollowHead{
+		Classify:     ClassifyBlock{Reader: chain, Pipeline: Pipeline{Classifiers: []domain.TxClassifier{staticClassifier{}}}},
+		Head:         chain,
+		Sinks:        []domain.ResultSink{healthy, failing},
+		PollInterval: time.Millisecond,
+		OnError:      func(error) { reported++ },
+	}
+	if err := uc.Execute(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Execute = %v, want context.Canceled", err)
+	}
+
+	if len(failing.written) != 1 || failing.written[0] != 10 {
+		t.Fatalf("failing sink wrote %v, want block 10 after retries", failing.written)
+	}
+	// The healthy sink got the block once, not on every retry.
+	if len(healthy.written) != 1 || healthy.written[0] != 10 {
+		t.Fatalf("healthy sink wrote %v, want [10]", healthy.written)
+	}
+	if reported != 2 {
+		t.Fatalf("reported %d errors, want 2", reported)
+	}
+}
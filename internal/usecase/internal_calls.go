@@ -0,0 +1,44 @@
+package usecase
+
+import "ethClassify/internal/domain"
+
+// summarizeCalls collects value transfers, self-destructs and nested creates
+// from a call tree. Reverted frames and everything below them are ignored
+// because their effects were rolled back. The root frame is the tx itself,
+// so its value is not repeated as an internal transfer.
+func summarizeCalls(root *domain.Call) *domain.InternalActivity {
+	if root == nil || root.Error != "" {
+		return nil
+	}
+	activity := &domain.InternalActivity{}
+	var walk func(call domain.Call, depth int)
+	walk = func(call domain.Call, depth int) {
+		if call.Error != "" {
+			return
+		}
+		ref := domain.InternalCall{
+			Type:  call.Type,
+			From:  call.From,
+			To:    call.To,
+			Value: call.Value,
+			Depth: depth,
+		}
+		switch call.Type {
+		case domain.CallTypeSelfDestruct:
+			activity.SelfDestructs = append(activity.SelfDestructs, ref)
+		case domain.CallTypeCreate, domain.CallTypeCreate2:
+			if depth > 0 {
+				activity.Creates = append(activity.Creates, ref)
+			}
+		case domain.CallTypeCall, domain.CallTypeCallCode:
+			if depth > 0 && call.Value != nil && call.Value.Sign() > 0 {
+				activity.Transfers = append(activity.Transfers, ref)
+			}
+		}
+		for _, child := range call.Calls {
+			walk(child, depth+1)
+		}
+	}
+	walk(*root, 0)
+	return activity
+}
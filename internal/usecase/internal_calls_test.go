@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"math/big"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+func TestSummarizeCalls(t *testing.T) {
+	transfer := domain.Call{Type: domain.CallTypeCall, From: "0xa", To: "0xb", Value: big.NewInt(1)}
+
+	if got := summarizeCalls(nil); got != nil {
+		t.Fatalf("no trace = %+v, want nil", got)
+	}
+	reverted := &domain.Call{Type: domain.CallTypeCall, Error: "execution reverted", Calls: []domain.Call{transfer}}
+	if got := summarizeCalls(reverted); got != nil {
+		t.Fatalf("reverted tx = %+v, want nil", got)
+	}
+
+	root := &domain.Call{Type: domain.CallTypeCall, Value: big.NewInt(9), Calls: []domain.Call{
+		transfer,
+		{Type: domain.CallTypeCall, Error: "out of gas", Calls: []domain.Call{transfer}},
+		{Type: domain.CallTypeCreate, From: "0xa", To: "0xc", Calls: []domain.Call{
+			{Type: domain.CallTypeSelfDestruct, From: "0xc", To: "0xa"},
+		}},
+	}}
+	got := summarizeCalls(root)
+	if len(got.Transfers) != 1 || len(got.Creates) != 1 || len(got.SelfDestructs) != 1 {
+		t.Fatalf("summary = %+v", got)
+	}
+	if got.SelfDestructs[0].Depth != 2 {
+		t.Fatalf("self-destruct depth = %d, want 2", got.SelfDestructs[0].Depth)
+	}
+}
@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ethClassify/internal/domain"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	stageClassifier = "classifier"
+	stageResolver   = "resolver"
+	stageState      = "state"
+	stageUnwrap     = "unwrap"
+	stageEnricher   = "enricher"
+	stageBlock      = "block"
+)
+
+// maxUnwrapDepth bounds how deep inner calls are unwrapped, e.g. a batch
+// inside a user operation inside a bundle.
+const maxUnwrapDepth = 4
+
+// maxInnerCalls bounds the inner calls classified for one tx across all
+// depths, so nested batches cannot fan out exponentially.
+const maxInnerCalls = 256
+
+type Pipeline struct {
+	Classifiers    []domain.TxClassifier
+	LogResolvers   []domain.TxLogResolver
+	StateResolvers []domain.TxStateResolver
+	Unwrappers     []domain.TxUnwrapper
+	Enrichers      []domain.TxEnricher
+	Labeler        domain.AddressLabeler
+	Explain        bool
+}
+
+func (p Pipeline) validate() error {
+	if len(p.Classifiers) == 0 {
+		return fmt.Errorf("at least one classifier is required")
+	}
+	return nil
+}
+
+func (p Pipeline) Classify(ctx context.Context, tx domain.Tx) (domain.TxResult, error) {
+	budget := maxInnerCalls
+	return p.classify(ctx, tx, 0, &budget)
+}
+
+// classify runs the pipeline on tx; budget is the number of inner calls the
+// whole tx may still unwrap.
+func (p Pipeline) classify(ctx context.Context, tx domain.Tx, depth int, budget *int) (domain.TxResult, error) {
+	var trace []domain.TraceStep
+	labeled := p.label(tx.To)
+	fromLabel := p.label(&tx.From)
+
+	result := domain.TxResult{
+		Tx:        tx,
+		Type:      domain.ClassificationUnknown,
+		Selector:  selectorHex(tx.Data),
+		FromLabel: fromLabel,
+		ToLabel:   labeled,
+	}
+
+	matched := false
+	for _, classifier := range p.Classifiers {
+		name := stepName(classifier)
+		if matched {
+			trace = p.step(trace, stageClassifier, name, domain.TraceSkipped, "earlier classifier matched", nil)
+			continue
+		}
+		classified, ok, err := classifier.Classify(ctx, tx)
+		if err != nil {
+			return domain.TxResult{}, err
+		}
+		if !ok {
+			trace = p.step(trace, stageClassifier, name, domain.TraceNoMatch, "", nil)
+			continue
+		}
+		classified.Tx = tx
+		classified.FromLabel = fromLabel
+		classified.ToLabel = labeled
+		result = classified
+		matched = true
+		trace = p.step(trace, stageClassifier, name, domain.TraceMatched, fmt.Sprintf("type=%s", classified.Type), classified.Evidence)
+	}
+	if !matched {
+		trace = p.step(trace, stageClassifier, "fallback", domain.TraceMatched, fmt.Sprintf("no classifier matched, type=%s", result.Type), &domain.Evidence{
+			Rule:     "default classification",
+			Selector: result.Selector,
+		})
+	}
+
+	result, trace, err := p.resolveState(ctx, tx, result, trace)
+	if err != nil {
+		return domain.TxResult{}, err
+	}
+	result, trace, err = p.unwrap(ctx, tx, result, trace, depth, budget)
+	if err != nil {
+		return domain.TxResult{}, err
+	}
+	result, trace, err = p.resolveLogs(ctx, tx, result, trace)
+	if err != nil {
+		return domain.TxResult{}, err
+	}
+	result.Internal = summarizeCalls(tx.Calls)
+	result, trace, err = p.enrich(ctx, tx, result, trace)
+	if err != nil {
+		return domain.TxResult{}, err
+	}
+	result.Trace = trace
+	return result, nil
+}
+
+func (p Pipeline) ClassifyAll(ctx context.Context, txs []domain.Tx, workers int) ([]domain.TxResult, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]domain.TxResult, len(txs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(workers)
+	for i, tx := range txs {
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			result, err := p.Classify(gctx, tx)
+			if err != nil {
+				return err
+			}
+			results[i] = result
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (p Pipeline) label(addr *string) string {
+	if addr == nil || *addr == "" || p.Labeler == nil {
+		return ""
+	}
+	return p.Labeler.Label(*addr)
+}
+
+// resolveLogs also runs for plain ETH sends: a TRANSFER to a contract's
+// receive() can emit events, e.g. a bridge deposit. Resolvers that only
+// refine calls leave it alone.
+func (p Pipeline) resolveLogs(ctx context.Context, tx domain.Tx, current domain.TxResult, trace []domain.TraceStep) (domain.TxResult, []domain.TraceStep, error) {
+	skipReason := ""
+	switch {
+	case current.Type != domain.ClassificationContractCall && current.Type != domain.ClassificationUnknown && current.Type != domain.ClassificationTransfer:
+		skipReason = fmt.Sprintf("classification %s is not resolvable from logs", current.Type)
+	case len(tx.Logs) == 0:
+		skipReason = "tx has no logs"
+	}
+	return runResolvers(p, ctx, tx, current, trace, stageResolver, skipReason, p.LogResolvers,
+		func(r domain.TxLogResolver, ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+			return r.Resolve(ctx, tx, current)
+		})
+}
+
+func (p Pipeline) resolveState(ctx context.Context, tx domain.Tx, current domain.TxResult, trace []domain.TraceStep) (domain.TxResult, []domain.TraceStep, error) {
+	skipReason := ""
+	switch {
+	case current.Type != domain.ClassificationContractCall && current.Type != domain.ClassificationUnknown:
+		skipReason = fmt.Sprintf("classification %s is not resolvable from state", current.Type)
+	case len(tx.StateDiff) == 0:
+		skipReason = "tx has no state diff"
+	}
+	return runResolvers(p, ctx, tx, current, trace, stageState, skipReason, p.StateResolvers,
+		func(r domain.TxStateResolver, ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+			return r.ResolveState(ctx, tx, current)
+		})
+}
+
+// unwrap runs before the log resolvers so a wrapper is not reclassified by
+// the token events of its inner calls. The first matching unwrapper wins and
+// its inner calls go through the whole pipeline again. SET_CODE txs are
+// unwrapped too, since they carry the call made with the new code.
+func (p Pipeline) unwrap(ctx context.Context, tx domain.Tx, current domain.TxResult, trace []domain.TraceStep, depth int, budget *int) (domain.TxResult, []domain.TraceStep, error) {
+	skipReason := ""
+	switch {
+	case current.Type != domain.ClassificationContractCall && current.Type != domain.ClassificationUnknown && current.Type != domain.ClassificationSetCode:
+		skipReason = fmt.Sprintf("classification %s is not a wrapper call", current.Type)
+	case depth >= maxUnwrapDepth:
+		skipReason = fmt.Sprintf("max unwrap depth %d reached", maxUnwrapDepth)
+	case *budget <= 0:
+		skipReason = fmt.Sprintf("inner call budget of %d exhausted", maxInnerCalls)
+	}
+	return runResolvers(p, ctx, tx, current, trace, stageUnwrap, skipReason, p.Unwrappers,
+		func(u domain.TxUnwrapper, ctx context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+			next, calls, ok, err := u.Unwrap(ctx, tx, current)
+			if err != nil || !ok {
+				return current, false, err
+			}
+			if dropped := len(calls) - *budget; dropped > 0 {
+				calls = calls[:*budget]
+				next.Details = strings.TrimSpace(fmt.Sprintf("%s (%d inner calls over the budget of %d not classified)", next.Details, dropped, maxInnerCalls))
+			}
+			*budget -= len(calls)
+			next.Children = make([]domain.TxResult, 0, len(calls))
+			for _, call := range calls {
+				child, err := p.classify(ctx, call.Tx, depth+1, budget)
+				if err != nil {
+					return domain.TxResult{}, false, err
+				}
+				child.UserOp = call.UserOp
+				next.Children = append(next.Children, child)
+			}
+			return next, true, nil
+		})
+}
+
+// runResolvers applies resolvers in order until one matches, recording the
+// skipped, unmatched and matched steps of the given stage.
+func runResolvers[R any](p Pipeline, ctx context.Context, tx domain.Tx, current domain.TxResult, trace []domain.TraceStep, stage, skipReason string, resolvers []R,
+	resolve func(R, context.Context, domain.Tx, domain.TxResult) (domain.TxResult, bool, error)) (domain.TxResult, []domain.TraceStep, error) {
+	if len(resolvers) == 0 {
+		return current, trace, nil
+	}
+	if skipReason != "" {
+		for _, resolver := range resolvers {
+			trace = p.step(trace, stage, stepName(resolver), domain.TraceSkipped, skipReason, nil)
+		}
+		return current, trace, nil
+	}
+
+	resolved := current
+	matched := false
+	for _, resolver := range resolvers {
+		name := stepName(resolver)
+		if matched {
+			trace = p.step(trace, stage, name, domain.TraceSkipped, "earlier resolver matched", nil)
+			continue
+		}
+		next, ok, err := resolve(resolver, ctx, tx, resolved)
+		if err != nil {
+			return domain.TxResult{}, nil, err
+		}
+		if !ok {
+			trace = p.step(trace, stage, name, domain.TraceNoMatch, "", nil)
+			continue
+		}
+		if next.ToLabel == "" {
+			next.ToLabel = resolved.ToLabel
+		}
+		if next.FromLabel == "" {
+			next.FromLabel = resolved.FromLabel
+		}
+		if next.Tx.Hash == "" {
+			next.Tx = tx
+		}
+		resolved = next
+		matched = true
+		trace = p.step(trace, stage, name, domain.TraceMatched, fmt.Sprintf("type=%s", next.Type), next.Evidence)
+	}
+
+	return resolved, trace, nil
+}
+
+func (p Pipeline) enrich(ctx context.Context, tx domain.Tx, current domain.TxResult, trace []domain.TraceStep) (domain.TxResult, []domain.TraceStep, error) {
+	for _, enricher := range p.Enrichers {
+		name := stepName(enricher)
+		next, ok, err := enricher.Enrich(ctx, tx, current)
+		if err != nil {
+			return domain.TxResult{}, nil, err
+		}
+		if !ok {
+			trace = p.step(trace, stageEnricher, name, domain.TraceNoMatch, "", nil)
+			continue
+		}
+		current = next
+		trace = p.step(trace, stageEnricher, name, domain.TraceMatched, "", nil)
+	}
+	return current, trace, nil
+}
+
+func (p Pipeline) step(trace []domain.TraceStep, stage, name string, outcome domain.TraceOutcome, reason string, evidence *domain.Evidence) []domain.TraceStep {
+	if !p.Explain {
+		return trace
+	}
+	return append(trace, domain.TraceStep{
+		Stage:    stage,
+		Name:     name,
+		Outcome:  outcome,
+		Reason:   reason,
+		Evidence: evidence,
+	})
+}
+
+func stepName(v any) string {
+	return fmt.Sprintf("%T", v)
+}
+
+func selectorHex(data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+	return fmt.Sprintf("%x", data[:4])
+}
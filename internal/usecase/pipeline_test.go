@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+type typeClassifier domain.ClassificationType
+
+func (c typeClassifier) Classify(_ context.Context, tx domain.Tx) (domain.TxResult, bool, error) {
+	return domain.TxResult{Type: domain.ClassificationType(c)}, true, nil
+}
+
+// seenResolver records the classification it was offered and never matches.
+type seenResolver struct {
+	seen *[]domain.ClassificationType
+}
+
+func (r seenResolver) Resolve(_ context.Context, _ domain.Tx, current domain.TxResult) (domain.TxResult, bool, error) {
+	*r.seen = append(*r.seen, current.Type)
+	return current, false, nil
+}
+
+func TestResolveLogsRunsForTransfers(t *testing.T) {
+	withLogs := domain.Tx{Logs: []domain.Log{{Address: "0x1"}}}
+	tests := []struct {
+		name   string
+		class  domain.ClassificationType
+		tx     domain.Tx
+		offers bool
+	}{
+		{"transfer with logs", domain.ClassificationTransfer, withLogs, true},
+		{"transfer without logs", domain.ClassificationTransfer, domain.Tx{}, false},
+		{"contract call", domain.ClassificationContractCall, withLogs, true},
+		{"deploy", domain.ClassificationDeploy, withLogs, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen []domain.ClassificationType
+			p := Pipeline{
+				Classifiers:  []domain.TxClassifier{typeClassifier(tt.class)},
+				LogResolvers: []domain.TxLogResolver{seenResolver{seen: &seen}},
+			}
+			if _, err := p.Classify(context.Background(), tt.tx); err != nil {
+				t.Fatalf("Classify: %v", err)
+			}
+			if (len(seen) > 0) != tt.offers {
+				t.Fatalf("resolver offered %v, want offered=%v", seen, tt.offers)
+			}
+		})
+	}
+}
+
+// fanOutUnwrapper turns every call into n copies of itself.
+type fanOutUnwrapper int
+
+func (u fanOutUnwrapper) Unwrap(_ context.Context, tx domain.Tx, current domain.TxResult) (domain.TxResult, []domain.InnerCall, bool, error) {
+	calls := make([]domain.InnerCall, int(u))
+	for i := range calls {
+		calls[i] = domain.InnerCall{Tx: tx}
+	}
+	return current, calls, true, nil
+}
+
+func countChildren(result domain.TxResult) int {
+	n := len(result.Children)
+	for _, child := range result.Children {
+		n += countChildren(child)
+	}
+	return n
+}
+
+func TestUnwrapCapsInnerCallsPerTx(t *testing.T) {
+	p := Pipeline{
+		Classifiers: []domain.TxClassifier{typeClassifier(domain.ClassificationContractCall)},
+		Unwrappers:  []domain.TxUnwrapper{fanOutUnwrapper(10)},
+	}
+	// Uncapped, four levels of ten calls each would classify 11110 calls.
+	result, err := p.Classify(context.Background(), domain.Tx{})
+	if err != nil {
+		t.Fatalf("Classify: %v", err)
+	}
+	if got := countChildren(result); got != maxInnerCalls {
+		t.Fatalf("classified %d inner calls, want %d", got, maxInnerCalls)
+	}
+
+	wide := Pipeline{
+		Classifiers: p.Classifiers,
+		Unwrappers:  []domain.TxUnwrapper{fanOutUnwrapper(maxInnerCalls + 5)},
+	}
+	result, err = wide.Classify(context.Background(), domain.Tx{})
+	if err != nil {
+		t.Fatalf("Classify: %v", err)
+	}
+	if len(result.Children) != maxInnerCalls || !strings.Contains(result.Details, "5 inner calls over the budget") {
+		t.Fatalf("wide batch: %d children, details %q", len(result.Children), result.Details)
+	}
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"ethClassify/internal/domain"
+)
+
+type QueryResults struct {
+	Store domain.ResultStore
+}
+
+func (uc QueryResults) Execute(ctx context.Context, filter domain.TxFilter) ([]domain.BlockResult, error) {
+	if uc.Store == nil {
+		return nil, fmt.Errorf("result store is required")
+	}
+	if filter.FromBlock != nil && filter.ToBlock != nil && *filter.FromBlock > *filter.ToBlock {
+		return nil, fmt.Errorf("invalid block range %d-%d", *filter.FromBlock, *filter.ToBlock)
+	}
+	return uc.Store.Query(ctx, filter)
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"ethClassify/internal/domain"
+)
+
+// SimulateTx classifies a tx that has no receipt (pending, unsigned or
+// hypothetical) by executing it against a block and feeding the collected
+// logs to the pipeline's log resolvers.
+type SimulateTx struct {
+	Simulator domain.TxSimulator
+	Calls     domain.TxCallReader
+	Pipeline  Pipeline
+}
+
+func (uc SimulateTx) Execute(ctx context.Context, call domain.CallRequest, block *big.Int) (domain.SimulationResult, error) {
+	if uc.Simulator == nil {
+		return domain.SimulationResult{}, fmt.Errorf("simulator is required")
+	}
+	if err := uc.Pipeline.validate(); err != nil {
+		return domain.SimulationResult{}, err
+	}
+	if call.From == "" {
+		return domain.SimulationResult{}, fmt.Errorf("%w: sender is required", domain.ErrInvalidArgument)
+	}
+
+	sim, err := uc.Simulator.Simulate(ctx, call, block)
+	if err != nil {
+		return domain.SimulationResult{}, fmt.Errorf("simulate call: %w", err)
+	}
+	result, err := uc.Pipeline.Classify(ctx, sim.Tx)
+	if err != nil {
+		return domain.SimulationResult{}, err
+	}
+	return domain.SimulationResult{Simulation: sim, Result: result}, nil
+}
+
+// ExecuteHash replays a known (typically pending) tx.
+func (uc SimulateTx) ExecuteHash(ctx context.Context, hash string, block *big.Int) (domain.SimulationResult, error) {
+	if uc.Calls == nil {
+		return domain.SimulationResult{}, fmt.Errorf("tx call reader is required")
+	}
+	call, err := uc.Calls.TransactionCall(ctx, hash)
+	if err != nil {
+		return domain.SimulationResult{}, err
+	}
+	return uc.Execute(ctx, call, block)
+}
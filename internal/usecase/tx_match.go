@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"math/big"
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+type TxMatch struct {
+	Types         []domain.ClassificationType
+	Addresses     []string
+	FromAddresses []string
+	Labels        []string
+	FromLabels    []string
+	MinValue      *big.Int
+	// FlashLoan keeps only txs that took a flash loan, whatever their type.
+	FlashLoan bool
+}
+
+func (m TxMatch) Matches(res domain.TxResult) bool {
+	if len(m.Types) > 0 && !containsType(m.Types, res.Type) {
+		return false
+	}
+	if len(m.Labels) > 0 && !containsFold(m.Labels, res.ToLabel) {
+		return false
+	}
+	if len(m.FromLabels) > 0 && !containsFold(m.FromLabels, res.FromLabel) {
+		return false
+	}
+	if len(m.FromAddresses) > 0 && !containsFold(m.FromAddresses, res.Tx.From) {
+		return false
+	}
+	if m.MinValue != nil {
+		if res.Tx.Value == nil || res.Tx.Value.Cmp(m.MinValue) < 0 {
+			return false
+		}
+	}
+	if m.FlashLoan && len(res.Flash) == 0 {
+		return false
+	}
+	if len(m.Addresses) > 0 && !touchesAny(res, m.Addresses) {
+		return false
+	}
+	return true
+}
+
+func touchesAny(res domain.TxResult, addrs []string) bool {
+	for _, addr := range addrs {
+		if strings.EqualFold(res.Tx.From, addr) {
+			return true
+		}
+		if res.Tx.To != nil && strings.EqualFold(*res.Tx.To, addr) {
+			return true
+		}
+		if res.Swap != nil && (strings.EqualFold(res.Swap.Pair, addr) || strings.EqualFold(res.Swap.Sender, addr) || strings.EqualFold(res.Swap.Recipient, addr)) {
+			return true
+		}
+		if res.Bridge != nil && (strings.EqualFold(res.Bridge.From, addr) || strings.EqualFold(res.Bridge.To, addr)) {
+			return true
+		}
+		for _, a := range res.Lending {
+			if strings.EqualFold(a.User, addr) || strings.EqualFold(a.Caller, addr) {
+				return true
+			}
+		}
+		for _, f := range res.Flash {
+			if strings.EqualFold(f.Borrower, addr) {
+				return true
+			}
+		}
+		for _, d := range res.SetCode {
+			if strings.EqualFold(d.Authority, addr) || strings.EqualFold(d.Delegate, addr) {
+				return true
+			}
+		}
+		for _, log := range res.Tx.Logs {
+			if strings.EqualFold(log.Address, addr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsType(types []domain.ClassificationType, t domain.ClassificationType) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, v string) bool {
+	if v == "" {
+		return false
+	}
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"ethClassify/internal/domain"
+)
+
+// WatchMempool classifies pending txs as they arrive and reconciles each one
+// with the chain: included, replaced (same sender and nonce), or dropped after
+// DropAfter. Mined txs that were never seen pending are reported as private
+// inclusions. The pipeline only sees calldata, so it should be built from
+// TxClassifiers without log resolvers.
+type WatchMempool struct {
+	Source       domain.PendingSource
+	Blocks       domain.BlockReader
+	Head         domain.HeadReader
+	Pipeline     Pipeline
+	Sinks        []domain.MempoolSink
+	Watch        Watchlist
+	DropAfter    time.Duration
+	PollInterval time.Duration
+	Now          func() time.Time
+	OnError      func(error)
+}
+
+type pendingEntry struct {
+	result domain.TxResult
+	seenAt time.Time
+	key    string
+}
+
+type mempoolState struct {
+	byHash  map[string]*pendingEntry
+	byNonce map[string]string
+}
+
+func (uc WatchMempool) Execute(ctx context.Context) error {
+	if uc.Source == nil {
+		return fmt.Errorf("pending source is required")
+	}
+	if uc.Blocks == nil || uc.Head == nil {
+		return fmt.Errorf("block and head readers are required")
+	}
+	if err := uc.Pipeline.validate(); err != nil {
+		return err
+	}
+	interval := uc.PollInterval
+	if interval <= 0 {
+		interval = 4 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pending := make(chan domain.Tx, 1024)
+	subErr := make(chan error, 1)
+	go func() {
+		subErr <- uc.Source.SubscribePending(ctx, pending)
+	}()
+
+	state := mempoolState{
+		byHash:  make(map[string]*pendingEntry),
+		byNonce: make(map[string]string),
+	}
+	var next uint64
+	started := false
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-subErr:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("pending subscription ended: %w", err)
+		case tx := <-pending:
+			uc.observe(ctx, &state, tx)
+		case <-ticker.C:
+			head, err := uc.Head.HeadNumber(ctx)
+			if err != nil {
+				uc.report(err)
+				continue
+			}
+			if !started {
+				// Blocks mined before we subscribed would all look private.
+				next = head + 1
+				started = true
+			}
+			for next <= head && ctx.Err() == nil {
+				if err := uc.reconcile(ctx, &state, next); err != nil {
+					uc.report(fmt.Errorf("reconcile block %d: %w", next, err))
+					break
+				}
+				next++
+			}
+			uc.expire(ctx, &state)
+		}
+	}
+}
+
+func (uc WatchMempool) observe(ctx context.Context, state *mempoolState, tx domain.Tx) {
+	hash := strings.ToLower(tx.Hash)
+	if _, ok := state.byHash[hash]; ok {
+		return
+	}
+	result, ok := uc.classify(ctx, tx)
+	if !ok {
+		return
+	}
+	now := uc.now()
+	key := nonceKey(tx)
+	if previous, ok := state.byNonce[key]; ok {
+		uc.replace(ctx, state, previous, hash, now)
+	}
+	state.byHash[hash] = &pendingEntry{result: result, seenAt: now, key: key}
+	state.byNonce[key] = hash
+	uc.publish(ctx, domain.MempoolEvent{
+		Kind:   domain.MempoolPending,
+		Result: result,
+		SeenAt: now,
+	})
+}
+
+func (uc WatchMempool) reconcile(ctx context.Context, state *mempoolState, number uint64) error {
+	block, err := uc.Blocks.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return err
+	}
+	now := uc.now()
+	for _, tx := range block.Transactions {
+		hash := strings.ToLower(tx.Hash)
+		if previous, ok := state.byNonce[nonceKey(tx)]; ok {
+			uc.replace(ctx, state, previous, hash, now)
+		}
+		entry, seen := state.byHash[hash]
+		if !seen {
+			result, ok := uc.classify(ctx, tx)
+			if !ok {
+				continue
+			}
+			uc.publish(ctx, domain.MempoolEvent{
+				Kind:        domain.MempoolPrivateInclusion,
+				Result:      result,
+				BlockNumber: block.Number,
+				BlockHash:   block.Hash,
+			})
+			continue
+		}
+		uc.forget(state, hash)
+		uc.publish(ctx, domain.MempoolEvent{
+			Kind:          domain.MempoolIncluded,
+			Result:        entry.result,
+			SeenAt:        entry.seenAt,
+			TimeInMempool: now.Sub(entry.seenAt),
+			BlockNumber:   block.Number,
+			BlockHash:     block.Hash,
+		})
+	}
+	return nil
+}
+
+func (uc WatchMempool) replace(ctx context.Context, state *mempoolState, previous, replacement string, now time.Time) {
+	entry, ok := state.byHash[previous]
+	if !ok || previous == replacement {
+		return
+	}
+	uc.forget(state, previous)
+	uc.publish(ctx, domain.MempoolEvent{
+		Kind:          domain.MempoolReplaced,
+		Result:        entry.result,
+		SeenAt:        entry.seenAt,
+		TimeInMempool: now.Sub(entry.seenAt),
+		ReplacedBy:    replacement,
+	})
+}
+
+func (uc WatchMempool) expire(ctx context.Context, state *mempoolState) {
+	dropAfter := uc.DropAfter
+	if dropAfter <= 0 {
+		dropAfter = 15 * time.Minute
+	}
+	now := uc.now()
+	for hash, entry := range state.byHash {
+		if now.Sub(entry.seenAt) < dropAfter {
+			continue
+		}
+		uc.forget(state, hash)
+		uc.publish(ctx, domain.MempoolEvent{
+			Kind:          domain.MempoolDropped,
+			Result:        entry.result,
+			SeenAt:        entry.seenAt,
+			TimeInMempool: now.Sub(entry.seenAt),
+		})
+	}
+}
+
+func (uc WatchMempool) forget(state *mempoolState, hash string) {
+	entry, ok := state.byHash[hash]
+	if !ok {
+		return
+	}
+	delete(state.byHash, hash)
+	if state.byNonce[entry.key] == hash {
+		delete(state.byNonce, entry.key)
+	}
+}
+
+func (uc WatchMempool) classify(ctx context.Context, tx domain.Tx) (domain.TxResult, bool) {
+	watch := uc.Watch.Match(tx)
+	if !uc.Watch.Empty() && len(watch) == 0 {
+		return domain.TxResult{}, false
+	}
+	result, err := uc.Pipeline.Classify(ctx, tx)
+	if err != nil {
+		uc.report(fmt.Errorf("classify tx %s: %w", tx.Hash, err))
+		return domain.TxResult{}, false
+	}
+	result.Watch = watch
+	return result, true
+}
+
+func (uc WatchMempool) publish(ctx context.Context, event domain.MempoolEvent) {
+	for _, sink := range uc.Sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			uc.report(fmt.Errorf("publish %s %s: %w", event.Kind, event.Result.Tx.Hash, err))
+		}
+	}
+}
+
+func (uc WatchMempool) now() time.Time {
+	if uc.Now != nil {
+		return uc.Now()
+	}
+	return time.Now()
+}
+
+func (uc WatchMempool) report(err error) {
+	if uc.OnError != nil && err != nil {
+		uc.OnError(err)
+	}
+}
+
+func nonceKey(tx domain.Tx) string {
+	return fmt.Sprintf("%s/%d", strings.ToLower(tx.From), tx.Nonce)
+}
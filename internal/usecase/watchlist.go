@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"strings"
+
+	"ethClassify/internal/domain"
+)
+
+type Watchlist struct {
+	labels map[string]string
+}
+
+func NewWatchlist(entries map[string]string) Watchlist {
+	labels := make(map[string]string, len(entries))
+	for addr, label := range entries {
+		labels[strings.ToLower(addr)] = label
+	}
+	return Watchlist{labels: labels}
+}
+
+func (w Watchlist) Empty() bool {
+	return len(w.labels) == 0
+}
+
+func (w Watchlist) Match(tx domain.Tx) []domain.WatchMatch {
+	if w.Empty() {
+		return nil
+	}
+	var matches []domain.WatchMatch
+	seen := make(map[domain.WatchMatch]bool)
+	add := func(addr string, role domain.WatchRole) {
+		addr = strings.ToLower(addr)
+		label, ok := w.labels[addr]
+		if !ok {
+			return
+		}
+		m := domain.WatchMatch{Address: addr, Label: label, Role: role}
+		if seen[m] {
+			return
+		}
+		seen[m] = true
+		matches = append(matches, m)
+	}
+
+	add(tx.From, domain.WatchRoleFrom)
+	if tx.To != nil {
+		add(*tx.To, domain.WatchRoleTo)
+	}
+	for _, log := range tx.Logs {
+		add(log.Address, domain.WatchRoleLogEmitter)
+		for i := 1; i < len(log.Topics); i++ {
+			if addr, ok := topicAddress(log.Topics[i]); ok {
+				add(addr, domain.WatchRoleTopic)
+			}
+		}
+	}
+	return matches
+}
+
+func (w Watchlist) Filter(results []domain.TxResult) []domain.TxResult {
+	if w.Empty() {
+		return results
+	}
+	out := results[:0]
+	for _, res := range results {
+		res.Watch = w.Match(res.Tx)
+		if len(res.Watch) == 0 {
+			continue
+		}
+		out = append(out, res)
+	}
+	return out
+}
+
+func topicAddress(topic string) (string, bool) {
+	raw := strings.TrimPrefix(strings.ToLower(topic), "0x")
+	if len(raw) != 64 || strings.TrimLeft(raw[:24], "0") != "" {
+		return "", false
+	}
+	return "0x" + raw[24:], true
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"ethClassify/internal/domain"
+)
+
+func TestWatchlistMatchRoles(t *testing.T) {
+	const (
+		watched = "0x1111111111111111111111111111111111111111"
+		token   = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+		other   = "0x2222222222222222222222222222222222222222"
+	)
+	watch := NewWatchlist(map[string]string{
+		"0x1111111111111111111111111111111111111111": "Ops",
+		"0xA0b86991c6218b36c1D19D4a2e9Eb0cE3606eB48": "",
+	})
+	transferLog := domain.Log{
+		Address: token,
+		Topics: []string{
+			"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			"0x000000000000000000000000" + other[2:],
+			"0x000000000000000000000000" + watched[2:],
+		},
+	}
+	to := other
+
+	tests := []struct {
+		name string
+		tx   domain.Tx
+		want []domain.WatchMatch
+	}{
+		{
+			name: "from",
+			tx:   domain.Tx{From: watched, To: &to},
+			want: []domain.WatchMatch{{Address: watched, Label: "Ops", Role: domain.WatchRoleFrom}},
+		},
+		{
+			name: "log emitter and topic",
+			tx:   domain.Tx{From: other, To: &to, Logs: []domain.Log{transferLog}},
+			want: []domain.WatchMatch{
+				{Address: token, Role: domain.WatchRoleLogEmitter},
+				{Address: watched, Label: "Ops", Role: domain.WatchRoleTopic},
+			},
+		},
+		{
+			name: "logs not fetched",
+			tx:   domain.Tx{From: other, To: &to},
+		},
+		{
+			name: "non-address topic",
+			tx: domain.Tx{From: other, Logs: []domain.Log{{
+				Address: other,
+				Topics:  []string{transferLog.Topics[0], "0xffffffffffffffffffffffff" + watched[2:]},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := watch.Match(tt.tx); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Match = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,21 +8,38 @@ import (
 	"os"
 
 	"ethClassify/internal/domain"
+	"ethClassify/internal/infrastructure/chain"
 	"ethClassify/internal/infrastructure/classifier"
 	"ethClassify/internal/infrastructure/ethereum"
 	"ethClassify/internal/infrastructure/labeler"
+	"ethClassify/internal/infrastructure/watchlist"
 	"ethClassify/internal/interface/cli"
 	"ethClassify/internal/usecase"
 )
 
 func main() {
 	flag.Usage = func() {
-		fmt.Fprintln(flag.CommandLine.Output(), "ethClassify - clasificador de transacciones de Ethereum")
+		fmt.Fprintln(flag.CommandLine.Output(), "ethClassify - clasificador de transacciones de Ethereum y cadenas EVM")
 		fmt.Fprintf(flag.CommandLine.Output(), "Uso: %s -url <rpc-url> [opciones]\n", os.Args[0])
-		fmt.Fprintln(flag.CommandLine.Output(), "Clasifica el ultimo bloque de Ethereum mainnet usando el endpoint RPC indicado.")
+		fmt.Fprintln(flag.CommandLine.Output(), "Clasifica el ultimo bloque de la cadena del endpoint RPC indicado (Ethereum, Optimism, Polygon, Base, Arbitrum).")
+		fmt.Fprintln(flag.CommandLine.Output(), "\nSubcomandos:")
+		fmt.Fprintln(flag.CommandLine.Output(), "\tbackfill\tClasifica un rango historico de bloques con checkpoint y reanudacion")
+		fmt.Fprintln(flag.CommandLine.Output(), "\tquery\t\tConsulta transacciones clasificadas guardadas en SQLite")
+		fmt.Fprintln(flag.CommandLine.Output(), "\tserve\t\tExpone la clasificacion via HTTP")
+		fmt.Fprintln(flag.CommandLine.Output(), "\tmempool\t\tClasifica transacciones pendientes y sigue su inclusion")
+		fmt.Fprintln(flag.CommandLine.Output(), "\tsimulate\tSimula una transaccion pendiente o hipotetica y la clasifica")
 		fmt.Fprintln(flag.CommandLine.Output(), "\nOpciones:")
 		fmt.Fprintln(flag.CommandLine.Output(), "\t-url <rpc-url>\tRPC URL")
+		fmt.Fprintln(flag.CommandLine.Output(), "\t-chain <nombre>\tPerfil de cadena; si se omite se detecta con eth_chainId")
 		fmt.Fprintln(flag.CommandLine.Output(), "\t-with-logs\tUsa logs para clasificar transacciones ERC (hace más llamadas RPC!!)")
+		fmt.Fprintln(flag.CommandLine.Output(), "\t-tx <hash>\tClasifica una sola transaccion y muestra la traza de decisiones")
+		fmt.Fprintln(flag.CommandLine.Output(), "\t-trace-source <geth|parity>\tAgrega las llamadas internas de cada transaccion")
+		fmt.Fprintln(flag.CommandLine.Output(), "\t-state-diff\tAgrega el diff de estado (prestateTracer) y detecta upgrades de proxies y cambios de codigo")
+		fmt.Fprintln(flag.CommandLine.Output(), "\t-explain\tIncluye la traza de decisiones de cada transaccion")
+		fmt.Fprintln(flag.CommandLine.Output(), "\t-format\tFormato de salida: text o json")
+		fmt.Fprintln(flag.CommandLine.Output(), "\t-balances\tMuestra los cambios de balance por direccion en la salida de texto")
+		fmt.Fprintln(flag.CommandLine.Output(), "\t-workers <n>\tCantidad de workers para traer recibos y clasificar en paralelo")
+		fmt.Fprintln(flag.CommandLine.Output(), "\t-watch-addresses <archivo>\tMuestra solo transacciones que involucran direcciones vigiladas")
 		flag.PrintDefaults()
 		fmt.Fprintf(flag.CommandLine.Output(), "\nEjemplo:\n  %s -url https://mainnet.infura.io/v3/<project-id> -with-logs", os.Args[0])
 	}
@@ -32,54 +49,196 @@ func main() {
 		return
 	}
 
+	switch os.Args[1] {
+	case "backfill":
+		runBackfill(os.Args[2:])
+		return
+	case "query":
+		runQuery(os.Args[2:])
+		return
+	case "serve":
+		runServe(os.Args[2:])
+		return
+	case "mempool":
+		runMempool(os.Args[2:])
+		return
+	case "simulate":
+		runSimulate(os.Args[2:])
+		return
+	}
+
 	url := flag.String("url", "", "rpc url raw link")
+	chainName := flag.String("chain", "", chainFlagUsage)
 	withLogs := flag.Bool("with-logs", false, "use transaction receipts/logs for ERC-type classification (extra RPC calls)")
+	traceSource := flag.String("trace-source", "", "attach internal call traces: geth (debug_traceBlockByNumber callTracer) or parity (trace_block)")
+	stateDiff := flag.Bool("state-diff", false, "attach the prestateTracer state diff of every transaction (one debug_traceTransaction call per tx)")
+	txHash := flag.String("tx", "", "classify a single transaction by hash and print the explain trace")
+	explain := flag.Bool("explain", false, "record and print the classifier/resolver decisions for every transaction")
+	format := flag.String("format", "text", "output format: text or json")
+	balances := flag.Bool("balances", false, "print the per-address balance changes of every transaction (text output)")
+	workers := flag.Int("workers", 8, "number of concurrent workers for receipt fetching and classification")
+	watchPath := flag.String("watch-addresses", "", "file with one watched address per line (optional label after it); only matching txs are printed")
 	flag.Parse()
 	if *url == "" {
 		fmt.Fprintln(flag.CommandLine.Output(), "error: -url is required")
 		flag.Usage()
 		os.Exit(2)
 	}
+	if *workers < 1 {
+		fmt.Fprintln(flag.CommandLine.Output(), "error: -workers must be at least 1")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(flag.CommandLine.Output(), "error: unknown -format %q\n", *format)
+		flag.Usage()
+		os.Exit(2)
+	}
 
-	reader, err := ethereum.NewBlockReader(*url, *withLogs)
+	watchLogs(*watchPath, withLogs)
+	reader, err := ethereum.NewBlockReader(*url, ethereum.ReaderOptions{
+		WithLogs:      *withLogs,
+		Workers:       *workers,
+		TraceSource:   *traceSource,
+		WithStateDiff: *stateDiff,
+	})
 	if err != nil {
 		log.Fatalf("failed to create block reader: %v", err)
 	}
 
-	addrLabeler := labeler.NewStaticLabeler(map[string]string{
-		"0xdac17f958d2ee523a2206206994597c13d831ec7": "USDT",
-		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": "USDC",
-		"0x6b175474e89094c44da98b954eedeac495271d0f": "DAI",
-		"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2": "WETH",
-	})
+	watch := loadWatchlist(*watchPath)
+	ctx := context.Background()
+	profile := mustResolveChain(ctx, *chainName, reader)
+	text := cli.TextOptions{Balances: *balances, NativeSymbol: profile.NativeSymbol}
+
+	if *txHash != "" {
+		uc := usecase.ClassifyTx{
+			Reader:   reader,
+			Pipeline: newPipeline(pipelineOptions{Chain: profile, WithLogs: true, StateDiff: *stateDiff, Explain: true}),
+			Watch:    watch,
+		}
+		result, err := uc.Execute(ctx, *txHash)
+		if err != nil {
+			log.Fatalf("failed to classify tx: %v", err)
+		}
+		if *format == "json" {
+			if err := cli.PrintTxResultJSON(result); err != nil {
+				log.Fatalf("failed to print tx: %v", err)
+			}
+			return
+		}
+		cli.PrintTxResult(result, text)
+		return
+	}
 
+	uc := usecase.ClassifyBlock{
+		Reader:   reader,
+		Pipeline: newPipeline(pipelineOptions{Chain: profile, WithLogs: *withLogs, StateDiff: *stateDiff, Explain: *explain}),
+		Workers:  *workers,
+		Watch:    watch,
+	}
+
+	result, err := uc.Execute(ctx)
+	if err != nil {
+		log.Fatalf("failed to classify block: %v", err)
+	}
+
+	if *format == "json" {
+		if err := cli.PrintBlockResultJSON(result); err != nil {
+			log.Fatalf("failed to print block: %v", err)
+		}
+		return
+	}
+	cli.PrintBlockResult(result, text)
+}
+
+func loadWatchlist(path string) usecase.Watchlist {
+	if path == "" {
+		return usecase.Watchlist{}
+	}
+	entries, err := watchlist.LoadFile(path)
+	if err != nil {
+		log.Fatalf("failed to load watchlist: %v", err)
+	}
+	return usecase.NewWatchlist(entries)
+}
+
+// watchLogs turns on receipt fetching when a watchlist is given, since the
+// log-emitter and topic roles match against the tx logs.
+func watchLogs(watchPath string, withLogs *bool) {
+	if watchPath != "" && !*withLogs {
+		log.Printf("-watch-addresses: enabling -with-logs to match log emitters and topics")
+		*withLogs = true
+	}
+}
+
+func newLabeler(profile chain.Profile) domain.AddressLabeler {
+	return labeler.NewStaticLabeler(profile.AllLabels())
+}
+
+type pipelineOptions struct {
+	Chain     chain.Profile
+	WithLogs  bool
+	StateDiff bool
+	Explain   bool
+}
+
+func newPipeline(opts pipelineOptions) usecase.Pipeline {
 	classifiers := []domain.TxClassifier{
+		classifier.L2TxClassifier{},
+		classifier.RollupBatchClassifier{Rollups: opts.Chain.Rollups},
+		classifier.SetCodeClassifier{ChainID: opts.Chain.ChainID, Labels: opts.Chain.AllLabels()},
 		classifier.DeployClassifier{},
 		classifier.NativeTransferClassifier{},
+		classifier.SelfDestructClassifier{},
+		classifier.RouterSwapClassifier{},
 		classifier.ContractCallClassifier{},
 	}
 
 	var resolvers []domain.TxLogResolver
-	if *withLogs {
+	if opts.WithLogs {
 		resolvers = []domain.TxLogResolver{
-			classifier.DexSwapLogResolver{},
+			classifier.BridgeLogResolver{Bridges: opts.Chain.Bridges},
+			classifier.LendingLogResolver{Markets: opts.Chain.Lending},
+			classifier.DexSwapLogResolver{Dexes: opts.Chain.Dexes},
+			classifier.FlashLoanLogResolver{Markets: opts.Chain.Lending, Lenders: opts.Chain.FlashLenders},
 			classifier.ERC721LogResolver{},
 			classifier.ERC20LogResolver{},
 		}
 	}
 
-	uc := usecase.ClassifyBlock{
-		Reader:       reader,
-		Classifiers:  classifiers,
-		LogResolvers: resolvers,
-		Labeler:      addrLabeler,
+	var stateResolvers []domain.TxStateResolver
+	if opts.StateDiff {
+		stateResolvers = []domain.TxStateResolver{
+			classifier.ProxyUpgradeResolver{},
+			classifier.CodeChangeResolver{},
+		}
 	}
 
-	ctx := context.Background()
-	result, err := uc.Execute(ctx)
-	if err != nil {
-		log.Fatalf("failed to classify block: %v", err)
+	enrichers := []domain.TxEnricher{
+		classifier.BalanceDeltaEnricher{WrappedNative: opts.Chain.WrappedNative},
+		classifier.FlashLoanEnricher{Markets: opts.Chain.Lending, Lenders: opts.Chain.FlashLenders},
 	}
 
-	cli.PrintBlockResult(result)
+	return usecase.Pipeline{
+		Classifiers:    classifiers,
+		LogResolvers:   resolvers,
+		StateResolvers: stateResolvers,
+		Unwrappers:     newUnwrappers(),
+		Enrichers:      enrichers,
+		Labeler:        newLabeler(opts.Chain),
+		Explain:        opts.Explain,
+	}
+}
+
+// newUnwrappers lists the wrapper calls whose inner calls are classified on
+// their own; the order only matters for calls matching more than one.
+func newUnwrappers() []domain.TxUnwrapper {
+	return []domain.TxUnwrapper{
+		classifier.SetCodeUnwrapper{},
+		classifier.UserOpUnwrapper{},
+		classifier.SafeUnwrapper{},
+		classifier.SmartAccountUnwrapper{},
+		classifier.MulticallUnwrapper{},
+	}
 }
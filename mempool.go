@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/infrastructure/chain"
+	"ethClassify/internal/infrastructure/classifier"
+	"ethClassify/internal/infrastructure/ethereum"
+	"ethClassify/internal/interface/cli"
+	"ethClassify/internal/usecase"
+)
+
+func runMempool(args []string) {
+	fs := flag.NewFlagSet("mempool", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "ethClassify mempool - clasifica transacciones pendientes y sigue su inclusion")
+		fmt.Fprintf(fs.Output(), "Uso: %s mempool -url <ws-url> [opciones]\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Requiere un endpoint websocket o IPC con suscripcion newPendingTransactions.")
+		fmt.Fprintln(fs.Output(), "Eventos: PENDING, INCLUDED, REPLACED, DROPPED, PRIVATE_INCLUSION")
+		fmt.Fprintln(fs.Output(), "\nOpciones:")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nEjemplo:\n  %s mempool -url wss://mainnet.infura.io/ws/v3/<project-id> -format json\n", os.Args[0])
+	}
+
+	url := fs.String("url", "", "websocket or IPC rpc endpoint")
+	chainName := fs.String("chain", "", chainFlagUsage)
+	explain := fs.Bool("explain", false, "record the classifier decisions for every pending transaction")
+	format := fs.String("format", "text", "output format: text or json (one event per line)")
+	dropAfter := fs.Duration("drop-after", 15*time.Minute, "time without inclusion after which a pending tx is reported as dropped")
+	pollInterval := fs.Duration("poll-interval", 4*time.Second, "interval between head checks for inclusion")
+	watchPath := fs.String("watch-addresses", "", "file with one watched address per line; only matching txs are reported")
+	fs.Parse(args)
+
+	if *url == "" {
+		fmt.Fprintln(fs.Output(), "error: -url is required")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(fs.Output(), "error: unknown -format %q\n", *format)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	source, err := ethereum.NewPendingSubscriber(ctx, *url)
+	if err != nil {
+		log.Fatalf("failed to subscribe to pending transactions: %v", err)
+	}
+	defer source.Close()
+
+	reader, err := ethereum.NewBlockReader(*url, ethereum.ReaderOptions{Workers: 8})
+	if err != nil {
+		log.Fatalf("failed to create block reader: %v", err)
+	}
+
+	profile := mustResolveChain(ctx, *chainName, reader)
+
+	uc := usecase.WatchMempool{
+		Source:       source,
+		Blocks:       reader,
+		Head:         reader,
+		Pipeline:     newMempoolPipeline(profile, *explain),
+		Sinks:        []domain.MempoolSink{cli.Printer{Format: *format, Text: cli.TextOptions{NativeSymbol: profile.NativeSymbol}}},
+		Watch:        loadWatchlist(*watchPath),
+		DropAfter:    *dropAfter,
+		PollInterval: *pollInterval,
+		OnError: func(err error) {
+			log.Printf("mempool: %v", err)
+		},
+	}
+	if err := uc.Execute(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("mempool watch failed: %v", err)
+	}
+}
+
+// newMempoolPipeline only uses calldata classifiers: pending txs have no
+// receipt, so swaps are predicted from router calls instead of Swap events.
+func newMempoolPipeline(profile chain.Profile, explain bool) usecase.Pipeline {
+	return usecase.Pipeline{
+		Classifiers: []domain.TxClassifier{
+			classifier.RollupBatchClassifier{Rollups: profile.Rollups},
+			classifier.SetCodeClassifier{ChainID: profile.ChainID, Labels: profile.AllLabels()},
+			classifier.DeployClassifier{},
+			classifier.NativeTransferClassifier{},
+			classifier.RouterSwapClassifier{},
+			classifier.ContractCallClassifier{},
+		},
+		Unwrappers: newUnwrappers(),
+		Labeler:    newLabeler(profile),
+		Explain:    explain,
+	}
+}
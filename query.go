@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/infrastructure/chain"
+	"ethClassify/internal/infrastructure/storage/sqlite"
+	"ethClassify/internal/interface/cli"
+	"ethClassify/internal/usecase"
+)
+
+func runQuery(args []string) {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "ethClassify query - consulta transacciones clasificadas guardadas en SQLite")
+		fmt.Fprintf(fs.Output(), "Uso: %s query -db <archivo> [filtros]\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "\nOpciones:")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nEjemplo:\n  %s query -db ethclassify.db -type SANDWICH_SUSPECT -address <pool> -from-block 18000000\n", os.Args[0])
+	}
+
+	dbPath := fs.String("db", "", "sqlite database written by backfill -db")
+	classType := fs.String("type", "", "classification type, e.g. SANDWICH_SUSPECT")
+	address := fs.String("address", "", "address matching from, to, swap pair or token transfer party/token")
+	fromBlock := fs.Uint64("from-block", 0, "first block (inclusive)")
+	toBlock := fs.Uint64("to-block", 0, "last block (inclusive)")
+	limit := fs.Int("limit", 0, "maximum number of transactions (0 means no limit)")
+	format := fs.String("format", "text", "output format: text or json")
+	chainName := fs.String("chain", "", "chain profile the database was backfilled from (sets the native currency symbol)")
+	fs.Parse(args)
+
+	if *dbPath == "" {
+		fmt.Fprintln(fs.Output(), "error: -db is required")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(fs.Output(), "error: unknown -format %q\n", *format)
+		fs.Usage()
+		os.Exit(2)
+	}
+	var text cli.TextOptions
+	if *chainName != "" {
+		profile, ok := chain.ByName(*chainName)
+		if !ok {
+			fmt.Fprintf(fs.Output(), "error: unknown -chain %q\n", *chainName)
+			fs.Usage()
+			os.Exit(2)
+		}
+		text.NativeSymbol = profile.NativeSymbol
+	}
+
+	filter := domain.TxFilter{
+		Type:    domain.ClassificationType(strings.ToUpper(*classType)),
+		Address: *address,
+		Limit:   *limit,
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "from-block":
+			filter.FromBlock = fromBlock
+		case "to-block":
+			filter.ToBlock = toBlock
+		}
+	})
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx, *dbPath)
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
+	}
+	defer store.Close()
+
+	results, err := usecase.QueryResults{Store: store}.Execute(ctx, filter)
+	if err != nil {
+		log.Fatalf("failed to query results: %v", err)
+	}
+
+	printer := cli.Printer{Format: *format, Text: text}
+	for _, result := range results {
+		if err := printer.Write(ctx, result); err != nil {
+			log.Fatalf("failed to print results: %v", err)
+		}
+	}
+}
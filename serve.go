@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/infrastructure/alert"
+	"ethClassify/internal/infrastructure/cache"
+	"ethClassify/internal/infrastructure/ethereum"
+	"ethClassify/internal/interface/httpapi"
+	"ethClassify/internal/interface/stream"
+	"ethClassify/internal/usecase"
+)
+
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "ethClassify serve - expone la clasificacion via HTTP")
+		fmt.Fprintf(fs.Output(), "Uso: %s serve -url <rpc-url> [opciones]\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Endpoints: GET /blocks/latest, GET /blocks/{number}, GET /tx/{hash}, GET /healthz")
+		fmt.Fprintln(fs.Output(), "Con -follow: GET /stream/sse y GET /stream/ws (filtros: type, address, label, min-value, slow)")
+		fmt.Fprintln(fs.Output(), "Con -follow -track-delegations: GET /delegations")
+		fmt.Fprintln(fs.Output(), "\nOpciones:")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nEjemplo:\n  %s serve -url https://mainnet.infura.io/v3/<project-id> -with-logs -addr :8080\n", os.Args[0])
+	}
+
+	url := fs.String("url", "", "rpc url raw link")
+	chainName := fs.String("chain", "", chainFlagUsage)
+	addr := fs.String("addr", ":8080", "listen address")
+	withLogs := fs.Bool("with-logs", false, "use transaction receipts/logs for ERC-type classification (extra RPC calls)")
+	traceSource := fs.String("trace-source", "", "attach internal call traces: geth (debug_traceBlockByNumber callTracer) or parity (trace_block)")
+	stateDiff := fs.Bool("state-diff", false, "attach the prestateTracer state diff of every transaction (one debug_traceTransaction call per tx)")
+	explain := fs.Bool("explain", false, "include the classifier/resolver decisions in every response")
+	workers := fs.Int("workers", 8, "number of concurrent workers per block for receipt fetching and classification")
+	cacheSize := fs.Int("cache-size", 256, "number of finalized blocks kept in the LRU cache")
+	requestTimeout := fs.Duration("request-timeout", 30*time.Second, "maximum time spent classifying a single request")
+	shutdownTimeout := fs.Duration("shutdown-timeout", 15*time.Second, "maximum time to wait for in-flight requests on shutdown")
+	watchPath := fs.String("watch-addresses", "", "file with one watched address per line; only matching txs are returned and streamed")
+	follow := fs.Bool("follow", false, "follow the chain head and stream classified transactions over SSE and WebSocket")
+	pollInterval := fs.Duration("poll-interval", 4*time.Second, "interval between head checks when following")
+	trackDelegations := fs.Bool("track-delegations", false, "track EIP-7702 delegated EOAs across followed blocks and serve them on /delegations")
+	streamBuffer := fs.Int("stream-buffer", 256, "events buffered per stream subscriber before the slow-client policy applies")
+	slowClient := fs.String("slow-client", string(stream.SlowDisconnect), "default policy for slow stream clients: drop or disconnect")
+	alertsPath := fs.String("alerts", "", "alert rules and webhooks (JSON), evaluated on every followed block")
+	outboxPath := fs.String("alert-outbox", "alerts.db", "sqlite file keeping pending alert deliveries across restarts")
+	alertAttempts := fs.Int("alert-max-attempts", 8, "delivery attempts before an alert is given up")
+	fs.Parse(args)
+
+	if *url == "" {
+		fmt.Fprintln(fs.Output(), "error: -url is required")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *workers < 1 {
+		fmt.Fprintln(fs.Output(), "error: -workers must be at least 1")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *alertsPath != "" && !*follow {
+		fmt.Fprintln(fs.Output(), "error: -alerts requires -follow")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *trackDelegations && !*follow {
+		fmt.Fprintln(fs.Output(), "error: -track-delegations requires -follow")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if policy := stream.SlowPolicy(*slowClient); policy != stream.SlowDrop && policy != stream.SlowDisconnect {
+		fmt.Fprintf(fs.Output(), "error: unknown -slow-client %q\n", *slowClient)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	watchLogs(*watchPath, withLogs)
+	reader, err := ethereum.NewBlockReader(*url, ethereum.ReaderOptions{
+		WithLogs:      *withLogs,
+		Workers:       *workers,
+		TraceSource:   *traceSource,
+		WithStateDiff: *stateDiff,
+	})
+	if err != nil {
+		log.Fatalf("failed to create block reader: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	profile := mustResolveChain(ctx, *chainName, reader)
+
+	watch := loadWatchlist(*watchPath)
+	classify := usecase.ClassifyBlock{
+		Reader:   reader,
+		Pipeline: newPipeline(pipelineOptions{Chain: profile, WithLogs: *withLogs, StateDiff: *stateDiff, Explain: *explain}),
+		Workers:  *workers,
+		Watch:    watch,
+	}
+	handler := httpapi.Handler{
+		Blocks: usecase.NewCachedClassifyBlock(classify, reader, cache.NewBlockLRU(*cacheSize)),
+		Txs: usecase.ClassifyTx{
+			Reader:   reader,
+			Pipeline: newPipeline(pipelineOptions{Chain: profile, WithLogs: true, StateDiff: *stateDiff, Explain: *explain}),
+			Watch:    watch,
+		},
+		RequestTimeout: *requestTimeout,
+	}
+
+	var hub *stream.Hub
+	if *follow {
+		hub = stream.NewHub(*streamBuffer)
+		handler.Stream = &stream.Handler{Hub: hub, DefaultPolicy: stream.SlowPolicy(*slowClient)}
+		sinks := []domain.ResultSink{hub}
+
+		if *alertsPath != "" {
+			rules, webhooks, err := loadAlertConfig(*alertsPath)
+			if err != nil {
+				log.Fatalf("failed to load alerts: %v", err)
+			}
+			notifier, err := alert.NewWebhookNotifier(&http.Client{Timeout: 10 * time.Second}, webhooks, profile.NativeSymbol)
+			if err != nil {
+				log.Fatalf("failed to configure webhooks: %v", err)
+			}
+			outbox, err := alert.OpenOutbox(ctx, *outboxPath)
+			if err != nil {
+				log.Fatalf("failed to open alert outbox: %v", err)
+			}
+			defer outbox.Close()
+
+			sinks = append(sinks, usecase.Alerting{Rules: rules, Outbox: outbox})
+			dispatcher := usecase.AlertDispatcher{
+				Outbox:      outbox,
+				Notifier:    notifier,
+				MaxAttempts: *alertAttempts,
+				OnError: func(err error) {
+					log.Printf("alerts: %v", err)
+				},
+			}
+			go dispatcher.Execute(ctx)
+		}
+
+		followed := classify
+		if *trackDelegations {
+			followed.Delegations = usecase.NewDelegationTracker()
+			handler.Delegations = followed.Delegations
+		}
+		follower := usecase.FollowHead{
+			Classify:     followed,
+			Head:         reader,
+			Sinks:        sinks,
+			PollInterval: *pollInterval,
+			OnError: func(err error) {
+				log.Printf("follow: %v", err)
+			},
+		}
+		go follower.Execute(ctx)
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           handler.Routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      *requestTimeout + 10*time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("serving on %s", *addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("server failed: %v", err)
+		}
+	case <-ctx.Done():
+		log.Printf("shutting down")
+		if hub != nil {
+			hub.Close()
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Fatalf("failed to shut down: %v", err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"ethClassify/internal/domain"
+	"ethClassify/internal/infrastructure/ethereum"
+	"ethClassify/internal/interface/cli"
+	"ethClassify/internal/usecase"
+	"ethClassify/utils"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func runSimulate(args []string) {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "ethClassify simulate - simula una transaccion y la clasifica con los logs obtenidos")
+		fmt.Fprintf(fs.Output(), "Uso: %s simulate -url <rpc-url> (-tx <hash> | -from <addr> [-to <addr>] [-value <eth>] [-data <hex>]) [opciones]\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Usa debug_traceCall con callTracer; si el nodo no lo soporta, eth_call (sin logs).")
+		fmt.Fprintln(fs.Output(), "\nOpciones:")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nEjemplo:\n  %s simulate -url http://localhost:8545 -from 0xabc... -to 0x7a25... -value 0.5 -data 0x7ff36ab5...\n", os.Args[0])
+	}
+
+	url := fs.String("url", "", "rpc url raw link")
+	chainName := fs.String("chain", "", chainFlagUsage)
+	txHash := fs.String("tx", "", "replay a pending or mined tx by hash")
+	from := fs.String("from", "", "sender of the simulated call")
+	to := fs.String("to", "", "recipient of the simulated call (empty for a deployment)")
+	value := fs.String("value", "0", "value sent with the call, in the chain's native currency")
+	data := fs.String("data", "", "calldata as hex")
+	gas := fs.Uint64("gas", 0, "gas limit for the call (0 lets the node choose)")
+	block := fs.String("block", "latest", "block whose state the call runs against: a number or latest")
+	explain := fs.Bool("explain", true, "print the classifier/resolver decisions")
+	format := fs.String("format", "text", "output format: text or json")
+	fs.Parse(args)
+
+	if *url == "" {
+		fmt.Fprintln(fs.Output(), "error: -url is required")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if (*txHash == "") == (*from == "") {
+		fmt.Fprintln(fs.Output(), "error: exactly one of -tx or -from is required")
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(fs.Output(), "error: unknown -format %q\n", *format)
+		fs.Usage()
+		os.Exit(2)
+	}
+	var number *big.Int
+	if *block != "latest" {
+		n, ok := new(big.Int).SetString(*block, 10)
+		if !ok || n.Sign() < 0 {
+			fmt.Fprintf(fs.Output(), "error: invalid -block %q\n", *block)
+			fs.Usage()
+			os.Exit(2)
+		}
+		number = n
+	}
+
+	simulator, err := ethereum.NewSimulator(*url)
+	if err != nil {
+		log.Fatalf("failed to create simulator: %v", err)
+	}
+	defer simulator.Close()
+
+	ctx := context.Background()
+	profile := mustResolveChain(ctx, *chainName, simulator)
+	uc := usecase.SimulateTx{
+		Simulator: simulator,
+		Calls:     simulator,
+		Pipeline:  newPipeline(pipelineOptions{Chain: profile, WithLogs: true, Explain: *explain}),
+	}
+
+	var result domain.SimulationResult
+	if *txHash != "" {
+		result, err = uc.ExecuteHash(ctx, *txHash, number)
+	} else {
+		call, callErr := buildCall(*from, *to, *value, *data, *gas)
+		if callErr != nil {
+			fmt.Fprintf(fs.Output(), "error: %v\n", callErr)
+			fs.Usage()
+			os.Exit(2)
+		}
+		result, err = uc.Execute(ctx, call, number)
+	}
+	if err != nil {
+		log.Fatalf("failed to simulate tx: %v", err)
+	}
+
+	if *format == "json" {
+		if err := cli.PrintSimulationJSON(result); err != nil {
+			log.Fatalf("failed to print simulation: %v", err)
+		}
+		return
+	}
+	cli.PrintSimulation(result, cli.TextOptions{NativeSymbol: profile.NativeSymbol})
+}
+
+func buildCall(from, to, value, data string, gas uint64) (domain.CallRequest, error) {
+	wei, ok := utils.EtherToWei(value)
+	if !ok {
+		return domain.CallRequest{}, fmt.Errorf("invalid -value %q", value)
+	}
+	call := domain.CallRequest{From: from, Value: wei, Gas: gas}
+	if to != "" {
+		call.To = &to
+	}
+	if data != "" {
+		if !strings.HasPrefix(data, "0x") {
+			data = "0x" + data
+		}
+		decoded, err := hexutil.Decode(data)
+		if err != nil {
+			return domain.CallRequest{}, fmt.Errorf("invalid -data: %v", err)
+		}
+		call.Data = decoded
+	}
+	return call, nil
+}
@@ -7,3 +7,24 @@ func WeiToEtherString(wei *big.Int) string {
 	eth := new(big.Float).Quo(f, big.NewFloat(1e18))
 	return eth.Text('f', 18)
 }
+
+func EtherToWei(eth string) (*big.Int, bool) {
+	r, ok := new(big.Rat).SetString(eth)
+	if !ok || r.Sign() < 0 {
+		return nil, false
+	}
+	r.Mul(r, new(big.Rat).SetInt(big.NewInt(1e18)))
+	if !r.IsInt() {
+		return nil, false
+	}
+	return new(big.Int).Set(r.Num()), true
+}
+
+// WeiToNativeString formats wei in the chain's native currency, e.g.
+// "1.5 POL"; an empty symbol falls back to ETH.
+func WeiToNativeString(wei *big.Int, symbol string) string {
+	if symbol == "" {
+		symbol = "ETH"
+	}
+	return WeiToEtherString(wei) + " " + symbol
+}